@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/internal/cli/tui"
+	"github.com/spf13/cobra"
+)
+
+var checkAccessCmd = &cobra.Command{
+	Use:   "check-access [owner/repo...]",
+	Short: "Check that the token can read each repository, without cloning",
+	Long: `Dry-run that checks, for every given repository, whether the token can
+access it. Repositories can be passed as arguments, read from a file with one
+owner/repo per line, or taken from a selection saved with --selection-file.
+Each repository is reported as accessible, not-found or forbidden.`,
+	RunE: runCheckAccess,
+}
+
+func init() {
+	checkAccessCmd.Flags().StringP("file", "f", "", "file listing repositories (owner/repo per line, # for comments)")
+	checkAccessCmd.Flags().IntP("concurrency", "j", 5, "maximum number of concurrent checks")
+	rootCmd.AddCommand(checkAccessCmd)
+}
+
+// AccessResult is the access classification for a single repository
+type AccessResult struct {
+	Repository string            `json:"repository" yaml:"repository"`
+	Status     auth.AccessStatus `json:"status" yaml:"status"`
+	Error      string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// AccessReport summarizes the access check over a set of repositories
+type AccessReport struct {
+	Accessible int            `json:"accessible" yaml:"accessible"`
+	NotFound   int            `json:"not_found" yaml:"not_found"`
+	Forbidden  int            `json:"forbidden" yaml:"forbidden"`
+	Errors     int            `json:"errors" yaml:"errors"`
+	Results    []AccessResult `json:"results" yaml:"results"`
+}
+
+// newAccessReport aggregates results into a report, preserving their order
+func newAccessReport(results []AccessResult) *AccessReport {
+	report := &AccessReport{Results: results}
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			report.Errors++
+		case r.Status == auth.AccessGranted:
+			report.Accessible++
+		case r.Status == auth.AccessNotFound:
+			report.NotFound++
+		case r.Status == auth.AccessForbidden:
+			report.Forbidden++
+		}
+	}
+	return report
+}
+
+func runCheckAccess(cmd *cobra.Command, args []string) error {
+	names, err := accessTargets(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	ctx, _, client, err := setup(cmd)
+	if err != nil {
+		return err
+	}
+
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	results := make([]AccessResult, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = AccessResult{Repository: name}
+			owner, repo, ok := strings.Cut(name, "/")
+			if !ok || owner == "" || repo == "" {
+				results[i].Error = "expected owner/repo"
+				return
+			}
+			status, err := client.ClassifyRepositoryAccess(ctx, owner, repo)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Status = status
+		}(i, name)
+	}
+	wg.Wait()
+
+	report := newAccessReport(results)
+	if format, _ := cmd.Flags().GetString("output"); format != "" {
+		return writeOutput(os.Stdout, format, report)
+	}
+
+	for _, r := range report.Results {
+		if r.Error != "" {
+			fmt.Printf("  ! %s: %s\n", r.Repository, r.Error)
+			continue
+		}
+		fmt.Printf("  %s %s\n", r.Repository, r.Status)
+	}
+	fmt.Printf("Accessible: %d, not found: %d, forbidden: %d, errors: %d\n",
+		report.Accessible, report.NotFound, report.Forbidden, report.Errors)
+	return nil
+}
+
+// accessTargets collects the repositories to check from the arguments, --file and --selection-file
+func accessTargets(cmd *cobra.Command, args []string) ([]string, error) {
+	names := append([]string{}, args...)
+	if file, _ := cmd.Flags().GetString("file"); file != "" {
+		fromFile, err := readRepoList(file)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, fromFile...)
+	}
+	if path, _ := cmd.Flags().GetString("selection-file"); path != "" {
+		state, err := tui.LoadSelectionFile(path)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, state.Repos...)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no repositories given. Pass owner/repo arguments or use --file or --selection-file")
+	}
+	return names, nil
+}
+
+// readRepoList reads owner/repo names from a file, skipping blank lines and comments
+func readRepoList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository list: %w", err)
+	}
+	defer f.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	return names, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/spf13/pflag"
+)
+
+func TestNewAccessReport(t *testing.T) {
+	results := []AccessResult{
+		{Repository: "acme/app", Status: auth.AccessGranted},
+		{Repository: "acme/gone", Status: auth.AccessNotFound},
+		{Repository: "acme/web", Status: auth.AccessGranted},
+		{Repository: "acme/secret", Status: auth.AccessForbidden},
+		{Repository: "nonsense", Error: "expected owner/repo"},
+		{Repository: "acme/broken", Error: "error checking repository access: 500"},
+	}
+	report := newAccessReport(results)
+
+	if report.Accessible != 2 || report.NotFound != 1 || report.Forbidden != 1 || report.Errors != 2 {
+		t.Errorf("report counts = %d accessible, %d not found, %d forbidden, %d errors; want 2, 1, 1, 2",
+			report.Accessible, report.NotFound, report.Forbidden, report.Errors)
+	}
+	for i, r := range report.Results {
+		if r.Repository != results[i].Repository {
+			t.Errorf("result %d = %s, want %s in input order", i, r.Repository, results[i].Repository)
+		}
+	}
+}
+
+func TestReadRepoList(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"one per line", "acme/app\nacme/web\n", []string{"acme/app", "acme/web"}},
+		{"comments and blanks", "# services\nacme/app\n\n  acme/web  \n#acme/old\n", []string{"acme/app", "acme/web"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "repos.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readRepoList(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("readRepoList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := readRepoList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("readRepoList() of a missing file succeeded")
+	}
+}
+
+func TestAccessTargets(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(list, []byte("acme/web\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	selection := filepath.Join(dir, "selection.yaml")
+	if err := os.WriteFile(selection, []byte("organization: acme\nrepos:\n  - acme/api\n  - acme/worker\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		flags   []string
+		want    []string
+		wantErr bool
+	}{
+		{"arguments", []string{"acme/app"}, nil, []string{"acme/app"}, false},
+		{"file", nil, []string{"--file", list}, []string{"acme/web"}, false},
+		{"selection file", nil, []string{"--selection-file", selection}, []string{"acme/api", "acme/worker"}, false},
+		{"all sources", []string{"acme/app"}, []string{"--file", list, "--selection-file", selection}, []string{"acme/app", "acme/web", "acme/api", "acme/worker"}, false},
+		{"missing selection file", nil, []string{"--selection-file", filepath.Join(dir, "missing.json")}, nil, true},
+		{"nothing given", nil, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				checkAccessCmd.Flags().Visit(func(f *pflag.Flag) {
+					f.Value.Set(f.DefValue)
+					f.Changed = false
+				})
+			})
+			if err := checkAccessCmd.ParseFlags(tt.flags); err != nil {
+				t.Fatal(err)
+			}
+			got, err := accessTargets(checkAccessCmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("accessTargets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("accessTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clone a single repository without listing its organization",
+	Long: `Resolve a single repository with the GitHub API and clone it with the
+configured options, skipping the organization listing and the TUI.`,
+	RunE: runClone,
+}
+
+func init() {
+	cloneCmd.Flags().StringP("repo", "r", "", "repository to clone as owner/name")
+	cloneCmd.Flags().StringP("branch", "b", "", "branch to check out (default is the repository's default branch)")
+	cloneCmd.Flags().String("tag", "", "tag to check out instead of a branch, e.g. v1.2.0")
+	cloneCmd.MarkFlagsMutuallyExclusive("branch", "tag")
+	rootCmd.AddCommand(cloneCmd)
+}
+
+// parseRepoRef splits an owner/name repository reference
+func parseRepoRef(ref string) (string, string, error) {
+	owner, name, ok := strings.Cut(strings.TrimSpace(ref), "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository %q, expected owner/name", ref)
+	}
+	return owner, strings.TrimSuffix(name, ".git"), nil
+}
+
+func runClone(cmd *cobra.Command, args []string) error {
+	ref, _ := cmd.Flags().GetString("repo")
+	if ref == "" {
+		return fmt.Errorf("repository not provided. Use --repo owner/name")
+	}
+	owner, name, err := parseRepoRef(ref)
+	if err != nil {
+		return err
+	}
+
+	ctx, cfg, client, err := setup(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts, err := prepareOptions(cmd, cfg, client)
+	if err != nil {
+		return err
+	}
+	opts.Branch, _ = cmd.Flags().GetString("branch")
+	opts.Tag, _ = cmd.Flags().GetString("tag")
+	if err := git.ValidateOptions(opts); err != nil {
+		return err
+	}
+
+	repo, err := client.GetRepository(ctx, owner, name)
+	if err != nil {
+		return err
+	}
+
+	result, err := cloneHeadless(ctx, cmd, cfg, client, opts, []*gh.Repository{repo})
+	if err != nil {
+		return err
+	}
+	if err := finishRun(ctx, cmd, cfg, client, result); err != nil {
+		return err
+	}
+	if summary := result.Summary(); summary.Failed > 0 {
+		return fmt.Errorf("failed to clone %s", repo.GetFullName())
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+func TestParseRepoRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		owner   string
+		name    string
+		wantErr bool
+	}{
+		{"acme/app", "acme", "app", false},
+		{" acme/app.git ", "acme", "app", false},
+		{"acme", "", "", true},
+		{"acme/", "", "", true},
+		{"/app", "", "", true},
+		{"acme/app/extra", "", "", true},
+	}
+	for _, tt := range tests {
+		owner, name, err := parseRepoRef(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRepoRef(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if owner != tt.owner || name != tt.name {
+			t.Errorf("parseRepoRef(%q) = %q, %q, want %q, %q", tt.ref, owner, name, tt.owner, tt.name)
+		}
+	}
+}
+
+// newLocalRemote creates a bare repository with a main branch and the given
+// extra branches, returning its file:// URL
+func newLocalRemote(t *testing.T, branches ...string) string {
+	t.Helper()
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	work := filepath.Join(root, "work")
+	run(root, "init", "-q", "--bare", "-b", "main", remote)
+	run(root, "init", "-q", "-b", "main", work)
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "add", ".")
+	run(work, "commit", "-qm", "initial")
+	run(work, "push", "-q", remote, "main")
+	for _, branch := range branches {
+		run(work, "checkout", "-qb", branch, "main")
+		if err := os.WriteFile(filepath.Join(work, "README.md"), []byte(branch+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(work, "commit", "-qam", branch)
+		run(work, "push", "-q", remote, branch)
+	}
+	return "file://" + remote
+}
+
+func TestCloneHeadlessSingleRepository(t *testing.T) {
+	remote := newLocalRemote(t, "dev")
+
+	tests := []struct {
+		name       string
+		branch     string
+		wantBranch string
+	}{
+		{"default branch", "", "main"},
+		{"requested branch", "dev", "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := t.TempDir()
+			cmd := parseRootFlags(t, "--no-tui", "--quiet")
+			cfg := loadTestConfig(t, "clone:\n  output_dir: "+out+"\n  max_concurrent: 1\n")
+			opts := git.DefaultCloneOptions()
+			opts.MaxRetries = 0
+			opts.Branch = tt.branch
+
+			repo := &gh.Repository{
+				Name:          gh.String("app"),
+				FullName:      gh.String("acme/app"),
+				Owner:         &gh.User{Login: gh.String("acme")},
+				DefaultBranch: gh.String("main"),
+				CloneURL:      gh.String(remote),
+			}
+			result, err := cloneHeadless(t.Context(), cmd, cfg, nil, opts, []*gh.Repository{repo})
+			if err != nil {
+				t.Fatalf("cloneHeadless() error = %v", err)
+			}
+
+			summary := result.Summary()
+			if summary.Total != 1 || summary.Succeeded != 1 {
+				t.Fatalf("summary = %d total, %d succeeded, want 1 and 1: %+v", summary.Total, summary.Succeeded, summary.Failures)
+			}
+			if got := summary.Repositories[0].Branch; got != tt.wantBranch {
+				t.Errorf("cloned branch = %q, want %q", got, tt.wantBranch)
+			}
+			content, err := os.ReadFile(filepath.Join(out, "acme", "app", "README.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(content)); got != tt.wantBranch {
+				t.Errorf("README.md = %q, want the %s branch", got, tt.wantBranch)
+			}
+		})
+	}
+}
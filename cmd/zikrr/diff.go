@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sachin-duhan/zikrr/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var diffRunsCmd = &cobra.Command{
+	Use:   "diff-runs <manifestA> <manifestB>",
+	Short: "Compare the session manifests of two clone runs",
+	Long: `Compare two session manifests written with --manifest and report repositories
+that were added, removed, newly failing or newly succeeding in the second run.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiffRuns,
+}
+
+func init() {
+	rootCmd.AddCommand(diffRunsCmd)
+}
+
+func runDiffRuns(cmd *cobra.Command, args []string) error {
+	before, err := git.LoadManifest(args[0])
+	if err != nil {
+		return err
+	}
+	after, err := git.LoadManifest(args[1])
+	if err != nil {
+		return err
+	}
+
+	diff := git.DiffManifests(before, after)
+
+	if format, _ := cmd.Flags().GetString("output"); format != "" {
+		return writeOutput(os.Stdout, format, diff)
+	}
+
+	if diff.IsEmpty() {
+		fmt.Println("No differences between runs")
+		return nil
+	}
+	printGroup := func(label, marker string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		fmt.Printf("%s (%d):\n", label, len(names))
+		for _, name := range names {
+			fmt.Printf("  %s %s\n", marker, name)
+		}
+	}
+	printGroup("Added", "+", diff.Added)
+	printGroup("Removed", "-", diff.Removed)
+	printGroup("Newly failing", "✗", diff.NewlyFailing)
+	printGroup("Newly succeeding", "✓", diff.NewlySucceeding)
+	return nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/mattn/go-isatty"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/sachin-duhan/zikrr/internal/git"
+	"github.com/sachin-duhan/zikrr/internal/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// headlessRun is the outcome of a non-interactive clone run
+type headlessRun struct {
+	manager *git.RepositoryManager
+	repos   []*gh.Repository
+}
+
+// Summary returns the outcome of the clone run
+func (h *headlessRun) Summary() *git.CloneSummary {
+	return h.manager.Summary()
+}
+
+// Manifest returns the session manifest of the clone run
+func (h *headlessRun) Manifest() *git.SessionManifest {
+	return h.manager.Manifest()
+}
+
+// ListedCount returns the number of repositories listed after filtering
+func (h *headlessRun) ListedCount() int {
+	return len(h.repos)
+}
+
+// ClonedRepositories returns the GitHub repositories that were cloned or updated successfully
+func (h *headlessRun) ClonedRepositories() []*gh.Repository {
+	var cloned []*gh.Repository
+	for _, repo := range h.repos {
+		managed := h.manager.GetRepository(repo.GetOwner().GetLogin(), repo.GetName())
+		if managed == nil {
+			continue
+		}
+		if status, _, _ := managed.GetStatus(); status == git.StatusSuccess {
+			cloned = append(cloned, repo)
+		}
+	}
+	return cloned
+}
+
+// listHeadlessRepositories lists the repositories to clone from --project, --user or --org
+func listHeadlessRepositories(ctx context.Context, cmd *cobra.Command, client *github.Client) ([]*gh.Repository, error) {
+	orgs := organizations(cmd)
+	if user, _ := cmd.Flags().GetString("user"); user != "" {
+		repos, err := client.ListFilteredUserRepositories(ctx, user, repositoryFilter(cmd))
+		if err != nil {
+			return nil, err
+		}
+		if pkg, _ := cmd.Flags().GetString("depends-on"); pkg != "" {
+			return client.FilterByDependency(ctx, repos, pkg)
+		}
+		return repos, nil
+	}
+	if project, _ := cmd.Flags().GetString("project"); project != "" {
+		projectOrg, number, err := github.ParseProjectRef(project, firstOrganization(orgs))
+		if err != nil {
+			return nil, err
+		}
+		return client.ListProjectRepositories(ctx, projectOrg, number)
+	}
+	if len(orgs) == 0 {
+		return nil, fmt.Errorf("organization not provided. Use --org or --user flag with --no-tui")
+	}
+
+	repos, err := client.ListFilteredRepositoriesMulti(ctx, orgs, repositoryFilter(cmd), nil)
+	if err != nil {
+		return nil, err
+	}
+	if pkg, _ := cmd.Flags().GetString("depends-on"); pkg != "" {
+		return client.FilterByDependency(ctx, repos, pkg)
+	}
+	return repos, nil
+}
+
+// runHeadless lists and clones repositories without the TUI
+func runHeadless(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, opts git.CloneOptions) (cloneRun, error) {
+	repos, err := listHeadlessRepositories(ctx, cmd, client)
+	if err != nil {
+		return nil, err
+	}
+	return cloneHeadless(ctx, cmd, cfg, client, opts, repos)
+}
+
+// cloneHeadless clones repos with a repository manager configured from the
+// flags and config, printing a line as each repository finishes
+func cloneHeadless(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, opts git.CloneOptions, repos []*gh.Repository) (cloneRun, error) {
+	manager, closeManager, err := newManager(cmd, cfg, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeManager()
+
+	strategy := existingRepoStrategy(cmd, cfg)
+	ssh := useSSH(cmd)
+	fallbacks, _ := cmd.Flags().GetStringSlice("branch-fallbacks")
+	for _, repo := range repos {
+		branch := opts.Branch
+		if len(fallbacks) > 0 && opts.Tag == "" {
+			if branch, err = client.ResolveBranch(ctx, repo, opts.Branch, fallbacks); err != nil {
+				if _, ok := auth.IsSSOError(err); ok {
+					return nil, err
+				}
+				util.Warn(fmt.Sprintf("Cloning %s without branch fallbacks: %v", repo.GetFullName(), err))
+				branch = opts.Branch
+			}
+		}
+		managed := manager.AddRepository(repo.GetOwner().GetLogin(), repo.GetName(), github.CloneURL(repo, ssh), branch, strategy)
+		managed.SetLanguage(repo.GetLanguage())
+		managed.SetDefaultBranch(repo.GetDefaultBranch())
+		managed.SetSize(repo.GetSize())
+		managed.SetTag(opts.Tag)
+	}
+	fmt.Printf("Cloning %d repositories into %s\n", len(repos), manager.BaseDir())
+
+	// Updates are coalesced, so print each repository once on its final status
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	color := isatty.IsTerminal(os.Stdout.Fd())
+	printed := make(map[string]bool)
+	// The token needs SSO authorization for every repository of the organization,
+	// so stop the run on the first SSO failure instead of failing the rest one by one
+	cloneCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var ssoErr error
+	for repo := range manager.CloneAll(cloneCtx) {
+		snapshot := repo.Snapshot()
+		name := snapshot.Organization + "/" + snapshot.Name
+		line := resultLine(snapshot, color)
+		if line == "" || printed[name] {
+			continue
+		}
+		printed[name] = true
+		if !quiet {
+			fmt.Println(line)
+		}
+		_, err, _ := repo.GetStatus()
+		if _, ok := auth.IsSSOError(err); ok && ssoErr == nil {
+			ssoErr = fmt.Errorf("stopped cloning at %s: %w", name, err)
+			cancel()
+		}
+	}
+	if ssoErr != nil {
+		return nil, ssoErr
+	}
+
+	result := &headlessRun{manager: manager, repos: repos}
+	if summaryFormat(cmd, cfg) == "" {
+		summary := result.Summary()
+		elapsed := time.Duration(summary.ElapsedSeconds * float64(time.Second)).Round(100 * time.Millisecond)
+		fmt.Printf("Done: %d succeeded, %d skipped, %d failed in %s\n", summary.Succeeded, summary.Skipped, summary.Failed, elapsed)
+	}
+	return result, nil
+}
+
+// ANSI colors for result lines on a terminal
+const (
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiRed    = "\033[31m"
+	ansiReset  = "\033[0m"
+)
+
+// resultLine formats the one-line result of a finished repository, e.g.
+// "✓ org/repo (main, 3.2s)" or "✗ org/repo: authentication required". It
+// returns "" while the repository is still in progress.
+func resultLine(repo git.RepositorySnapshot, color bool) string {
+	name := repo.Organization + "/" + repo.Name
+
+	var mark, detail, ansi string
+	switch repo.Status {
+	case git.StatusSuccess.String():
+		branch := repo.ClonedBranch
+		if branch == "" && repo.Tag != "" {
+			branch = "tag " + repo.Tag
+		}
+		if branch == "" {
+			branch = repo.Branch
+		}
+		if branch == "" {
+			branch = "default branch"
+		}
+		duration := time.Duration(repo.DurationSeconds * float64(time.Second)).Round(100 * time.Millisecond)
+		mark, detail, ansi = "✓", fmt.Sprintf(" (%s, %s)", branch, duration), ansiGreen
+	case git.StatusSkipped.String():
+		mark, detail, ansi = "-", " (skipped)", ansiYellow
+	case git.StatusFailed.String():
+		// Only the first line; git output follows on later lines
+		reason, _, _ := strings.Cut(repo.Error, "\n")
+		mark, detail, ansi = "✗", ": "+reason, ansiRed
+	default:
+		return ""
+	}
+
+	if color {
+		mark = ansi + mark + ansiReset
+	}
+	return fmt.Sprintf("  %s %s%s", mark, name, detail)
+}
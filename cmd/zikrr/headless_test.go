@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+func TestCloneHeadlessStopsOnSSO(t *testing.T) {
+	const ssoURL = "https://github.com/orgs/acme/sso?authorization_request=abc"
+
+	// git shows a text/plain error body as remote output, as it does for GitHub's SSO notice
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("The 'acme' organization has enabled or enforced SAML SSO. To access\n" +
+			"this repository, visit " + ssoURL + " and try your request again.\n"))
+	}))
+	t.Cleanup(remote.Close)
+
+	tests := []struct {
+		name  string
+		repos []string
+	}{
+		{"single repository", []string{"app"}},
+		{"remaining repositories", []string{"app", "lib", "web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := parseRootFlags(t, "--no-tui", "--quiet")
+			cfg := loadTestConfig(t, "clone:\n  output_dir: "+t.TempDir()+"\n  max_concurrent: 1\n")
+			opts := git.DefaultCloneOptions()
+			opts.MaxRetries = 0
+
+			var repos []*gh.Repository
+			for _, name := range tt.repos {
+				repos = append(repos, &gh.Repository{
+					Name:     gh.String(name),
+					FullName: gh.String("acme/" + name),
+					Owner:    &gh.User{Login: gh.String("acme")},
+					CloneURL: gh.String(remote.URL + "/acme/" + name + ".git"),
+				})
+			}
+
+			result, err := cloneHeadless(t.Context(), cmd, cfg, nil, opts, repos)
+			if result != nil {
+				t.Errorf("cloneHeadless() returned a result for the aborted run")
+			}
+			url, ok := auth.IsSSOError(err)
+			if !ok || url != ssoURL {
+				t.Fatalf("cloneHeadless() error = %v, want an SSO error with %q", err, ssoURL)
+			}
+			if !errors.Is(err, git.ErrAuthRequired) || !strings.Contains(err.Error(), ssoURL) {
+				t.Errorf("error %q does not show the authorization URL", err)
+			}
+		})
+	}
+}
+
+func TestResultLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		repo  git.RepositorySnapshot
+		color bool
+		want  string
+	}{
+		{
+			name: "success",
+			repo: git.RepositorySnapshot{Organization: "acme", Name: "app", Status: "Success", ClonedBranch: "main", DurationSeconds: 3.24},
+			want: "  ✓ acme/app (main, 3.2s)",
+		},
+		{
+			name: "success on a tag",
+			repo: git.RepositorySnapshot{Organization: "acme", Name: "app", Status: "Success", Tag: "v1.2.0", DurationSeconds: 1},
+			want: "  ✓ acme/app (tag v1.2.0, 1s)",
+		},
+		{
+			name: "success without a known branch",
+			repo: git.RepositorySnapshot{Organization: "acme", Name: "app", Status: "Success"},
+			want: "  ✓ acme/app (default branch, 0s)",
+		},
+		{
+			name: "skipped",
+			repo: git.RepositorySnapshot{Organization: "acme", Name: "app", Status: "Skipped"},
+			want: "  - acme/app (skipped)",
+		},
+		{
+			name: "failed shows the first line",
+			repo: git.RepositorySnapshot{Organization: "acme", Name: "app", Status: "Failed", Error: "authentication required\nOutput: fatal: could not read Username"},
+			want: "  ✗ acme/app: authentication required",
+		},
+		{
+			name:  "colored",
+			repo:  git.RepositorySnapshot{Organization: "acme", Name: "app", Status: "Failed", Error: "timed out"},
+			color: true,
+			want:  "  " + ansiRed + "✗" + ansiReset + " acme/app: timed out",
+		},
+		{
+			name: "in progress",
+			repo: git.RepositorySnapshot{Organization: "acme", Name: "app", Status: "Cloning"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultLine(tt.repo, tt.color); got != tt.want {
+				t.Errorf("resultLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = saved }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-output
+}
+
+func TestCloneHeadlessResultLines(t *testing.T) {
+	remote := newLocalRemote(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"streamed", []string{"--no-tui"}, true},
+		{"quiet", []string{"--no-tui", "--quiet"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := parseRootFlags(t, tt.args...)
+			cfg := loadTestConfig(t, "clone:\n  output_dir: "+t.TempDir()+"\n  max_concurrent: 1\n")
+			repo := &gh.Repository{
+				Name:     gh.String("app"),
+				FullName: gh.String("acme/app"),
+				Owner:    &gh.User{Login: gh.String("acme")},
+				CloneURL: gh.String(remote),
+			}
+
+			var err error
+			output := captureStdout(t, func() {
+				_, err = cloneHeadless(t.Context(), cmd, cfg, nil, git.DefaultCloneOptions(), []*gh.Repository{repo})
+			})
+			if err != nil {
+				t.Fatalf("cloneHeadless() error = %v", err)
+			}
+			// Output is not a terminal, so the line is not colored
+			if got := strings.Contains(output, "  ✓ acme/app (main, "); got != tt.want {
+				t.Errorf("result line printed = %v, want %v:\n%s", got, tt.want, output)
+			}
+			if !strings.Contains(output, "Done: 1 succeeded, 0 skipped, 0 failed") {
+				t.Errorf("final summary missing:\n%s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented default config file",
+	Long: `Write a config file listing every setting with its default value, to the path
+given with --config or .zikrr.yaml in $XDG_CONFIG_HOME (or ~/.config).`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().Bool("force", false, "overwrite an existing config file")
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			return err
+		}
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if err := config.WriteDefault(path, force); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n", path)
+	return nil
+}
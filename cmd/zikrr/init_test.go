@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// runInitCommand runs `zikrr init` with args, restoring the flags afterwards
+func runInitCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		for _, flags := range []*pflag.FlagSet{rootCmd.PersistentFlags(), initCmd.Flags()} {
+			flags.Visit(func(f *pflag.Flag) {
+				f.Value.Set(f.DefValue)
+				f.Changed = false
+			})
+		}
+		rootCmd.SetArgs(nil)
+	})
+	rootCmd.SetArgs(append([]string{"init"}, args...))
+	var err error
+	output := captureStdout(t, func() { err = rootCmd.Execute() })
+	return output, err
+}
+
+func TestInitCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	defaultPath := filepath.Join(home, ".config", ".zikrr.yaml")
+	explicit := filepath.Join(t.TempDir(), "custom.yaml")
+
+	tests := []struct {
+		name    string
+		args    []string
+		path    string
+		wantErr string
+	}{
+		{"default path", nil, defaultPath, ""},
+		{"existing file refused", nil, defaultPath, "already exists"},
+		{"existing file forced", []string{"--force"}, defaultPath, ""},
+		{"explicit path", []string{"--config", explicit}, explicit, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := runInitCommand(t, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("init error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("init error = %v", err)
+			}
+			if !strings.Contains(output, "Wrote "+tt.path) {
+				t.Errorf("output = %q, want the written path", output)
+			}
+			content, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"github:", "clone:", "max_concurrent: 5", "existing_repos: skip", "log:"} {
+				if !strings.Contains(string(content), key) {
+					t.Errorf("%s does not contain %q", tt.path, key)
+				}
+			}
+		})
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	gh "github.com/google/go-github/v60/github"
 	"github.com/sachin-duhan/zikrr/internal/auth"
 	"github.com/sachin-duhan/zikrr/internal/cli/tui"
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/sachin-duhan/zikrr/internal/git"
 	"github.com/sachin-duhan/zikrr/internal/github"
+	"github.com/sachin-duhan/zikrr/internal/notify"
 	"github.com/sachin-duhan/zikrr/pkg/util"
 	"github.com/spf13/cobra"
 )
@@ -19,24 +26,101 @@ var rootCmd = &cobra.Command{
 	Long: `Zikrr is a powerful command-line tool for cloning GitHub organization repositories.
 It provides interactive selection, concurrent cloning, and multi-branch support.
 Complete documentation is available at https://github.com/sachin-duhan/zikrr`,
-	Version: "0.1.0",
+	Version: Version,
 	RunE:    run,
 }
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is $HOME/.zikrr.yaml)")
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is .zikrr.yaml in $XDG_CONFIG_HOME, ~/.config or the current directory)")
 	rootCmd.PersistentFlags().StringP("log-level", "l", "info", "log level (debug, info, warn, error)")
-	rootCmd.PersistentFlags().StringP("output", "o", "", "output format for summary (json, yaml)")
-	rootCmd.PersistentFlags().StringP("token", "t", "", "GitHub personal access token (can also be set via GITHUB_TOKEN env)")
-	rootCmd.PersistentFlags().StringP("org", "g", "", "GitHub organization name")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "output format for summary (json, yaml); written to output.file from the config, or stdout")
+	rootCmd.PersistentFlags().StringP("token", "t", "", "GitHub personal access token (can also be set via GITHUB_TOKEN env or github.token in the config)")
+	rootCmd.PersistentFlags().String("output-dir", "", "directory repositories are cloned into (default clone.output_dir, or the current directory)")
+	rootCmd.PersistentFlags().Int("max-concurrent", 0, "maximum number of concurrent clones (default clone.max_concurrent)")
+	rootCmd.PersistentFlags().Duration("connect-timeout", 0, "abort a transfer stalled for this long (default clone.connect_timeout seconds)")
+	rootCmd.PersistentFlags().Duration("operation-timeout", 0, "longest time a single clone may take (default clone.operation_timeout seconds)")
+	rootCmd.PersistentFlags().Duration("update-timeout", 0, "longest time the fetch updating an existing clone may take (default clone.update_timeout seconds, or the operation timeout)")
+	rootCmd.PersistentFlags().String("user", "", "list the repositories of this user account instead of an organization")
+	rootCmd.PersistentFlags().String("github-url", "", "GitHub Enterprise Server URL, e.g. https://github.example.com (also github.base_url in the config)")
+	rootCmd.PersistentFlags().StringSliceP("org", "g", nil, "GitHub organization name (comma-separated or repeated for several)")
+	rootCmd.PersistentFlags().Bool("no-tui", false, "clone non-interactively without the TUI, printing plain progress lines (requires --org, --user or --project)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "with --no-tui, print only the final summary instead of a line per repository")
+	rootCmd.PersistentFlags().String("visibility", "", "only list repositories with this visibility (public, private, all)")
+	rootCmd.PersistentFlags().String("language", "", "only list repositories with this primary language")
+	rootCmd.PersistentFlags().StringSlice("topics", nil, "only list repositories having all of these topics")
+	rootCmd.PersistentFlags().Int("min-stars", 0, "only list repositories with at least this many stars (0 = no minimum)")
+	rootCmd.PersistentFlags().Int("max-stars", 0, "only list repositories with at most this many stars (0 = no maximum)")
+	rootCmd.PersistentFlags().StringSlice("name", nil, "only list repositories whose name or org/name matches one of these globs (e.g. api-*)")
+	rootCmd.PersistentFlags().StringSlice("exclude", nil, "leave out repositories whose name or org/name matches one of these globs (e.g. *-archive,legacy-*); wins over --name")
+	rootCmd.PersistentFlags().Bool("exclude-templates", true, "leave template repositories out of the listing")
+	rootCmd.PersistentFlags().Bool("include-templates", false, "list template repositories too (overrides --exclude-templates)")
+	rootCmd.PersistentFlags().String("project", "", "clone the repositories linked from an organization project (URL, org/number, or number with --org)")
+	rootCmd.PersistentFlags().String("depends-on", "", "only list repositories whose dependency graph contains this package (e.g. npm:lodash)")
+	rootCmd.PersistentFlags().StringSlice("branch-fallbacks", nil, "branches to try, in order, when the requested branch is missing (e.g. release,main,master)")
+	rootCmd.PersistentFlags().String("clone-mode", "normal", "kind of clone: normal, bare, or mirror (every ref, for backups); bare and mirror clones go to <repo>.git")
+	rootCmd.PersistentFlags().Int("depth", 0, "create shallow clones with this many commits of history (0 for a full clone)")
+	rootCmd.PersistentFlags().Bool("submodules", false, "clone and update submodules recursively (also clone.submodules in the config)")
+	rootCmd.PersistentFlags().StringSlice("keep-ext", nil, "after cloning, delete working-tree files without one of these extensions (e.g. go,md; .git is kept)")
+	rootCmd.PersistentFlags().StringSlice("worktrees", nil, "extra branches (patterns like release/*) to check out as worktrees next to each clone")
+	rootCmd.PersistentFlags().Bool("ssh", false, "clone over SSH instead of HTTPS")
+	rootCmd.PersistentFlags().String("ssh-key", "", "private key for SSH clones, used instead of ~/.ssh/config (implies --ssh)")
+	rootCmd.PersistentFlags().String("ssh-command", "", "ssh command for SSH clones, e.g. \"ssh -p 2222\" (sets GIT_SSH_COMMAND, implies --ssh)")
+	rootCmd.PersistentFlags().Bool("lfs-skip-smudge", false, "clone Git LFS pointers only, without downloading LFS content (run git lfs pull later)")
+	rootCmd.PersistentFlags().Duration("slow-threshold", 0, "flag clones taking longer than this as slow in the summary (e.g. 2m, 0 to disable)")
+	rootCmd.PersistentFlags().String("post-clone-hook", "", "shell command run in each repository after a successful clone or update; may use {{.Org}}, {{.Repo}} and {{quote .Repo}}")
+	rootCmd.PersistentFlags().Bool("post-clone-hook-required", false, "mark a repository failed when its post-clone hook fails (default: record the failure only)")
+	rootCmd.PersistentFlags().Int("post-clone-retries", 0, "number of times a failed post-clone hook is retried")
+	rootCmd.PersistentFlags().IntSlice("post-clone-retry-codes", nil, "only retry the post-clone hook for these exit codes (default: any failure)")
+	rootCmd.PersistentFlags().Duration("post-clone-backoff", time.Second, "delay before the first post-clone hook retry; it doubles with each further retry")
+	rootCmd.PersistentFlags().Bool("sync", false, "clone new repositories and update existing ones, so repeated runs are safe (same as clone.existing_repos: sync)")
+	rootCmd.PersistentFlags().Bool("backup-on-overwrite", false, "move existing repositories to a timestamped .bak directory instead of deleting them on overwrite")
+	rootCmd.PersistentFlags().Int("backup-retention", 0, "number of backups kept per repository by --backup-on-overwrite, 0 keeps all (default clone.backup_retention)")
+	rootCmd.PersistentFlags().Duration("since", 0, "skip existing repositories cloned or fetched within this long, e.g. 30m (default clone.skip_if_newer_than)")
+	rootCmd.PersistentFlags().String("disk-check", "warn", "before cloning, compare repository sizes with free disk space: warn, abort or off")
+	rootCmd.PersistentFlags().Duration("max-backoff", git.DefaultMaxBackoff, "longest delay between clone retries; delays double per retry with random jitter")
+	rootCmd.PersistentFlags().Duration("ramp-up-interval", 0, "start with one clone and add another concurrent clone every interval (e.g. 3s, 0 to start all at once)")
+	rootCmd.PersistentFlags().Bool("adaptive-concurrency", false, "scale concurrent clones down as the GitHub rate limit runs low and hold new clones while it is exhausted")
+	rootCmd.PersistentFlags().String("dependency-order", "", "YAML file mapping org/repo to the repositories it depends on; clones run in dependency order")
+	rootCmd.PersistentFlags().StringToString("org-dir", nil, "output directory for an organization as org=path (repeatable, overrides <dir>/<org>)")
+	rootCmd.PersistentFlags().Bool("notify-bell", false, "ring the terminal bell on completion and show progress in the terminal title")
+	rootCmd.PersistentFlags().String("progress-socket", "", "stream progress events as JSON over a Unix domain socket at this path")
+	rootCmd.PersistentFlags().Int("page-size", github.MaxPageSize, "number of items requested per GitHub API page (1-100)")
+	rootCmd.PersistentFlags().String("tag-topic", "", "after cloning, add this topic to each cloned repository on GitHub (requires admin, asks for confirmation)")
+	rootCmd.PersistentFlags().String("manifest", "", "write a JSON session manifest of the run, with the path and commit of each clone, to this path (compare runs with diff-runs)")
+	rootCmd.PersistentFlags().Bool("with-releases", false, "look up the latest release of each repository for the summary (one API call per repository)")
+	rootCmd.PersistentFlags().Bool("verify-count", false, "fail the run unless every listed repository was cloned, updated or skipped")
+	rootCmd.PersistentFlags().Bool("resume-listing", false, "persist listing progress so an interrupted listing resumes on the next run")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "list organizations from the API even when github.cache_ttl enables the listing cache")
+	rootCmd.PersistentFlags().String("selection-file", "", "pre-select the repositories listed in this JSON/YAML file; r in the TUI saves the selection to it")
+	rootCmd.PersistentFlags().Bool("remember-selection", false, "save the TUI selection per organization and offer to restore it on the next launch")
+	rootCmd.MarkFlagsMutuallyExclusive("user", "org")
+	rootCmd.MarkFlagsMutuallyExclusive("ramp-up-interval", "adaptive-concurrency")
 }
 
-func run(cmd *cobra.Command, args []string) error {
+// setup initializes logging and authentication shared by all commands
+func setup(cmd *cobra.Command) (context.Context, *config.Config, *github.Client, error) {
 	// Initialize logger
 	logLevel, _ := cmd.Flags().GetString("log-level")
 	if err := util.InitLogger(logLevel, "text", ""); err != nil {
-		return fmt.Errorf("failed to initialize logger: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	applyFlagOverrides(cmd, cfg)
+
+	// Reinitialize the logger with the merged settings
+	rotate := util.RotateOptions{
+		MaxSizeMB:  cfg.Log.MaxSizeMB,
+		MaxBackups: cfg.Log.MaxBackups,
+		MaxAgeDays: cfg.Log.MaxAgeDays,
+	}
+	if err := util.InitLoggerWithRotation(cfg.Log.Level, cfg.Log.Format, cfg.Log.File, rotate); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
 	// Get GitHub token
@@ -45,35 +129,479 @@ func run(cmd *cobra.Command, args []string) error {
 		token = auth.GetTokenFromEnv()
 	}
 	if token == "" {
-		return fmt.Errorf("GitHub token not provided. Use --token flag or set GITHUB_TOKEN environment variable")
+		token = cfg.GitHub.Token
+	}
+	if token == "" {
+		return nil, nil, nil, fmt.Errorf("GitHub token not provided. Use --token flag, set GITHUB_TOKEN environment variable or github.token in the config")
+	}
+
+	baseURL, _ := cmd.Flags().GetString("github-url")
+	if baseURL == "" {
+		baseURL = cfg.GitHub.BaseURL
+	}
+	baseURL, err = auth.ParseBaseURL(baseURL)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	proxy, err := auth.ParseProxyURL(cfg.GitHub.Proxy)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	// Validate token
 	ctx := context.Background()
-	authToken, err := auth.ValidateToken(ctx, token)
+	authToken, err := auth.ValidateToken(ctx, token, baseURL, proxy)
 	if err != nil {
-		return fmt.Errorf("invalid GitHub token: %w", err)
+		return nil, nil, nil, fmt.Errorf("invalid GitHub token: %w", err)
+	}
+	if authToken.ExpiresWithin(auth.ExpiryWarningWindow) {
+		util.Warn(fmt.Sprintf("GitHub token expires at %s; clones may fail once it does",
+			authToken.ExpiresAt.Local().Format(time.RFC1123)))
 	}
 
 	// Create GitHub client
 	client := github.NewClient(ctx, authToken)
+	client.SetAllowedOrgs(cfg.Security.AllowedOrgs)
+	pageSize, _ := cmd.Flags().GetInt("page-size")
+	client.SetPageSize(pageSize)
+	if resume, _ := cmd.Flags().GetBool("resume-listing"); resume {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get user cache directory: %w", err)
+		}
+		client.SetListStateDir(filepath.Join(cacheDir, "zikrr"))
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); cfg.GitHub.CacheTTL > 0 && !noCache {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get user cache directory: %w", err)
+		}
+		client.SetCache(filepath.Join(cacheDir, "zikrr"), cfg.GitHub.CacheTTL)
+	}
+
+	return ctx, cfg, client, nil
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx, cfg, client, err := setup(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts, err := prepareOptions(cmd, cfg, client)
+	if err != nil {
+		return err
+	}
+
+	headless, _ := cmd.Flags().GetBool("no-tui")
+	var result cloneRun
+	if headless {
+		result, err = runHeadless(ctx, cmd, cfg, client, opts)
+	} else {
+		result, err = runTUI(ctx, cmd, cfg, client, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := finishRun(ctx, cmd, cfg, client, result); err != nil {
+		return err
+	}
+	if summary := result.Summary(); headless && summary.Failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed to clone", summary.Failed, summary.Total)
+	}
+	return nil
+}
+
+// cloneRun exposes the outcome of a completed clone run, interactive or headless
+type cloneRun interface {
+	Summary() *git.CloneSummary
+	Manifest() *git.SessionManifest
+	ClonedRepositories() []*gh.Repository
+	ListedCount() int
+}
+
+// applyFlagOverrides replaces config values with the flags given on the command line
+func applyFlagOverrides(cmd *cobra.Command, cfg *config.Config) {
+	if cmd.Flags().Changed("log-level") {
+		cfg.Log.Level, _ = cmd.Flags().GetString("log-level")
+	}
+	if cmd.Flags().Changed("output-dir") {
+		cfg.Clone.OutputDir, _ = cmd.Flags().GetString("output-dir")
+	}
+	if cmd.Flags().Changed("max-concurrent") {
+		cfg.Clone.MaxConcurrent, _ = cmd.Flags().GetInt("max-concurrent")
+	}
+	if cmd.Flags().Changed("backup-retention") {
+		cfg.Clone.BackupRetention, _ = cmd.Flags().GetInt("backup-retention")
+	}
+	if cmd.Flags().Changed("connect-timeout") {
+		timeout, _ := cmd.Flags().GetDuration("connect-timeout")
+		cfg.Clone.ConnectTimeout = int(timeout.Seconds())
+	}
+	if cmd.Flags().Changed("operation-timeout") {
+		timeout, _ := cmd.Flags().GetDuration("operation-timeout")
+		cfg.Clone.OperationTimeout = int(timeout.Seconds())
+	}
+}
+
+// Defaults used when the output directory or concurrency is not configured
+const (
+	defaultBaseDir       = "."
+	defaultMaxConcurrent = 5
+)
+
+// newManager builds the repository manager shared by the interactive and
+// headless modes, configured from the flags and config. The returned function
+// closes the progress socket, if any, once cloning is over.
+func newManager(cmd *cobra.Command, cfg *config.Config, client *github.Client, opts git.CloneOptions) (*git.RepositoryManager, func(), error) {
+	baseDir := cfg.Clone.OutputDir
+	if baseDir == "" {
+		baseDir = defaultBaseDir
+	}
+	maxConcurrent := cfg.Clone.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultMaxConcurrent
+	}
+
+	manager := git.NewRepositoryManager(baseDir, maxConcurrent)
+	manager.SetCloneDefaults(opts)
+	overrides, err := strategyOverrides(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager.SetStrategyOverrides(overrides)
+	if orgDirs, _ := cmd.Flags().GetStringToString("org-dir"); len(orgDirs) > 0 {
+		manager.SetOrgDirs(orgDirs)
+	}
+	if interval, _ := cmd.Flags().GetDuration("ramp-up-interval"); interval > 0 {
+		manager.SetRampUp(interval)
+	}
+	if adaptive, _ := cmd.Flags().GetBool("adaptive-concurrency"); adaptive {
+		manager.SetRateLimitProvider(rateLimitProvider{client})
+	}
+	if path, _ := cmd.Flags().GetString("dependency-order"); path != "" {
+		deps, err := git.LoadDependencyOrder(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		manager.SetDependencies(deps)
+	}
+	diskCheck, err := diskCheckMode(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager.SetDiskCheck(diskCheck)
+	manager.SetSkipNewerThan(skipNewerThan(cmd, cfg))
+	layout, err := git.ParseLayout(cfg.Clone.Layout)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager.SetLayout(layout)
+	if cfg.Clone.PathTemplate != "" {
+		tmpl, err := git.ParsePathTemplate(cfg.Clone.PathTemplate)
+		if err != nil {
+			return nil, nil, err
+		}
+		manager.SetPathTemplate(tmpl)
+	}
+
+	closeManager := func() {}
+	if socketPath, _ := cmd.Flags().GetString("progress-socket"); socketPath != "" {
+		server, err := git.NewProgressServer(socketPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		manager.AddObserver(server.Publish)
+		closeManager = func() { server.Close() }
+	}
+	return manager, closeManager, nil
+}
+
+// existingRepoStrategy returns how existing clones are handled: sync with --sync,
+// otherwise clone.existing_repos from the config. An unknown value falls back
+// to skipping existing clones with a warning.
+func existingRepoStrategy(cmd *cobra.Command, cfg *config.Config) git.ExistingRepoStrategy {
+	if sync, _ := cmd.Flags().GetBool("sync"); sync {
+		return git.Sync
+	}
+	strategy, err := git.ParseExistingRepoStrategy(cfg.Clone.ExistingRepos)
+	if err != nil {
+		util.Warn(fmt.Sprintf("Ignoring clone.existing_repos: %v; skipping existing repositories", err))
+		return git.SkipExisting
+	}
+	return strategy
+}
+
+// strategyOverrides parses the per-repository strategies of clone.strategy_overrides
+func strategyOverrides(cfg *config.Config) ([]git.StrategyOverride, error) {
+	overrides := make([]git.StrategyOverride, 0, len(cfg.Clone.StrategyOverrides))
+	for _, entry := range cfg.Clone.StrategyOverrides {
+		override, err := git.ParseStrategyOverride(entry.Repo, entry.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+// diskCheckMode returns the free-space preflight mode from --disk-check
+func diskCheckMode(cmd *cobra.Command) (git.DiskCheck, error) {
+	mode, _ := cmd.Flags().GetString("disk-check")
+	return git.ParseDiskCheck(mode)
+}
+
+// skipNewerThan returns the freshness window from --since, falling back to clone.skip_if_newer_than
+func skipNewerThan(cmd *cobra.Command, cfg *config.Config) time.Duration {
+	if cmd.Flags().Changed("since") {
+		window, _ := cmd.Flags().GetDuration("since")
+		return window
+	}
+	return cfg.Clone.SkipIfNewerThan
+}
 
-	// Create and run TUI
-	model := tui.NewModel(ctx, client, ".", 5)
+// rateLimitProvider reports the GitHub client's core rate limit for adaptive clone concurrency
+type rateLimitProvider struct {
+	client *github.Client
+}
+
+// RateLimit fetches the current core rate limit
+func (p rateLimitProvider) RateLimit(ctx context.Context) (git.RateLimit, error) {
+	info, err := p.client.GetRateLimit(ctx)
+	if err != nil {
+		return git.RateLimit{}, err
+	}
+	return git.RateLimit{Remaining: info.Remaining, Limit: info.Limit, Reset: info.Reset}, nil
+}
+
+// useSSH reports whether repositories are cloned over SSH
+func useSSH(cmd *cobra.Command) bool {
+	ssh, _ := cmd.Flags().GetBool("ssh")
+	key, _ := cmd.Flags().GetString("ssh-key")
+	command, _ := cmd.Flags().GetString("ssh-command")
+	return ssh || key != "" || command != ""
+}
+
+// organizations returns the organizations given with --org
+func organizations(cmd *cobra.Command) []string {
+	values, _ := cmd.Flags().GetStringSlice("org")
+	return github.ParseOrganizations(values)
+}
+
+// firstOrganization returns the first organization, or "" when none was given
+func firstOrganization(orgs []string) string {
+	if len(orgs) == 0 {
+		return ""
+	}
+	return orgs[0]
+}
+
+// runTUI runs the interactive repository picker and clone progress view
+func runTUI(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, opts git.CloneOptions) (cloneRun, error) {
+	manager, closeManager, err := newManager(cmd, cfg, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeManager()
+
+	model := tui.NewModel(ctx, client, manager)
+	model.SetExistingRepoStrategy(existingRepoStrategy(cmd, cfg))
 
 	// If organization is provided via flag, pre-fill it
-	if org, _ := cmd.Flags().GetString("org"); org != "" {
-		model.SetOrganization(org)
+	orgs := organizations(cmd)
+	if len(orgs) > 0 {
+		model.SetOrganization(strings.Join(orgs, ","))
+	}
+
+	if path, _ := cmd.Flags().GetString("selection-file"); path != "" {
+		if err := model.SetSelectionFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// If a user is provided, offer the repositories of that account
+	if user, _ := cmd.Flags().GetString("user"); user != "" {
+		repos, err := client.ListFilteredUserRepositories(ctx, user, repositoryFilter(cmd))
+		if err != nil {
+			return nil, err
+		}
+		model.SetRepositories(user, repos)
+	}
+
+	// If a project is provided, offer only the repositories it links to
+	if project, _ := cmd.Flags().GetString("project"); project != "" {
+		projectOrg, number, err := github.ParseProjectRef(project, firstOrganization(orgs))
+		if err != nil {
+			return nil, err
+		}
+		repos, err := client.ListProjectRepositories(ctx, projectOrg, number)
+		if err != nil {
+			return nil, err
+		}
+		model.SetRepositories(fmt.Sprintf("%s project #%d", projectOrg, number), repos)
+	}
+
+	model.SetFilter(repositoryFilter(cmd))
+	if notify, _ := cmd.Flags().GetBool("notify-bell"); notify {
+		model.SetNotify(true)
+	}
+
+	if pkg, _ := cmd.Flags().GetString("depends-on"); pkg != "" {
+		model.SetDependsOn(pkg)
+	}
+	if fallbacks, _ := cmd.Flags().GetStringSlice("branch-fallbacks"); len(fallbacks) > 0 {
+		model.SetBranchFallbacks(fallbacks)
+	}
+	model.SetSSH(useSSH(cmd))
+	if remember, _ := cmd.Flags().GetBool("remember-selection"); remember {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user cache directory: %w", err)
+		}
+		model.SetSelectionStateDir(filepath.Join(cacheDir, "zikrr"))
 	}
 
 	p := tea.NewProgram(model)
 	if err := p.Start(); err != nil {
-		return fmt.Errorf("failed to start TUI: %w", err)
+		return nil, fmt.Errorf("failed to start TUI: %w", err)
+	}
+	return &model, nil
+}
+
+// finishRun runs the post-clone steps shared by the interactive and headless modes
+func finishRun(ctx context.Context, cmd *cobra.Command, cfg *config.Config, client *github.Client, result cloneRun) error {
+	if topic, _ := cmd.Flags().GetString("tag-topic"); topic != "" {
+		tagClonedRepositories(ctx, client, result.ClonedRepositories(), topic)
+	}
+
+	// Email the summary; notification failures never fail the run
+	if summary := result.Summary(); cfg.Notify.SMTP.Host != "" && summary.Total > 0 {
+		if err := notify.SendEmail(cfg.Notify.SMTP, summary); err != nil {
+			util.Warn(err.Error())
+		}
+	}
+
+	releases := collectReleases(ctx, cmd, client, result.ClonedRepositories())
+	if summaryFormat(cmd, cfg) != "" {
+		if err := writeSummary(cmd, cfg, runReport{
+			CloneSummary: result.Summary(),
+			Releases:     releases,
+			Config:       effectiveConfig(cmd),
+		}); err != nil {
+			return err
+		}
+	} else {
+		printReleases(releases)
+	}
+
+	if path, _ := cmd.Flags().GetString("manifest"); path != "" {
+		if err := git.WriteManifest(path, result.Manifest()); err != nil {
+			return err
+		}
+	}
+
+	if verify, _ := cmd.Flags().GetBool("verify-count"); verify {
+		if _, err := git.Reconcile(result.ListedCount(), result.Summary()); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// repositoryFilter builds the repository filter from the command flags
+func repositoryFilter(cmd *cobra.Command) *github.RepositoryFilter {
+	filter := &github.RepositoryFilter{}
+	filter.Visibility, _ = cmd.Flags().GetString("visibility")
+	filter.Language, _ = cmd.Flags().GetString("language")
+	filter.Topics, _ = cmd.Flags().GetStringSlice("topics")
+	filter.MinStars, _ = cmd.Flags().GetInt("min-stars")
+	filter.MaxStars, _ = cmd.Flags().GetInt("max-stars")
+	filter.NamePatterns, _ = cmd.Flags().GetStringSlice("name")
+	filter.ExcludePatterns, _ = cmd.Flags().GetStringSlice("exclude")
+	include, _ := cmd.Flags().GetBool("include-templates")
+	exclude, _ := cmd.Flags().GetBool("exclude-templates")
+	if !include && exclude {
+		template := false
+		filter.Template = &template
+	}
+	return filter
+}
+
+// prepareOptions builds and validates the clone options from the flags and
+// config, authenticating clones with the client's token
+func prepareOptions(cmd *cobra.Command, cfg *config.Config, client *github.Client) (git.CloneOptions, error) {
+	opts, err := configuredOptions(cmd, cfg)
+	if err != nil {
+		return opts, err
+	}
+	opts.Token = client.Token().Value
+	opts.TokenHost = auth.WebHost(client.Token().BaseURL)
+	opts.Proxy = client.Token().Proxy
+	return opts, nil
+}
+
+// configuredOptions builds and validates the clone options from the flags and
+// the config, with flag overrides already applied to cfg
+func configuredOptions(cmd *cobra.Command, cfg *config.Config) (git.CloneOptions, error) {
+	opts := cloneOptions(cmd)
+	if cfg.Clone.ConnectTimeout > 0 {
+		opts.ConnTimeout = time.Duration(cfg.Clone.ConnectTimeout) * time.Second
+	}
+	if cfg.Clone.OperationTimeout > 0 {
+		opts.CloneTimeout = time.Duration(cfg.Clone.OperationTimeout) * time.Second
+	}
+	if opts.UpdateTimeout == 0 && cfg.Clone.UpdateTimeout > 0 {
+		opts.UpdateTimeout = time.Duration(cfg.Clone.UpdateTimeout) * time.Second
+	}
+	opts.BackupRetention = cfg.Clone.BackupRetention
+	opts.Backend = cfg.Clone.Backend
+	opts.LanguageHooks = cfg.Clone.LanguageHooks
+	opts.Submodules = opts.Submodules || cfg.Clone.Submodules
+	if opts.PostCloneHook == "" {
+		opts.PostCloneHook = cfg.Clone.PostHook
+	}
+	opts.PostCloneHookRequired = opts.PostCloneHookRequired || cfg.Clone.PostHookRequired
+	mode, _ := cmd.Flags().GetString("clone-mode")
+	var err error
+	if opts.Mode, err = git.ParseCloneMode(mode); err != nil {
+		return opts, err
+	}
+
+	// Reject conflicting options before any clone starts
+	if err := git.ValidateOptions(opts); err != nil {
+		return opts, err
+	}
+	if err := repositoryFilter(cmd).ValidatePatterns(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// cloneOptions builds the default clone options from the command flags
+func cloneOptions(cmd *cobra.Command) git.CloneOptions {
+	opts := git.DefaultCloneOptions()
+	opts.BackupOnOverwrite, _ = cmd.Flags().GetBool("backup-on-overwrite")
+	opts.Depth, _ = cmd.Flags().GetInt("depth")
+	opts.Submodules, _ = cmd.Flags().GetBool("submodules")
+	opts.KeepExtensions, _ = cmd.Flags().GetStringSlice("keep-ext")
+	opts.Worktrees, _ = cmd.Flags().GetStringSlice("worktrees")
+	opts.LFSSkipSmudge, _ = cmd.Flags().GetBool("lfs-skip-smudge")
+	opts.SSHKeyPath, _ = cmd.Flags().GetString("ssh-key")
+	opts.SSHCommand, _ = cmd.Flags().GetString("ssh-command")
+	opts.SlowThreshold, _ = cmd.Flags().GetDuration("slow-threshold")
+	opts.MaxBackoff, _ = cmd.Flags().GetDuration("max-backoff")
+	opts.UpdateTimeout, _ = cmd.Flags().GetDuration("update-timeout")
+	opts.PostCloneHook, _ = cmd.Flags().GetString("post-clone-hook")
+	opts.PostCloneHookRequired, _ = cmd.Flags().GetBool("post-clone-hook-required")
+	opts.PostCloneRetries, _ = cmd.Flags().GetInt("post-clone-retries")
+	opts.PostCloneRetryCodes, _ = cmd.Flags().GetIntSlice("post-clone-retry-codes")
+	opts.PostCloneBackoff, _ = cmd.Flags().GetDuration("post-clone-backoff")
+	return opts
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/internal/cli/tui"
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/sachin-duhan/zikrr/internal/git"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// parseRootFlags parses args as root command flags, restoring the flag
+// defaults when the test ends
+func parseRootFlags(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	t.Cleanup(func() {
+		rootCmd.Flags().Visit(func(f *pflag.Flag) {
+			if slice, ok := f.Value.(pflag.SliceValue); ok {
+				slice.Replace(nil)
+			} else {
+				f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	})
+	if err := rootCmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return rootCmd
+}
+
+// loadTestConfig loads content as the config file given with --config
+func loadTestConfig(t *testing.T, content string) *config.Config {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	path := filepath.Join(t.TempDir(), "zikrr.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestRepositoryFilterTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		// want is the Template filter value, nil when templates are listed
+		want *bool
+	}{
+		{"default excludes", nil, boolPtr(false)},
+		{"include", []string{"--include-templates"}, nil},
+		{"include wins over exclude", []string{"--include-templates", "--exclude-templates"}, nil},
+		{"exclude disabled", []string{"--exclude-templates=false"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := repositoryFilter(parseRootFlags(t, tt.args...))
+			if (filter.Template == nil) != (tt.want == nil) || (tt.want != nil && *filter.Template != *tt.want) {
+				t.Errorf("Template = %v, want %v", filter.Template, tt.want)
+			}
+		})
+	}
+}
+
+// boolPtr returns a pointer to b
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestNewManagerDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"not configured", "clone:\n  max_concurrent: 0\n", defaultBaseDir},
+		{"configured", "clone:\n  output_dir: /srv/src\n  max_concurrent: 2\n", "/srv/src"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := parseRootFlags(t)
+			cfg := loadTestConfig(t, tt.config)
+			manager, closeManager, err := newManager(cmd, cfg, nil, git.DefaultCloneOptions())
+			if err != nil {
+				t.Fatalf("newManager() error = %v", err)
+			}
+			defer closeManager()
+			if manager.BaseDir() != tt.want {
+				t.Errorf("BaseDir() = %q, want %q", manager.BaseDir(), tt.want)
+			}
+			// The TUI model is built on the manager without a GitHub client
+			if view := tui.NewModel(t.Context(), nil, manager).View(); view == "" {
+				t.Error("the TUI model rendered nothing")
+			}
+		})
+	}
+}
+
+func TestExistingRepoStrategy(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		want   git.ExistingRepoStrategy
+	}{
+		{"default", nil, "", git.SkipExisting},
+		{"config", nil, "clone:\n  existing_repos: fetch-only\n", git.FetchOnly},
+		{"config sync", nil, "clone:\n  existing_repos: sync\n", git.Sync},
+		{"sync flag wins", []string{"--sync"}, "clone:\n  existing_repos: overwrite\n", git.Sync},
+		{"invalid config skips", nil, "clone:\n  existing_repos: merge\n", git.SkipExisting},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := parseRootFlags(t, tt.args...)
+			cfg := loadTestConfig(t, tt.config)
+			if got := existingRepoStrategy(cmd, cfg); got != tt.want {
+				t.Errorf("existingRepoStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategyOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    []git.StrategyOverride
+		wantErr bool
+	}{
+		{"none", "", nil, false},
+		{"listed in order", "clone:\n  strategy_overrides:\n    - repo: acme/api\n      strategy: overwrite\n    - repo: acme/legacy-*\n      strategy: fetch-only\n",
+			[]git.StrategyOverride{{Pattern: "acme/api", Strategy: git.OverwriteExisting}, {Pattern: "acme/legacy-*", Strategy: git.FetchOnly}}, false},
+		{"dotted repository name", "clone:\n  strategy_overrides:\n    - repo: acme/site.github.io\n      strategy: sync\n",
+			[]git.StrategyOverride{{Pattern: "acme/site.github.io", Strategy: git.Sync}}, false},
+		{"invalid strategy", "clone:\n  strategy_overrides:\n    - repo: acme/api\n      strategy: merge\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := strategyOverrides(loadTestConfig(t, tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("strategyOverrides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("strategyOverrides() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("override %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	const file = `
+clone:
+  max_concurrent: 3
+  output_dir: /srv/from-config
+  connect_timeout: 30
+  operation_timeout: 120
+log:
+  level: warn
+`
+	tests := []struct {
+		name          string
+		args          []string
+		maxConcurrent int
+		outputDir     string
+		logLevel      string
+		connTimeout   time.Duration
+		cloneTimeout  time.Duration
+	}{
+		{
+			name:          "config file",
+			maxConcurrent: 3,
+			outputDir:     "/srv/from-config",
+			logLevel:      "warn",
+			connTimeout:   30 * time.Second,
+			cloneTimeout:  2 * time.Minute,
+		},
+		{
+			name:          "flags override the config file",
+			args:          []string{"--max-concurrent", "8", "--output-dir", "/srv/from-flag", "--log-level", "debug", "--connect-timeout", "5s", "--operation-timeout", "1m"},
+			maxConcurrent: 8,
+			outputDir:     "/srv/from-flag",
+			logLevel:      "debug",
+			connTimeout:   5 * time.Second,
+			cloneTimeout:  time.Minute,
+		},
+		{
+			name:          "flags override only what they set",
+			args:          []string{"--operation-timeout", "15m"},
+			maxConcurrent: 3,
+			outputDir:     "/srv/from-config",
+			logLevel:      "warn",
+			connTimeout:   30 * time.Second,
+			cloneTimeout:  15 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadTestConfig(t, file)
+			cmd := parseRootFlags(t, tt.args...)
+			applyFlagOverrides(cmd, cfg)
+
+			opts, err := configuredOptions(cmd, cfg)
+			if err != nil {
+				t.Fatalf("configuredOptions() error = %v", err)
+			}
+			if cfg.Clone.MaxConcurrent != tt.maxConcurrent {
+				t.Errorf("max concurrent = %d, want %d", cfg.Clone.MaxConcurrent, tt.maxConcurrent)
+			}
+			if cfg.Clone.OutputDir != tt.outputDir {
+				t.Errorf("output dir = %q, want %q", cfg.Clone.OutputDir, tt.outputDir)
+			}
+			if cfg.Log.Level != tt.logLevel {
+				t.Errorf("log level = %q, want %q", cfg.Log.Level, tt.logLevel)
+			}
+			if opts.ConnTimeout != tt.connTimeout {
+				t.Errorf("connect timeout = %v, want %v", opts.ConnTimeout, tt.connTimeout)
+			}
+			if opts.CloneTimeout != tt.cloneTimeout {
+				t.Errorf("clone timeout = %v, want %v", opts.CloneTimeout, tt.cloneTimeout)
+			}
+		})
+	}
+}
+
+func TestUpdateTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		args []string
+		want time.Duration
+	}{
+		{"unset", "", nil, 0},
+		{"config file", "clone:\n  update_timeout: 90\n", nil, 90 * time.Second},
+		{"flag over config file", "clone:\n  update_timeout: 90\n", []string{"--update-timeout", "5m"}, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadTestConfig(t, tt.file)
+			cmd := parseRootFlags(t, tt.args...)
+			applyFlagOverrides(cmd, cfg)
+
+			opts, err := configuredOptions(cmd, cfg)
+			if err != nil {
+				t.Fatalf("configuredOptions() error = %v", err)
+			}
+			if opts.UpdateTimeout != tt.want {
+				t.Errorf("update timeout = %v, want %v", opts.UpdateTimeout, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackupRetention(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		args []string
+		want int
+	}{
+		{"unset", "", nil, 3},
+		{"config file", "clone:\n  backup_retention: 10\n", nil, 10},
+		{"keep all", "clone:\n  backup_retention: 0\n", nil, 0},
+		{"flag over config file", "clone:\n  backup_retention: 10\n", []string{"--backup-retention", "1"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadTestConfig(t, tt.file)
+			cmd := parseRootFlags(t, tt.args...)
+			applyFlagOverrides(cmd, cfg)
+
+			opts, err := configuredOptions(cmd, cfg)
+			if err != nil {
+				t.Fatalf("configuredOptions() error = %v", err)
+			}
+			if opts.BackupRetention != tt.want {
+				t.Errorf("backup retention = %d, want %d", opts.BackupRetention, tt.want)
+			}
+		})
+	}
+}
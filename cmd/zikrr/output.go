@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// writeOutput writes v to w in the requested format (json or yaml)
+func writeOutput(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode json output: %w", err)
+		}
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		defer enc.Close()
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode yaml output: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q (expected json or yaml)", format)
+	}
+	return nil
+}
+
+// summaryFormat returns the summary output format from --output or the config (empty = none)
+func summaryFormat(cmd *cobra.Command, cfg *config.Config) string {
+	if format, _ := cmd.Flags().GetString("output"); format != "" {
+		return format
+	}
+	return cfg.Output.Format
+}
+
+// writeSummary writes the run report in the configured format to output.file, or stdout when unset
+func writeSummary(cmd *cobra.Command, cfg *config.Config, report runReport) error {
+	format := summaryFormat(cmd, cfg)
+	if cfg.Output.File == "" {
+		return writeOutput(os.Stdout, format, report)
+	}
+
+	f, err := os.Create(cfg.Output.File)
+	if err != nil {
+		return fmt.Errorf("failed to create summary file: %w", err)
+	}
+	defer f.Close()
+	return writeOutput(f, format, report)
+}
+
+// effectiveConfig returns the redacted configuration and flags that drove the run
+func effectiveConfig(cmd *cobra.Command) map[string]interface{} {
+	flags := make(map[string]string)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+	return config.Effective(flags)
+}
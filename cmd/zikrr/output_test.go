@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sachin-duhan/zikrr/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteOutputSummary(t *testing.T) {
+	summary := &git.CloneSummary{
+		Total:     2,
+		Succeeded: 1,
+		Failed:    1,
+		Failures:  []git.CloneFailure{{Repository: "acme/web", Error: "repository not found"}},
+		Repositories: []git.RepositoryResult{
+			{Organization: "acme", Name: "app", Status: "Success", Branch: "main", DurationSeconds: 1.5},
+			{Organization: "acme", Name: "web", Status: "Failed", Error: "repository not found", DurationSeconds: 0.25},
+		},
+	}
+	report := runReport{CloneSummary: summary, Config: map[string]interface{}{"dir": "/src"}}
+
+	tests := []struct {
+		format    string
+		unmarshal func([]byte, interface{}) error
+		// want are fragments of the encoded report, checking field names and inlining
+		want []string
+	}{
+		{"json", json.Unmarshal, []string{`"succeeded": 1`, `"duration_seconds": 1.5`, `"error": "repository not found"`, `"dir": "/src"`}},
+		{"yaml", yaml.Unmarshal, []string{"\nsucceeded: 1\n", "duration_seconds: 1.5", "error: repository not found", "dir: /src"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeOutput(&buf, tt.format, report); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("%s output missing %q:\n%s", tt.format, want, buf.String())
+				}
+			}
+
+			var decoded git.CloneSummary
+			if err := tt.unmarshal(buf.Bytes(), &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded.Total != 2 || decoded.Succeeded != 1 || decoded.Failed != 1 || len(decoded.Repositories) != 2 {
+				t.Errorf("decoded summary = %+v, want the written counts", decoded)
+			}
+			if got := decoded.Repositories[1]; got.Name != "web" || got.Status != "Failed" || got.Error != "repository not found" || got.DurationSeconds != 0.25 {
+				t.Errorf("decoded repository = %+v, want acme/web failed", got)
+			}
+		})
+	}
+
+	if err := writeOutput(&bytes.Buffer{}, "xml", report); err == nil || !strings.Contains(err.Error(), "unsupported output format") {
+		t.Errorf("writeOutput(xml) = %v, want an unsupported format error", err)
+	}
+}
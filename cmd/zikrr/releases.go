@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/git"
+	"github.com/sachin-duhan/zikrr/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// releaseConcurrency bounds the release lookups in flight
+const releaseConcurrency = 5
+
+// runReport is the machine-readable report of a run: the clone summary, the
+// optional latest release of each listed repository and the effective configuration
+type runReport struct {
+	*git.CloneSummary `yaml:",inline"`
+	Releases          []github.ReleaseInfo   `json:"releases,omitempty" yaml:"releases,omitempty"`
+	Config            map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// collectReleases looks up the latest releases of repos when --with-releases is set
+func collectReleases(ctx context.Context, cmd *cobra.Command, client *github.Client, repos []*gh.Repository) []github.ReleaseInfo {
+	if withReleases, _ := cmd.Flags().GetBool("with-releases"); !withReleases || len(repos) == 0 {
+		return nil
+	}
+	return client.LatestReleases(ctx, repos, releaseConcurrency)
+}
+
+// printReleases prints the latest release of each repository
+func printReleases(releases []github.ReleaseInfo) {
+	if len(releases) == 0 {
+		return
+	}
+	fmt.Println("Latest releases:")
+	for _, r := range releases {
+		switch {
+		case r.Error != "":
+			fmt.Printf("  ! %s: %s\n", r.Repository, r.Error)
+		case !r.HasRelease():
+			fmt.Printf("  - %s: no releases\n", r.Repository)
+		case r.PublishedAt != nil:
+			fmt.Printf("  %s: %s (%s)\n", r.Repository, r.Tag, r.PublishedAt.Format("2006-01-02"))
+		default:
+			fmt.Printf("  %s: %s\n", r.Repository, r.Tag)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/sachin-duhan/zikrr/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var scanNamesCmd = &cobra.Command{
+	Use:   "scan-names",
+	Short: "Report repositories that violate a naming convention",
+	Long: `Scan all repositories of an organization and report those whose names do not
+match the given regular expression. No repositories are cloned.`,
+	RunE: runScanNames,
+}
+
+func init() {
+	scanNamesCmd.Flags().StringP("pattern", "p", "", "regular expression repository names must match (e.g. ^[a-z]+-[a-z]+$)")
+	rootCmd.AddCommand(scanNamesCmd)
+}
+
+func runScanNames(cmd *cobra.Command, args []string) error {
+	patternValue, _ := cmd.Flags().GetString("pattern")
+	if patternValue == "" {
+		return fmt.Errorf("naming pattern not provided. Use --pattern flag")
+	}
+	pattern, err := regexp.Compile(patternValue)
+	if err != nil {
+		return fmt.Errorf("invalid naming pattern %q: %w", patternValue, err)
+	}
+
+	orgs := organizations(cmd)
+	if len(orgs) == 0 {
+		return fmt.Errorf("organization not provided. Use --org flag")
+	}
+	if len(orgs) > 1 {
+		return fmt.Errorf("scan-names checks a single organization, got %d", len(orgs))
+	}
+	org := orgs[0]
+
+	ctx, _, client, err := setup(cmd)
+	if err != nil {
+		return err
+	}
+
+	repos, err := client.ListFilteredRepositories(ctx, org, nil)
+	if err != nil {
+		return err
+	}
+
+	report := github.CheckNamingConvention(org, repos, pattern)
+
+	if format, _ := cmd.Flags().GetString("output"); format != "" {
+		return writeOutput(os.Stdout, format, report)
+	}
+
+	fmt.Printf("%d/%d repositories in %s match %s\n", report.Compliant, report.Total, org, report.Pattern)
+	for _, v := range report.Violations {
+		fmt.Printf("  ✗ %s\n", v.Repository)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	gh "github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// tagClonedRepositories adds topic to every cloned repository after confirmation.
+// It writes to GitHub, so failures are reported but do not fail the run.
+func tagClonedRepositories(ctx context.Context, client *github.Client, repos []*gh.Repository, topic string) {
+	if len(repos) == 0 {
+		return
+	}
+	if !confirm(fmt.Sprintf("Add topic %q to %d repositories on GitHub?", topic, len(repos))) {
+		fmt.Println("Skipping topic tagging")
+		return
+	}
+
+	tagged := 0
+	for _, repo := range repos {
+		if err := client.AddTopic(ctx, repo, topic); err != nil {
+			util.Warn(err.Error())
+			continue
+		}
+		tagged++
+	}
+	fmt.Printf("Tagged %d/%d repositories with %q\n", tagged, len(repos), topic)
+}
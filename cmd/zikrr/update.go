@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/sachin-duhan/zikrr/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var checkUpdatesCmd = &cobra.Command{
+	Use:   "check-updates",
+	Short: "Show how far behind local clones are and choose which to update",
+	Long: `Fetch every repository previously cloned under --dir (clone.output_dir by
+default) without modifying the working trees, report how many commits each is
+behind its remote, and interactively choose which repositories to update.
+Clones are found in the nested <org>/<repo> layout only.`,
+	RunE: runCheckUpdates,
+}
+
+func init() {
+	checkUpdatesCmd.Flags().StringP("dir", "d", "", "base directory containing cloned repositories in the nested <org>/<repo> layout (default clone.output_dir, or the current directory)")
+	checkUpdatesCmd.Flags().IntP("concurrency", "j", 0, "maximum number of concurrent updates (default clone.max_concurrent)")
+	rootCmd.AddCommand(checkUpdatesCmd)
+}
+
+func runCheckUpdates(cmd *cobra.Command, args []string) error {
+	ctx, cfg, client, err := setup(cmd)
+	if err != nil {
+		return err
+	}
+	if dir, _ := cmd.Flags().GetString("dir"); dir != "" {
+		cfg.Clone.OutputDir = dir
+	}
+	if cmd.Flags().Changed("concurrency") {
+		cfg.Clone.MaxConcurrent, _ = cmd.Flags().GetInt("concurrency")
+	}
+	if err := checkNestedLayout(cmd, cfg); err != nil {
+		return err
+	}
+
+	opts, err := prepareOptions(cmd, cfg, client)
+	if err != nil {
+		return err
+	}
+	manager, closeManager, err := newManager(cmd, cfg, client, opts)
+	if err != nil {
+		return err
+	}
+	defer closeManager()
+
+	baseDir := manager.BaseDir()
+	repos, err := git.FindLocalRepositories(ctx, baseDir)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		fmt.Printf("No repositories found under %s\n", baseDir)
+		return nil
+	}
+
+	// Read-only fetch phase
+	var behind []git.LocalRepository
+	for _, repo := range repos {
+		fetchOpts := opts
+		fetchOpts.TargetDir = repo.Dir
+		if err := git.FetchRemote(ctx, fetchOpts); err != nil {
+			fmt.Printf("  ✗ %s/%s: %v\n", repo.Organization, repo.Name, err)
+			continue
+		}
+		count, err := git.BehindCount(ctx, repo.Dir, "")
+		if err != nil {
+			fmt.Printf("  ✗ %s/%s: %v\n", repo.Organization, repo.Name, err)
+			continue
+		}
+		if count == 0 {
+			continue
+		}
+		behind = append(behind, repo)
+		fmt.Printf("  %d) %s/%s is %d commits behind\n", len(behind), repo.Organization, repo.Name, count)
+	}
+
+	if len(behind) == 0 {
+		fmt.Println("All repositories are up to date")
+		return nil
+	}
+
+	fmt.Print("Update which repositories? (all, none, or comma-separated numbers): ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	selected, err := parseSelection(strings.TrimSpace(line), len(behind))
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	for _, i := range selected {
+		repo := behind[i]
+		manager.AddRepository(repo.Organization, repo.Name, repo.URL, "", git.FetchOnly)
+	}
+
+	failed := 0
+	for repo := range manager.CloneAll(ctx) {
+		if status, err, _ := repo.GetStatus(); status == git.StatusFailed {
+			failed++
+			fmt.Printf("  ✗ %s/%s: %v\n", repo.Organization, repo.Name, err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d repositories failed to update", failed)
+	}
+	fmt.Printf("Updated %d repositories\n", len(selected))
+	return nil
+}
+
+// checkNestedLayout rejects settings that place clones anywhere but
+// <dir>/<org>/<repo>, the only layout FindLocalRepositories scans
+func checkNestedLayout(cmd *cobra.Command, cfg *config.Config) error {
+	layout, err := git.ParseLayout(cfg.Clone.Layout)
+	if err != nil {
+		return err
+	}
+	if layout != git.LayoutNested {
+		return fmt.Errorf("check-updates only supports the nested layout, but clone.layout is %q", cfg.Clone.Layout)
+	}
+	if cfg.Clone.PathTemplate != "" {
+		return fmt.Errorf("check-updates only supports the nested layout, but clone.path_template is set")
+	}
+	if orgDirs, _ := cmd.Flags().GetStringToString("org-dir"); len(orgDirs) > 0 {
+		return fmt.Errorf("check-updates only supports the nested layout, but --org-dir is set")
+	}
+	return nil
+}
+
+// parseSelection parses a selection answer into zero-based indexes
+func parseSelection(answer string, total int) ([]int, error) {
+	switch strings.ToLower(answer) {
+	case "", "none", "n":
+		return nil, nil
+	case "all", "a":
+		indexes := make([]int, total)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	var indexes []int
+	for _, part := range strings.Split(answer, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 || n > total {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		indexes = append(indexes, n-1)
+	}
+	return indexes, nil
+}
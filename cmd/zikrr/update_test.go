@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckNestedLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		config  string
+		wantErr string
+	}{
+		{"default", nil, "", ""},
+		{"explicit nested", nil, "clone:\n  layout: nested\n", ""},
+		{"flat layout", nil, "clone:\n  layout: flat\n", `clone.layout is "flat"`},
+		{"unknown layout", nil, "clone:\n  layout: sideways\n", "unknown layout"},
+		{"path template", nil, "clone:\n  path_template: \"{{.Repo}}\"\n", "clone.path_template is set"},
+		{"org dir", []string{"--org-dir", "acme=/srv/acme"}, "", "--org-dir is set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := parseRootFlags(t, tt.args...)
+			cfg := loadTestConfig(t, tt.config)
+			err := checkNestedLayout(cmd, cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkNestedLayout() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkNestedLayout() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/internal/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// Version is the zikrr version, overridden at build time via -ldflags
+var Version = "0.1.0"
+
+const (
+	releaseOwner       = "sachin-duhan"
+	releaseRepo        = "zikrr"
+	versionCheckMaxAge = 24 * time.Hour
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the zikrr version",
+	RunE:  runVersion,
+}
+
+func init() {
+	versionCmd.Flags().Bool("check", false, "check GitHub releases for a newer version")
+	rootCmd.AddCommand(versionCmd)
+}
+
+// versionCheck is the cached result of the latest release lookup
+type versionCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+	URL       string    `json:"url"`
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	fmt.Printf("zikrr %s\n", Version)
+
+	if check, _ := cmd.Flags().GetBool("check"); !check {
+		return nil
+	}
+
+	result, err := latestVersion(cmd)
+	if err != nil {
+		// Being offline or rate limited should not make the version command fail
+		fmt.Printf("Unable to check for updates: %v\n", err)
+		return nil
+	}
+
+	cmp, err := util.CompareVersions(Version, result.Latest)
+	if err != nil {
+		fmt.Printf("Unable to compare versions: %v\n", err)
+		return nil
+	}
+	if cmp < 0 {
+		fmt.Printf("A newer version is available: %s\n%s\n", result.Latest, result.URL)
+	} else {
+		fmt.Println("You are running the latest version")
+	}
+	return nil
+}
+
+// latestVersion returns the latest release, using a cached lookup when it is recent enough
+func latestVersion(cmd *cobra.Command) (*versionCheck, error) {
+	cachePath := ""
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		cachePath = filepath.Join(cacheDir, "zikrr", "version-check.json")
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var cached versionCheck
+			if json.Unmarshal(data, &cached) == nil && time.Since(cached.CheckedAt) < versionCheckMaxAge {
+				return &cached, nil
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var client *github.Client
+	if token, _ := cmd.Flags().GetString("token"); token != "" || auth.GetTokenFromEnv() != "" {
+		var err error
+		if _, _, client, err = setup(cmd); err != nil {
+			return nil, err
+		}
+	} else {
+		client = github.NewUnauthenticatedClient()
+	}
+
+	release, err := client.GetLatestRelease(ctx, releaseOwner, releaseRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &versionCheck{
+		CheckedAt: time.Now(),
+		Latest:    release.GetTagName(),
+		URL:       release.GetHTMLURL(),
+	}
+	if cachePath != "" {
+		if data, err := json.Marshal(result); err == nil {
+			if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+				_ = os.WriteFile(cachePath, data, 0644)
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// expirationHeader carries the expiry of tokens that have one, e.g. "2026-04-02 17:25:10 UTC"
+const expirationHeader = "GitHub-Authentication-Token-Expiration"
+
+// expirationLayouts are the formats GitHub uses for the expiration header
+var expirationLayouts = []string{
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+}
+
+// ExpiryWarningWindow is how close to expiry a token must be to warn about it
+const ExpiryWarningWindow = 24 * time.Hour
+
+// ParseTokenExpiration parses an expiration header value. An empty value, for
+// a token that never expires, yields nil.
+func ParseTokenExpiration(value string) (*github.Timestamp, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range expirationLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &github.Timestamp{Time: t}, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognized token expiration %q", value)
+}
+
+// ExpiresWithin reports whether the token expires within d from now
+func (t *Token) ExpiresWithin(d time.Duration) bool {
+	return t.ExpiresAt != nil && time.Until(t.ExpiresAt.Time) < d
+}
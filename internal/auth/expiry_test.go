@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestParseTokenExpiration(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    time.Time
+		wantErr bool
+	}{
+		{"absent", "", time.Time{}, false},
+		{"utc", "2026-04-02 17:25:10 UTC", time.Date(2026, 4, 2, 17, 25, 10, 0, time.UTC), false},
+		{"offset", "2026-04-02 19:25:10 +0200", time.Date(2026, 4, 2, 17, 25, 10, 0, time.UTC), false},
+		{"padded", " 2026-04-02 17:25:10 UTC ", time.Date(2026, 4, 2, 17, 25, 10, 0, time.UTC), false},
+		{"garbage", "next tuesday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTokenExpiration(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTokenExpiration(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			}
+			if tt.want.IsZero() {
+				if got != nil {
+					t.Errorf("ParseTokenExpiration(%q) = %v, want nil", tt.header, got)
+				}
+				return
+			}
+			if got == nil || !got.Time.Equal(tt.want) {
+				t.Errorf("ParseTokenExpiration(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpiresWithin(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		never     bool
+		want      bool
+	}{
+		{"never expires", 0, true, false},
+		{"expires in an hour", time.Hour, false, true},
+		{"expires in a week", 7 * 24 * time.Hour, false, false},
+		{"already expired", -time.Hour, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &Token{}
+			if !tt.never {
+				token.ExpiresAt = &github.Timestamp{Time: time.Now().Add(tt.expiresIn)}
+			}
+			if got := token.ExpiresWithin(ExpiryWarningWindow); got != tt.want {
+				t.Errorf("ExpiresWithin(%v) = %v, want %v", ExpiryWarningWindow, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTokenExpiration(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Time
+	}{
+		{"expiring token", "2026-04-02 17:25:10 UTC", time.Date(2026, 4, 2, 17, 25, 10, 0, time.UTC)},
+		{"no header", "", time.Time{}},
+		{"unparseable header", "soon", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("GitHub-Authentication-Token-Expiration", tt.header)
+				}
+				w.Write([]byte(`{"login":"octocat"}`))
+			}))
+			t.Cleanup(server.Close)
+
+			// An unparseable expiry is logged; the token is still valid
+			token, err := ValidateToken(context.Background(), "test-token", server.URL, "")
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+			if tt.want.IsZero() {
+				if token.ExpiresAt != nil {
+					t.Errorf("ExpiresAt = %v, want nil", token.ExpiresAt)
+				}
+				return
+			}
+			if token.ExpiresAt == nil || !token.ExpiresAt.Time.Equal(tt.want) {
+				t.Errorf("ExpiresAt = %v, want %v", token.ExpiresAt, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ParseProxyURL validates a proxy URL such as http://proxy.example.com:3128. An
+// empty value (use HTTPS_PROXY/HTTP_PROXY from the environment) is returned unchanged.
+func ParseProxyURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return "", fmt.Errorf("invalid proxy URL %q: expected http, https or socks5 scheme", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid proxy URL %q: missing host", raw)
+	}
+	return raw, nil
+}
+
+// proxyFunc selects the proxy for each request: proxy when set, otherwise
+// HTTPS_PROXY/HTTP_PROXY. NO_PROXY from the environment applies to both.
+func proxyFunc(proxy string) func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	if proxy != "" {
+		config.HTTPProxy = proxy
+		config.HTTPSProxy = proxy
+	}
+	selectProxy := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return selectProxy(req.URL)
+	}
+}
+
+// newHTTPClient returns an HTTP client for the GitHub API that routes requests through proxyFunc(proxy)
+func newHTTPClient(proxy string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc(proxy)
+	return &http.Client{Transport: transport}
+}
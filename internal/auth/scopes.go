@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// scopesHeader lists the OAuth scopes of a classic token on every API response
+const scopesHeader = "X-OAuth-Scopes"
+
+// repoScope grants classic tokens access to private repositories
+const repoScope = "repo"
+
+// ParseScopes splits an X-OAuth-Scopes header value such as "repo, read:org"
+func ParseScopes(value string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(value, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// HasScope reports whether a classic token was granted the scope
+func (t *Token) HasScope(scope string) bool {
+	for _, granted := range t.Scopes {
+		if strings.EqualFold(granted, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRepoScope reports whether the token can read private repositories according
+// to its scopes. Fine-grained tokens have no scopes; their repository access is
+// only known per repository, so they are assumed to have it.
+func (t *Token) HasRepoScope() bool {
+	return t.Type == TokenTypeFineGrained || t.HasScope(repoScope)
+}
+
+// AccessWarnings checks that the token can list and read the organization's
+// repositories, combining its scopes, CheckOrganizationAccess and a read of one
+// repository. It returns a message for each likely problem.
+func (t *Token) AccessWarnings(ctx context.Context, orgName string) ([]string, error) {
+	var warnings []string
+	if !t.HasRepoScope() {
+		warnings = append(warnings, "token lacks the repo scope, so private repositories will be missing or fail to clone")
+	}
+
+	ok, err := t.CheckOrganizationAccess(ctx, orgName)
+	if err != nil {
+		return warnings, err
+	}
+	if !ok {
+		return append(warnings, fmt.Sprintf("token cannot access organization %s", orgName)), nil
+	}
+
+	repos, _, err := t.Client.Repositories.ListByOrg(ctx, orgName, &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return warnings, fmt.Errorf("error listing repositories of %s: %w", orgName, err)
+	}
+	if len(repos) == 0 {
+		log.Printf("[DEBUG] No repositories of %s visible to sample", orgName)
+		return warnings, nil
+	}
+
+	status, err := t.ClassifyRepositoryAccess(ctx, orgName, repos[0].GetName())
+	if err != nil {
+		return warnings, err
+	}
+	if status != AccessGranted {
+		warnings = append(warnings, fmt.Sprintf("token cannot read %s (%s)", repos[0].GetFullName(), status))
+	}
+	return warnings, nil
+}
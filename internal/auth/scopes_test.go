@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", nil},
+		{"repo", []string{"repo"}},
+		{"repo, read:org", []string{"repo", "read:org"}},
+		{" public_repo ,, workflow ", []string{"public_repo", "workflow"}},
+	}
+	for _, tt := range tests {
+		if got := ParseScopes(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseScopes(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestHasRepoScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenType TokenType
+		header    string
+		want      bool
+	}{
+		{"classic with repo", TokenTypeClassic, "read:org, repo", true},
+		{"classic with repo in capitals", TokenTypeClassic, "REPO", true},
+		{"classic with public_repo only", TokenTypeClassic, "public_repo, read:org", false},
+		{"classic without scopes", TokenTypeClassic, "", false},
+		{"fine-grained", TokenTypeFineGrained, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &Token{Type: tt.tokenType, Scopes: ParseScopes(tt.header)}
+			if got := token.HasRepoScope(); got != tt.want {
+				t.Errorf("HasRepoScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTokenScopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	token, err := ValidateToken(context.Background(), "test-token", server.URL, "")
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if want := []string{"repo", "read:org"}; !reflect.DeepEqual(token.Scopes, want) {
+		t.Errorf("Scopes = %q, want %q", token.Scopes, want)
+	}
+}
+
+func TestAccessWarnings(t *testing.T) {
+	token := newTestToken(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/acme", "/orgs/empty", "/orgs/locked":
+			w.Write([]byte(`{"login":"` + strings.TrimPrefix(r.URL.Path, "/orgs/") + `"}`))
+		case "/orgs/acme/repos":
+			w.Write([]byte(`[{"name":"app","full_name":"acme/app"}]`))
+		case "/orgs/empty/repos":
+			w.Write([]byte(`[]`))
+		case "/orgs/locked/repos":
+			w.Write([]byte(`[{"name":"secret","full_name":"locked/secret"}]`))
+		case "/repos/acme/app":
+			w.Write([]byte(`{"name":"app"}`))
+		case "/repos/locked/secret":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Resource not accessible by personal access token"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	tests := []struct {
+		name   string
+		org    string
+		scopes string
+		want   []string
+	}{
+		{"everything readable", "acme", "repo", nil},
+		{"missing repo scope", "acme", "read:org", []string{"lacks the repo scope"}},
+		{"no repositories to sample", "empty", "repo", nil},
+		{"organization not visible", "missing", "repo", []string{"cannot access organization missing"}},
+		{"sample repository forbidden", "locked", "repo", []string{"cannot read locked/secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token.Scopes = ParseScopes(tt.scopes)
+			warnings, err := token.AccessWarnings(context.Background(), tt.org)
+			if err != nil {
+				t.Fatalf("AccessWarnings() error = %v", err)
+			}
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("AccessWarnings() = %q, want %d warnings", warnings, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warning %q does not mention %q", warnings[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// ssoHeader is set by GitHub when a token must be authorized for an organization's SAML SSO
+const ssoHeader = "X-GitHub-SSO"
+
+// SSOError indicates the token needs SAML SSO authorization before it can access an organization
+type SSOError struct {
+	URL string
+	Err error
+}
+
+func (e *SSOError) Error() string {
+	if e.URL == "" {
+		return "token requires SSO authorization for this organization"
+	}
+	return fmt.Sprintf("token requires SSO authorization, authorize it at %s", e.URL)
+}
+
+func (e *SSOError) Unwrap() error {
+	return e.Err
+}
+
+// ParseSSOHeader extracts the authorization URL from an X-GitHub-SSO header
+// value such as "required; url=https://github.com/orgs/acme/sso?authorization_request=...".
+// It reports false when the header does not indicate required SSO.
+func ParseSSOHeader(value string) (string, bool) {
+	parts := strings.Split(value, ";")
+	if len(parts) == 0 || strings.TrimSpace(parts[0]) != "required" {
+		return "", false
+	}
+	for _, part := range parts[1:] {
+		if url, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			return url, true
+		}
+	}
+	return "", true
+}
+
+// ssoOutputURL matches the authorization URL git prints when SAML SSO blocks a transfer
+var ssoOutputURL = regexp.MustCompile(`https://\S+/sso\?authorization_request=[^\s'"]+`)
+
+// ParseSSOOutput extracts the authorization URL from git output such as
+// "remote: The 'acme' organization has enabled or enforced SAML SSO. To access
+// remote: this repository, visit https://github.com/orgs/acme/sso?authorization_request=...".
+// It reports false when the output does not mention SAML SSO.
+func ParseSSOOutput(output string) (string, bool) {
+	if !strings.Contains(output, "SAML SSO") {
+		return "", false
+	}
+	return ssoOutputURL.FindString(output), true
+}
+
+// CheckSSO converts an API error into an *SSOError when the response indicates
+// SSO authorization is required, returning err unchanged otherwise
+func CheckSSO(resp *github.Response, err error) error {
+	if err == nil || resp == nil {
+		return err
+	}
+	if url, ok := ParseSSOHeader(resp.Header.Get(ssoHeader)); ok {
+		return &SSOError{URL: url, Err: err}
+	}
+	return err
+}
+
+// IsSSOError reports whether err requires SSO authorization, returning the authorization URL
+func IsSSOError(err error) (string, bool) {
+	var sso *SSOError
+	if errors.As(err, &sso) {
+		return sso.URL, true
+	}
+	return "", false
+}
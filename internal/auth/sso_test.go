@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestParseSSOHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		url   string
+		ok    bool
+	}{
+		{"required with url", "required; url=https://github.com/orgs/acme/sso?authorization_request=abc", "https://github.com/orgs/acme/sso?authorization_request=abc", true},
+		{"required without url", "required", "", true},
+		{"partial results", "partial-results; organizations=123,456", "", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, ok := ParseSSOHeader(tt.value)
+			if url != tt.url || ok != tt.ok {
+				t.Errorf("ParseSSOHeader(%q) = %q, %v, want %q, %v", tt.value, url, ok, tt.url, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseSSOOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		url    string
+		ok     bool
+	}{
+		{
+			name: "git over https",
+			output: "remote: The 'acme' organization has enabled or enforced SAML SSO. To access\n" +
+				"remote: this repository, visit https://github.com/orgs/acme/sso?authorization_request=AbC-1 and try your request again.\n" +
+				"fatal: unable to access 'https://github.com/acme/app.git/': The requested URL returned error: 403\n",
+			url: "https://github.com/orgs/acme/sso?authorization_request=AbC-1",
+			ok:  true,
+		},
+		{
+			name:   "go-git response body",
+			output: "authorization failed: The 'acme' organization has enabled or enforced SAML SSO. To access this repository, you must re-authorize the OAuth Application.",
+			ok:     true,
+		},
+		{
+			name:   "other failure",
+			output: "fatal: repository 'https://github.com/acme/app.git/' not found\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, ok := ParseSSOOutput(tt.output)
+			if url != tt.url || ok != tt.ok {
+				t.Errorf("ParseSSOOutput() = %q, %v, want %q, %v", url, ok, tt.url, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheckSSO(t *testing.T) {
+	apiErr := errors.New("403 Resource protected by organization SAML enforcement")
+	response := func(header string) *github.Response {
+		resp := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}}
+		if header != "" {
+			resp.Header.Set(ssoHeader, header)
+		}
+		return &github.Response{Response: resp}
+	}
+
+	tests := []struct {
+		name string
+		resp *github.Response
+		err  error
+		url  string
+		sso  bool
+	}{
+		{"sso required", response("required; url=https://github.com/orgs/acme/sso?authorization_request=abc"), apiErr, "https://github.com/orgs/acme/sso?authorization_request=abc", true},
+		{"forbidden without header", response(""), apiErr, "", false},
+		{"no response", nil, apiErr, "", false},
+		{"no error", response("required; url=https://github.com/orgs/acme/sso"), nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSSO(tt.resp, tt.err)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("CheckSSO() = %v, want it to wrap %v", err, tt.err)
+			}
+			url, sso := IsSSOError(err)
+			if url != tt.url || sso != tt.sso {
+				t.Errorf("IsSSOError(CheckSSO()) = %q, %v, want %q, %v", url, sso, tt.url, tt.sso)
+			}
+		})
+	}
+}
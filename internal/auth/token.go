@@ -2,9 +2,13 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/google/go-github/v60/github"
 	"golang.org/x/oauth2"
@@ -26,17 +30,79 @@ type Token struct {
 	Type      TokenType
 	ExpiresAt *github.Timestamp
 	Client    *github.Client
+	// BaseURL is the GitHub Enterprise Server URL the token belongs to ("" = github.com)
+	BaseURL string
+	// Login is the user the token authenticates as
+	Login string
+	// Scopes are the OAuth scopes of a classic token (empty for fine-grained tokens)
+	Scopes []string
+	// Proxy is the proxy API requests go through ("" = HTTPS_PROXY from the environment)
+	Proxy string
 }
 
-// ValidateToken validates the GitHub token and returns its metadata
-func ValidateToken(ctx context.Context, tokenValue string) (*Token, error) {
+// defaultWebHost is the host serving github.com repositories
+const defaultWebHost = "github.com"
+
+// ParseBaseURL validates a GitHub Enterprise Server URL such as
+// https://github.example.com, returning it without a trailing slash. An empty
+// value (github.com) is returned unchanged.
+func ParseBaseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid GitHub URL %q: %w", raw, err)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("invalid GitHub URL %q: expected http(s)://host", raw)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid GitHub URL %q: must not contain credentials, a query or a fragment", raw)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// WebHost returns the host serving repositories for a base URL from ParseBaseURL
+func WebHost(baseURL string) string {
+	if baseURL == "" {
+		return defaultWebHost
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return defaultWebHost
+	}
+	return u.Host
+}
+
+// newAPIClient creates a GitHub API client for baseURL ("" = api.github.com)
+func newAPIClient(httpClient *http.Client, baseURL string) (*github.Client, error) {
+	client := github.NewClient(httpClient)
+	if baseURL == "" {
+		return client, nil
+	}
+	client, err := client.WithEnterpriseURLs(baseURL, baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub URL %q: %w", baseURL, err)
+	}
+	return client, nil
+}
+
+// ValidateToken validates the GitHub token against the API at baseURL
+// ("" = api.github.com), reached through proxy, and returns its metadata
+func ValidateToken(ctx context.Context, tokenValue, baseURL, proxy string) (*Token, error) {
 	log.Printf("[DEBUG] Validating GitHub token")
 	if tokenValue == "" {
 		return nil, fmt.Errorf("token cannot be empty")
 	}
 
 	// Create GitHub client
-	client := github.NewClient(nil).WithAuthToken(tokenValue)
+	client, err := newAPIClient(newHTTPClient(proxy), baseURL)
+	if err != nil {
+		return nil, err
+	}
+	client = client.WithAuthToken(tokenValue)
 
 	// Get authenticated user to validate token
 	user, resp, err := client.Users.Get(ctx, "")
@@ -59,10 +125,20 @@ func ValidateToken(ctx context.Context, tokenValue string) (*Token, error) {
 		log.Printf("[DEBUG] Detected classic token")
 	}
 
+	expiresAt, err := ParseTokenExpiration(resp.Header.Get(expirationHeader))
+	if err != nil {
+		log.Printf("[WARN] %v", err)
+	}
+
 	return &Token{
-		Value:  tokenValue,
-		Type:   tokenType,
-		Client: client,
+		Value:     tokenValue,
+		Type:      tokenType,
+		ExpiresAt: expiresAt,
+		Client:    client,
+		BaseURL:   baseURL,
+		Login:     user.GetLogin(),
+		Scopes:    ParseScopes(resp.Header.Get(scopesHeader)),
+		Proxy:     proxy,
 	}, nil
 }
 
@@ -87,6 +163,54 @@ func (t *Token) CheckOrganizationAccess(ctx context.Context, orgName string) (bo
 	return false, nil
 }
 
+// AccessStatus classifies a token's access to a repository
+type AccessStatus string
+
+const (
+	// AccessGranted means the repository can be read
+	AccessGranted AccessStatus = "accessible"
+	// AccessNotFound means the repository does not exist or is hidden from the token
+	AccessNotFound AccessStatus = "not-found"
+	// AccessForbidden means the repository exists but the token may not read it
+	AccessForbidden AccessStatus = "forbidden"
+)
+
+// ClassifyRepositoryAccess reports whether the token can read a repository, distinguishing missing from forbidden
+func (t *Token) ClassifyRepositoryAccess(ctx context.Context, orgName, repoName string) (AccessStatus, error) {
+	_, resp, err := t.Client.Repositories.Get(ctx, orgName, repoName)
+	return ClassifyAccess(resp, err)
+}
+
+// ClassifyAccess classifies the response to a repository request. A 403 caused
+// by a rate limit says nothing about access and is returned as an error.
+func ClassifyAccess(resp *github.Response, err error) (AccessStatus, error) {
+	if err == nil {
+		return AccessGranted, nil
+	}
+	if isRateLimited(resp, err) {
+		return "", fmt.Errorf("rate limited while checking repository access: %w", err)
+	}
+	if resp != nil {
+		switch resp.StatusCode {
+		case 404:
+			return AccessNotFound, nil
+		case 401, 403, 451:
+			return AccessForbidden, nil
+		}
+	}
+	return "", fmt.Errorf("error checking repository access: %w", err)
+}
+
+// isRateLimited reports whether a failed request hit the primary or secondary rate limit
+func isRateLimited(resp *github.Response, err error) bool {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateErr) || errors.As(err, &abuseErr) {
+		return true
+	}
+	return resp != nil && resp.Header.Get("Retry-After") != ""
+}
+
 // CheckRepositoryAccess checks if the token has access to a specific repository in an organization
 func (t *Token) CheckRepositoryAccess(ctx context.Context, orgName, repoName string) (bool, error) {
 	log.Printf("[DEBUG] Checking access to repository: %s/%s", orgName, repoName)
@@ -129,6 +253,12 @@ func CreateGitHubClient(ctx context.Context, token *Token) *github.Client {
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token.Value},
 	)
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc)
+	tc := oauth2.NewClient(context.WithValue(ctx, oauth2.HTTPClient, newHTTPClient(token.Proxy)), ts)
+	client, err := newAPIClient(tc, token.BaseURL)
+	if err != nil {
+		// BaseURL was already accepted by ValidateToken, so this is not expected
+		log.Printf("[ERROR] %v, falling back to github.com", err)
+		return github.NewClient(tc)
+	}
+	return client
 }
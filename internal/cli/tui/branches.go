@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// branchPicker is the sub-view choosing the branch to clone for one repository
+type branchPicker struct {
+	repo     *github.Repository
+	branches []string
+	cursor   int
+	loading  bool
+	err      error
+}
+
+// indexOf returns the position of the selected branch, falling back to the default branch
+func (p *branchPicker) indexOf(selected, defaultBranch string) int {
+	if selected == "" {
+		selected = defaultBranch
+	}
+	for i, branch := range p.branches {
+		if branch == selected {
+			return i
+		}
+	}
+	return 0
+}
+
+// branchesMsg carries the branches fetched for a repository
+type branchesMsg struct {
+	repo     string
+	branches []string
+	err      error
+}
+
+// fetchBranches is a command that lists the branches of a repository, default branch first
+func (m Model) fetchBranches(repo *github.Repository) tea.Cmd {
+	return func() tea.Msg {
+		branches, err := m.client.ListBranches(m.ctx, repo.GetOwner().GetLogin(), repo.GetName(), &github.BranchListOptions{
+			ListOptions: github.ListOptions{PerPage: m.client.PageSize()},
+		})
+		if err != nil {
+			return branchesMsg{repo: repo.GetFullName(), err: err}
+		}
+
+		names := []string{repo.GetDefaultBranch()}
+		for _, branch := range branches {
+			if branch.GetName() != repo.GetDefaultBranch() {
+				names = append(names, branch.GetName())
+			}
+		}
+		return branchesMsg{repo: repo.GetFullName(), branches: names}
+	}
+}
+
+// updateBranchPicker handles keys while the branch picker is open
+func (m Model) updateBranchPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	picker := m.repositories.picker
+	switch msg.String() {
+	case "up", "k":
+		if picker.cursor > 0 {
+			picker.cursor--
+		}
+	case "down", "j":
+		if picker.cursor < len(picker.branches)-1 {
+			picker.cursor++
+		}
+	case "enter":
+		if !picker.loading && picker.cursor < len(picker.branches) {
+			m.repositories.SetBranch(picker.repo, picker.branches[picker.cursor])
+		}
+		m.repositories.picker = nil
+	case "esc":
+		m.repositories.picker = nil
+	}
+	return m, nil
+}
+
+// branchPickerView renders the branch picker
+func (m Model) branchPickerView() string {
+	picker := m.repositories.picker
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s - Choose Branch", picker.repo.GetFullName())))
+	b.WriteString("\n\n")
+
+	switch {
+	case picker.loading:
+		b.WriteString("Loading branches...\n")
+	case picker.err != nil:
+		b.WriteString(errorStyle.Render(picker.err.Error()))
+		b.WriteString("\n")
+	default:
+		for i, branch := range picker.branches {
+			cursor := " "
+			if picker.cursor == i {
+				cursor = ">"
+			}
+			line := fmt.Sprintf("%s %s", cursor, branch)
+			if branch == picker.repo.GetDefaultBranch() {
+				line += " (default)"
+			}
+			if picker.cursor == i {
+				line = cursorStyle.Render(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(infoStyle.Render("↑/k, ↓/j: Navigate  Enter: Select  Esc: Cancel"))
+	b.WriteString(m.rateLimitFooter())
+	return b.String()
+}
+
+// resolvedBranchesMsg carries the branch to clone per selected repository (full name)
+type resolvedBranchesMsg struct {
+	branches map[string]string
+}
+
+// resolveBranches is a command applying the branch fallbacks to each selected
+// repository, starting from the branch picked for it. A repository whose
+// branches cannot be listed keeps the picked branch.
+func (m Model) resolveBranches() tea.Cmd {
+	type pick struct {
+		repo   *github.Repository
+		branch string
+	}
+	var picks []pick
+	for _, repo := range m.repositories.repositories {
+		if m.repositories.selectedRepos[repo.GetFullName()] {
+			picks = append(picks, pick{repo, m.repositories.BranchFor(repo)})
+		}
+	}
+
+	fallbacks := m.branchFallbacks
+	return func() tea.Msg {
+		branches := make(map[string]string, len(picks))
+		for _, p := range picks {
+			branch, err := m.client.ResolveBranch(m.ctx, p.repo, p.branch, fallbacks)
+			if err != nil {
+				util.Warn(fmt.Sprintf("Cloning %s without branch fallbacks: %v", p.repo.GetFullName(), err))
+				continue
+			}
+			branches[p.repo.GetFullName()] = branch
+		}
+		return resolvedBranchesMsg{branches}
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+// strategyCycle is the order in which the s key steps through existing-repository strategies
+var strategyCycle = []git.ExistingRepoStrategy{git.SkipExisting, git.OverwriteExisting, git.FetchOnly, git.Sync}
+
+// nextStrategy returns the strategy following current in strategyCycle
+func nextStrategy(current git.ExistingRepoStrategy) git.ExistingRepoStrategy {
+	for i, strategy := range strategyCycle {
+		if strategy == current {
+			return strategyCycle[(i+1)%len(strategyCycle)]
+		}
+	}
+	return strategyCycle[0]
+}
+
+// confirmListLimit is the number of selected repositories listed by name before summarizing the rest
+const confirmListLimit = 10
+
+// updateConfirmView starts cloning on y or Enter, returns to the selection on
+// n or Esc and cycles the existing-repository strategy on s
+func (m Model) updateConfirmView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if resolved, ok := msg.(resolvedBranchesMsg); ok {
+		m.resolvingBranches = false
+		m.currentView = ViewProgress
+		return m, m.startCloning(resolved.branches)
+	}
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || m.resolvingBranches {
+		return m, nil
+	}
+	switch key.String() {
+	case "y", "enter":
+		if len(m.branchFallbacks) > 0 {
+			m.resolvingBranches = true
+			return m, m.resolveBranches()
+		}
+		m.currentView = ViewProgress
+		return m, m.startCloning(nil)
+	case "n", "esc":
+		m.currentView = ViewRepositories
+	case "s":
+		m.strategy = nextStrategy(m.strategy)
+	}
+	return m, nil
+}
+
+// confirmView summarizes what is about to be cloned and where
+func (m Model) confirmView() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Clone %d repositories?", m.repositories.SelectedCount())))
+	b.WriteString("\n\n")
+
+	listed := 0
+	for _, repo := range m.repositories.repositories {
+		if !m.repositories.selectedRepos[repo.GetFullName()] {
+			continue
+		}
+		if listed < confirmListLimit {
+			line := "  " + repo.GetFullName()
+			if branch := m.repositories.BranchFor(repo); branch != "" {
+				line += " @" + branch
+			}
+			b.WriteString(line + "\n")
+		}
+		listed++
+	}
+	if listed > confirmListLimit {
+		b.WriteString(fmt.Sprintf("  ... and %d more\n", listed-confirmListLimit))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("Target directory: %s\n", m.progress.repoManager.BaseDir()))
+	b.WriteString(fmt.Sprintf("Existing repositories: %s\n", strategyNames[m.strategy]))
+	if m.strategy == git.OverwriteExisting {
+		b.WriteString(warningStyle.Render("Existing clones of these repositories will be replaced by fresh clones"))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.strategyOverridesView())
+	b.WriteString(m.backupView())
+
+	b.WriteString("\n")
+	if m.resolvingBranches {
+		b.WriteString(infoStyle.Render("Resolving branch fallbacks..."))
+		return b.String()
+	}
+	b.WriteString(infoStyle.Render("y/Enter: Start cloning, s: Change existing-repository strategy, n/Esc: Back to selection, q: Quit"))
+	return b.String()
+}
+
+// backupView describes where --backup-on-overwrite moves replaced clones, or
+// returns an empty string when no selected repository is overwritten
+func (m Model) backupView() string {
+	opts := m.progress.repoManager.CloneDefaults()
+	if !opts.BackupOnOverwrite || !m.overwritesAny() {
+		return ""
+	}
+	kept := "all backups are kept"
+	if opts.BackupRetention > 0 {
+		kept = fmt.Sprintf("the newest %d per repository are kept", opts.BackupRetention)
+	}
+	return fmt.Sprintf("Replaced clones are moved to timestamped .bak directories; %s\n", kept)
+}
+
+// overwritesAny reports whether any selected repository will be overwritten,
+// after strategy overrides
+func (m Model) overwritesAny() bool {
+	for _, repo := range m.repositories.repositories {
+		if !m.repositories.selectedRepos[repo.GetFullName()] {
+			continue
+		}
+		if m.progress.repoManager.StrategyFor(repo.GetOwner().GetLogin(), repo.GetName(), m.strategy) == git.OverwriteExisting {
+			return true
+		}
+	}
+	return false
+}
+
+// strategyOverridesView lists the selected repositories whose strategy
+// clone.strategy_overrides replaces with a different one, warning about those
+// that will be overwritten
+func (m Model) strategyOverridesView() string {
+	var lines []string
+	for _, repo := range m.repositories.repositories {
+		if !m.repositories.selectedRepos[repo.GetFullName()] {
+			continue
+		}
+		strategy := m.progress.repoManager.StrategyFor(repo.GetOwner().GetLogin(), repo.GetName(), m.strategy)
+		if strategy == m.strategy {
+			continue
+		}
+		line := fmt.Sprintf("  %s: %s", repo.GetFullName(), strategyNames[strategy])
+		if strategy == git.OverwriteExisting {
+			line = warningStyle.Render(line + " (an existing clone will be replaced)")
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Overridden by clone.strategy_overrides:\n")
+	for i, line := range lines {
+		if i == confirmListLimit {
+			b.WriteString(fmt.Sprintf("  ... and %d more\n", len(lines)-confirmListLimit))
+			break
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
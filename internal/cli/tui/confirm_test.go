@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+func TestConfirmViewKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want View
+		// clones reports whether the key starts the clone run
+		clones bool
+	}{
+		{"y confirms", "y", ViewProgress, true},
+		{"enter confirms", "enter", ViewProgress, true},
+		{"n goes back", "n", ViewRepositories, false},
+		{"esc goes back", "esc", ViewRepositories, false},
+		{"other keys wait", "j", ViewConfirm, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t, "api", "web")
+			for _, repo := range m.repositories.repositories {
+				repo.CloneURL = github.String("file://" + t.TempDir() + "/missing.git")
+			}
+			opts := git.DefaultCloneOptions()
+			opts.MaxRetries = 0
+			m.progress.repoManager.SetCloneDefaults(opts)
+			m = pressKeys(m, " ", "enter")
+			if m.currentView != ViewConfirm {
+				t.Fatalf("view = %v after Enter, want the confirmation", m.currentView)
+			}
+
+			model, cmd := m.Update(keyMsg(tt.key))
+			m = model.(Model)
+			if m.currentView != tt.want {
+				t.Errorf("view = %v, want %v", m.currentView, tt.want)
+			}
+			if started := cmd != nil; started != tt.clones {
+				t.Fatalf("clone command returned = %v, want %v", started, tt.clones)
+			}
+			if tt.clones {
+				drain(t, m.progress, cmd)
+			}
+			if queued := len(m.progress.repoManager.GetRepositories()) > 0; queued != tt.clones {
+				t.Errorf("repositories queued = %v, want %v", queued, tt.clones)
+			}
+			// Going back keeps the selection
+			if got := selectedNames(m); strings.Join(got, ",") != "acme/api" {
+				t.Errorf("selection = %v, want acme/api", got)
+			}
+		})
+	}
+}
+
+func TestConfirmViewSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		selected int
+		want     []string
+		absent   []string
+	}{
+		{"few repositories", 2, []string{"Clone 2 repositories?", "  acme/repo00\n", "  acme/repo01\n"}, []string{"more", "repo02"}},
+		{"beyond the list limit", 12, []string{"Clone 12 repositories?", "  acme/repo09\n", "... and 2 more"}, []string{"repo10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t, repoNames(0, 15)...)
+			for i := 0; i < tt.selected; i++ {
+				m.repositories.selectedRepos[fmt.Sprintf("acme/repo%02d", i)] = true
+			}
+			m.currentView = ViewConfirm
+
+			view := m.View()
+			want := append(tt.want, "Target directory: "+m.progress.repoManager.BaseDir(), "Existing repositories: Skip")
+			for _, w := range want {
+				if !strings.Contains(view, w) {
+					t.Errorf("view does not show %q:\n%s", w, view)
+				}
+			}
+			for _, a := range tt.absent {
+				if strings.Contains(view, a) {
+					t.Errorf("view shows %q:\n%s", a, view)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmViewShowsBranch(t *testing.T) {
+	m := newRepositoriesTestModel(t, "api")
+	m.repositories.selectedRepos["acme/api"] = true
+	m.repositories.branches = map[string]string{"acme/api": "develop"}
+	m.currentView = ViewConfirm
+	if view := m.View(); !strings.Contains(view, "acme/api @develop") {
+		t.Errorf("view does not show the chosen branch:\n%s", view)
+	}
+	if m.strategy != git.SkipExisting {
+		t.Errorf("strategy = %v, want skip by default", m.strategy)
+	}
+}
+
+func TestNextStrategy(t *testing.T) {
+	tests := []struct {
+		current git.ExistingRepoStrategy
+		want    git.ExistingRepoStrategy
+	}{
+		{git.SkipExisting, git.OverwriteExisting},
+		{git.OverwriteExisting, git.FetchOnly},
+		{git.FetchOnly, git.Sync},
+		{git.Sync, git.SkipExisting},
+		{git.ExistingRepoStrategy(99), git.SkipExisting},
+	}
+	for _, tt := range tests {
+		if got := nextStrategy(tt.current); got != tt.want {
+			t.Errorf("nextStrategy(%v) = %v, want %v", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmStrategyAppliedToQueued(t *testing.T) {
+	tests := []struct {
+		name    string
+		presses int
+		want    git.ExistingRepoStrategy
+		warning bool
+	}{
+		{"default", 0, git.SkipExisting, false},
+		{"overwrite", 1, git.OverwriteExisting, true},
+		{"update", 2, git.FetchOnly, false},
+		{"sync", 3, git.Sync, false},
+		{"back to skip", 4, git.SkipExisting, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t, "api", "web")
+			for _, repo := range m.repositories.repositories {
+				repo.CloneURL = github.String("file://" + t.TempDir() + "/missing.git")
+			}
+			opts := git.DefaultCloneOptions()
+			opts.MaxRetries = 0
+			m.progress.repoManager.SetCloneDefaults(opts)
+
+			m = pressKeys(m, "a", "enter")
+			for i := 0; i < tt.presses; i++ {
+				m = pressKeys(m, "s")
+			}
+
+			view := m.View()
+			if !strings.Contains(view, "Existing repositories: "+strategyNames[tt.want]) {
+				t.Errorf("view does not show strategy %s:\n%s", strategyNames[tt.want], view)
+			}
+			if got := strings.Contains(view, "will be replaced"); got != tt.warning {
+				t.Errorf("overwrite warning shown = %v, want %v", got, tt.warning)
+			}
+
+			model, cmd := m.Update(keyMsg("y"))
+			m = model.(Model)
+			drain(t, m.progress, cmd)
+			queued := m.progress.repoManager.GetRepositories()
+			if len(queued) != 2 {
+				t.Fatalf("%d repositories queued, want 2", len(queued))
+			}
+			for _, repo := range queued {
+				if repo.ExistingRepo != tt.want {
+					t.Errorf("%s queued with %v, want %v", repo.Name, repo.ExistingRepo, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmViewListsStrategyOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		presses int
+		// listed maps the repositories shown as overridden to their strategy
+		listed  map[string]git.ExistingRepoStrategy
+		warning bool
+		queued  map[string]git.ExistingRepoStrategy
+	}{
+		{
+			name:    "skip chosen",
+			listed:  map[string]git.ExistingRepoStrategy{"legacy": git.OverwriteExisting},
+			warning: true,
+			queued:  map[string]git.ExistingRepoStrategy{"api": git.SkipExisting, "legacy": git.OverwriteExisting, "vendor": git.SkipExisting},
+		},
+		{
+			name:    "overwrite chosen",
+			presses: 1,
+			listed:  map[string]git.ExistingRepoStrategy{"vendor": git.SkipExisting},
+			queued:  map[string]git.ExistingRepoStrategy{"api": git.OverwriteExisting, "legacy": git.OverwriteExisting, "vendor": git.SkipExisting},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t, "api", "legacy", "vendor")
+			for _, repo := range m.repositories.repositories {
+				repo.CloneURL = github.String("file://" + t.TempDir() + "/missing.git")
+			}
+			opts := git.DefaultCloneOptions()
+			opts.MaxRetries = 0
+			m.progress.repoManager.SetCloneDefaults(opts)
+			m.progress.repoManager.SetStrategyOverrides([]git.StrategyOverride{
+				{Pattern: "acme/legacy", Strategy: git.OverwriteExisting},
+				{Pattern: "acme/vendor", Strategy: git.SkipExisting},
+			})
+
+			m = pressKeys(m, "a", "enter")
+			for i := 0; i < tt.presses; i++ {
+				m = pressKeys(m, "s")
+			}
+
+			view := m.View()
+			if !strings.Contains(view, "Overridden by clone.strategy_overrides") {
+				t.Errorf("view does not list the overrides:\n%s", view)
+			}
+			for _, name := range []string{"api", "legacy", "vendor"} {
+				line := fmt.Sprintf("  acme/%s: ", name)
+				strategy, ok := tt.listed[name]
+				if got := strings.Contains(view, line); got != ok {
+					t.Errorf("acme/%s listed as overridden = %v, want %v:\n%s", name, got, ok, view)
+				}
+				if ok && !strings.Contains(view, line+strategyNames[strategy]) {
+					t.Errorf("acme/%s not listed with %s:\n%s", name, strategyNames[strategy], view)
+				}
+			}
+			if got := strings.Contains(view, "an existing clone will be replaced"); got != tt.warning {
+				t.Errorf("override overwrite warning shown = %v, want %v", got, tt.warning)
+			}
+
+			model, cmd := m.Update(keyMsg("y"))
+			m = model.(Model)
+			drain(t, m.progress, cmd)
+			for _, repo := range m.progress.repoManager.GetRepositories() {
+				if want := tt.queued[repo.Name]; repo.ExistingRepo != want {
+					t.Errorf("%s queued with %v, want %v", repo.Name, repo.ExistingRepo, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmViewMentionsBackups(t *testing.T) {
+	tests := []struct {
+		name      string
+		backup    bool
+		retention int
+		overwrite bool // press s once to choose Overwrite
+		override  bool // override acme/legacy to Overwrite
+		want      string
+	}{
+		{"no backups", false, 3, true, false, ""},
+		{"nothing overwritten", true, 3, false, false, ""},
+		{"overwrite chosen", true, 3, true, false, "the newest 3 per repository are kept"},
+		{"keep all backups", true, 0, true, false, "all backups are kept"},
+		{"overwritten by override", true, 3, false, true, "the newest 3 per repository are kept"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t, "api", "legacy")
+			opts := git.DefaultCloneOptions()
+			opts.BackupOnOverwrite = tt.backup
+			opts.BackupRetention = tt.retention
+			m.progress.repoManager.SetCloneDefaults(opts)
+			if tt.override {
+				m.progress.repoManager.SetStrategyOverrides([]git.StrategyOverride{{Pattern: "acme/legacy", Strategy: git.OverwriteExisting}})
+			}
+
+			m = pressKeys(m, "a", "enter")
+			if tt.overwrite {
+				m = pressKeys(m, "s")
+			}
+
+			view := m.View()
+			if got := strings.Contains(view, ".bak directories"); got != (tt.want != "") {
+				t.Fatalf("backups mentioned = %v, want %v:\n%s", got, tt.want != "", view)
+			}
+			if tt.want != "" && !strings.Contains(view, tt.want) {
+				t.Errorf("view does not say %q:\n%s", tt.want, view)
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package tui
 import (
 	"context"
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/git"
 	gh "github.com/sachin-duhan/zikrr/internal/github"
 )
 
@@ -15,6 +18,7 @@ type View int
 const (
 	ViewOrganization View = iota
 	ViewRepositories
+	ViewConfirm
 	ViewProgress
 )
 
@@ -35,11 +39,33 @@ type Model struct {
 	progress     *ProgressModel
 
 	// Shared state
-	filter *gh.RepositoryFilter
+	filter          *gh.RepositoryFilter
+	branchFallbacks []string
+	// resolvingBranches is set while the branch fallbacks of the selection are resolved
+	resolvingBranches bool
+	dependsOn         string
+	strategy          git.ExistingRepoStrategy
+	useSSH            bool
+
+	// selectionStateDir enables saving selections per organization when set
+	selectionStateDir string
+	// selectionFile is where "r" exports the selection
+	selectionFile string
+
+	// rateLimitErr is the error of the latest rate limit refresh, if it failed
+	rateLimitErr error
+	// rateLimitSeen is the status at the previous tick; API responses replace
+	// it, so an unchanged one means the status line needs a refresh
+	rateLimitSeen *gh.RateLimitInfo
 }
 
-// NewModel creates a new TUI model
-func NewModel(ctx context.Context, client *gh.Client, baseDir string, maxConcurrent int) Model {
+// NewModel creates a new TUI model cloning the selected repositories with
+// manager, which carries the clone settings
+func NewModel(ctx context.Context, client *gh.Client, manager *git.RepositoryManager) Model {
+	progress := NewProgressModel(manager)
+	if client != nil {
+		progress.SetRevalidate(client.Revalidate)
+	}
 	return Model{
 		ctx:          ctx,
 		client:       client,
@@ -47,13 +73,17 @@ func NewModel(ctx context.Context, client *gh.Client, baseDir string, maxConcurr
 		filter:       &gh.RepositoryFilter{},
 		organization: NewOrganizationModel(),
 		repositories: NewRepositoriesModel(),
-		progress:     NewProgressModel(baseDir, maxConcurrent),
+		progress:     progress,
 	}
 }
 
 // Init implements tea.Model
 func (m Model) Init() tea.Cmd {
-	return nil
+	if m.client == nil {
+		return nil
+	}
+	// API responses keep the rate limit current; the tick covers idle periods
+	return scheduleRateLimit()
 }
 
 // Update implements tea.Model
@@ -64,12 +94,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
+			if msg.String() == "q" && m.currentView == ViewRepositories && m.repositories.filterVisible {
+				// Typed into the search input
+				break
+			}
+			if m.currentView == ViewProgress {
+				m.progress.cancel()
+			}
 			return m, tea.Quit
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+
+	case rateLimitTickMsg:
+		if info := m.client.LastRateLimit(); info != nil && info != m.rateLimitSeen {
+			m.rateLimitSeen = info
+			m.rateLimitErr = nil
+			return m, scheduleRateLimit()
+		}
+		return m, m.refreshRateLimit
+
+	case rateLimitMsg:
+		m.rateLimitErr = msg.err
+		m.rateLimitSeen = m.client.LastRateLimit()
+		return m, scheduleRateLimit()
+
+	case accessWarningsMsg:
+		m.organization.warnings = msg.warnings
+		return m, nil
 	}
 
 	// Handle view-specific updates
@@ -78,8 +132,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateOrganizationView(msg)
 	case ViewRepositories:
 		return m.updateRepositoriesView(msg)
+	case ViewConfirm:
+		return m.updateConfirmView(msg)
 	case ViewProgress:
-		return m.progress.Update(msg)
+		_, cmd := m.progress.Update(msg)
+		return m, cmd
 	}
 
 	return m, tea.Batch(cmds...)
@@ -92,6 +149,8 @@ func (m Model) View() string {
 		return m.organizationView()
 	case ViewRepositories:
 		return m.repositoriesView()
+	case ViewConfirm:
+		return m.confirmView()
 	case ViewProgress:
 		return m.progress.View()
 	default:
@@ -118,6 +177,9 @@ var (
 
 	cursorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFFF00"))
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500"))
 )
 
 // Error handling helper
@@ -143,3 +205,97 @@ func (m *Model) SetOrganization(org string) {
 		m.organization.input = org
 	}
 }
+
+// SetRepositories skips the organization view and offers a pre-resolved set of
+// repositories for selection, labelled with the given name
+func (m *Model) SetRepositories(label string, repos []*github.Repository) {
+	m.organization.name = label
+	m.repositories.SetRepositories(repos)
+	m.repositories.finishPreselected()
+	m.currentView = ViewRepositories
+}
+
+// SetFilter sets the filter applied when listing organization repositories
+func (m *Model) SetFilter(filter *gh.RepositoryFilter) {
+	m.filter = filter
+}
+
+// SetExistingRepoStrategy sets how repositories already present on disk are handled
+func (m *Model) SetExistingRepoStrategy(strategy git.ExistingRepoStrategy) {
+	m.strategy = strategy
+}
+
+// SetSelectionStateDir enables saving the repository selection per organization
+// to dir and offering to restore it on the next launch
+func (m *Model) SetSelectionStateDir(dir string) {
+	m.selectionStateDir = dir
+}
+
+// SetSSH makes repositories clone over SSH instead of HTTPS
+func (m *Model) SetSSH(enabled bool) {
+	m.useSSH = enabled
+}
+
+// SetSelectionFile sets the file the selection is exported to with "r". When
+// it exists, its repositories are pre-selected as they are listed.
+func (m *Model) SetSelectionFile(path string) error {
+	m.selectionFile = path
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	state, err := LoadSelectionFile(path)
+	if err != nil {
+		return err
+	}
+	m.repositories.SetPreselected(state.Repos)
+	return nil
+}
+
+// SetBranchFallbacks sets the branches tried, in order, when a repository lacks the requested branch
+func (m *Model) SetBranchFallbacks(fallbacks []string) {
+	m.branchFallbacks = fallbacks
+}
+
+// SetNotify enables the completion bell and terminal title updates
+func (m *Model) SetNotify(enabled bool) {
+	m.progress.SetNotify(enabled)
+}
+
+// Summary returns the outcome of the clone run
+func (m *Model) Summary() *git.CloneSummary {
+	return m.progress.repoManager.Summary()
+}
+
+// Manifest returns the session manifest of the clone run
+func (m *Model) Manifest() *git.SessionManifest {
+	return m.progress.repoManager.Manifest()
+}
+
+// SetDependsOn limits the listed repositories to those depending on the given package
+func (m *Model) SetDependsOn(pkg string) {
+	m.dependsOn = pkg
+}
+
+// ClonedRepositories returns the GitHub repositories that were cloned or updated successfully
+func (m *Model) ClonedRepositories() []*github.Repository {
+	byName := make(map[string]*github.Repository, len(m.repositories.repositories))
+	for _, repo := range m.repositories.repositories {
+		byName[repo.GetFullName()] = repo
+	}
+
+	var cloned []*github.Repository
+	for _, repo := range m.progress.repoManager.GetRepositories() {
+		if status, _, _ := repo.GetStatus(); status != git.StatusSuccess {
+			continue
+		}
+		if r, ok := byName[repo.Organization+"/"+repo.Name]; ok {
+			cloned = append(cloned, r)
+		}
+	}
+	return cloned
+}
+
+// ListedCount returns the number of repositories listed after filtering
+func (m *Model) ListedCount() int {
+	return len(m.repositories.repositories)
+}
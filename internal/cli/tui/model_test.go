@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+func TestNewModelRendersEveryView(t *testing.T) {
+	tests := []struct {
+		name  string
+		view  View
+		repos []string
+	}{
+		{"organization", ViewOrganization, nil},
+		{"empty repositories", ViewRepositories, nil},
+		{"repositories", ViewRepositories, []string{"api", "web"}},
+		{"confirm", ViewConfirm, []string{"api"}},
+		{"progress", ViewProgress, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(t.Context(), nil, git.NewRepositoryManager(t.TempDir(), 2))
+			m.Init()
+			model, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+			m = model.(Model)
+
+			m.currentView = tt.view
+			if tt.repos != nil {
+				m.organization.name = "acme"
+				m.repositories.SetRepositories(testRepositories(tt.repos...))
+				m.repositories.SelectAll()
+			}
+			if view := m.View(); view == "" || view == "Unknown view" {
+				t.Errorf("View() = %q", view)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// terminalNotifier emits a bell and window title updates so long runs can be
+// followed from another tab. It does nothing unless enabled and writing to a TTY.
+type terminalNotifier struct {
+	enabled bool
+	out     io.Writer
+	last    string
+}
+
+// stderrIsTerminal reports whether stderr is a terminal
+var stderrIsTerminal = func() bool {
+	return isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())
+}
+
+// newTerminalNotifier creates a notifier writing to stderr when it is a terminal
+func newTerminalNotifier(enabled bool) *terminalNotifier {
+	return &terminalNotifier{
+		enabled: enabled && stderrIsTerminal(),
+		out:     os.Stderr,
+	}
+}
+
+// progress updates the terminal title with the finished/total count when it changes
+func (n *terminalNotifier) progress(finished, total int) {
+	if n == nil || !n.enabled {
+		return
+	}
+	title := fmt.Sprintf("zikrr: %d/%d", finished, total)
+	if title == n.last {
+		return
+	}
+	n.last = title
+	fmt.Fprintf(n.out, "\x1b]0;%s\a", title)
+}
+
+// complete rings the terminal bell
+func (n *terminalNotifier) complete() {
+	if n == nil || !n.enabled {
+		return
+	}
+	fmt.Fprint(n.out, "\a")
+}
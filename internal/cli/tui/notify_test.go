@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+func TestTerminalNotifier(t *testing.T) {
+	saved := stderrIsTerminal
+	t.Cleanup(func() { stderrIsTerminal = saved })
+
+	tests := []struct {
+		name    string
+		enabled bool
+		tty     bool
+		want    string
+	}{
+		{"enabled on a terminal", true, true, "\x1b]0;zikrr: 1/2\a\x1b]0;zikrr: 2/2\a\a"},
+		{"enabled without a terminal", true, false, ""},
+		{"disabled on a terminal", false, true, ""},
+		{"disabled without a terminal", false, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderrIsTerminal = func() bool { return tt.tty }
+			manager := git.NewRepositoryManager(t.TempDir(), 1)
+			m := NewProgressModel(manager)
+			m.SetNotify(tt.enabled)
+			var out bytes.Buffer
+			m.notifier.out = &out
+
+			app := m.AddRepository("acme", "app", "unused", "", git.SkipExisting)
+			lib := m.AddRepository("acme", "lib", "unused", "", git.SkipExisting)
+			app.UpdateStatus(git.StatusSuccess, nil)
+			m.Update(repoUpdateMsg{app})
+			// An unchanged count does not rewrite the title
+			m.Update(repoUpdateMsg{app})
+			lib.UpdateStatus(git.StatusFailed, nil)
+			m.Update(repoUpdateMsg{lib})
+			m.Update(cloneDoneMsg{})
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
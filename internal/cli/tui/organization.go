@@ -1,18 +1,26 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	gh "github.com/sachin-duhan/zikrr/internal/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
 )
 
 // OrganizationModel represents the organization input view
 type OrganizationModel struct {
 	input string
 	name  string
+	// orgs are the organizations listed, parsed from a comma-separated input
+	orgs  []string
 	error error
+	// warnings are likely token permission problems found for the organizations
+	warnings []string
 }
 
 // NewOrganizationModel creates a new organization model
@@ -26,10 +34,11 @@ func (m Model) updateOrganizationView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEnter:
-			if strings.TrimSpace(m.organization.input) != "" {
-				m.organization.name = strings.TrimSpace(m.organization.input)
+			if orgs := gh.ParseOrganizations([]string{m.organization.input}); len(orgs) > 0 {
+				m.organization.orgs = orgs
+				m.organization.name = strings.Join(orgs, ", ")
 				m.currentView = ViewRepositories
-				return m, m.fetchRepositories
+				return m, tea.Batch(m.fetchRepositories, m.checkAccess)
 			}
 		case tea.KeyBackspace:
 			if len(m.organization.input) > 0 {
@@ -52,7 +61,7 @@ func (m Model) organizationView() string {
 	b.WriteString("\n\n")
 
 	// Input prompt
-	prompt := "Enter GitHub organization name: "
+	prompt := "Enter GitHub organization name(s), comma-separated: "
 	b.WriteString(prompt)
 
 	// Input field
@@ -84,18 +93,108 @@ func (m Model) organizationView() string {
 		b.WriteString(errorStyle.Render(m.organization.error.Error()))
 	}
 
+	// The scopes are known before any organization is entered
+	if token := m.token(); token != nil && !token.HasRepoScope() {
+		b.WriteString("\n")
+		b.WriteString(warningStyle.Render("Warning: the token lacks the repo scope, so private repositories will be missing or fail to clone"))
+	}
+
+	b.WriteString(m.rateLimitFooter())
+	return b.String()
+}
+
+// token returns the client's token, or nil without a client
+func (m Model) token() *auth.Token {
+	if m.client == nil {
+		return nil
+	}
+	return m.client.Token()
+}
+
+// checkAccess is a command checking in the background that the token can read
+// the repositories of each organization
+func (m Model) checkAccess() tea.Msg {
+	token := m.token()
+	if token == nil || token.Client == nil {
+		return nil
+	}
+	var warnings []string
+	seen := make(map[string]bool)
+	for _, org := range m.organization.orgs {
+		found, err := token.AccessWarnings(m.ctx, org)
+		if err != nil {
+			util.Warn(fmt.Sprintf("Could not check token access to %s: %v", org, err))
+		}
+		for _, warning := range found {
+			util.Warn(warning)
+			if !seen[warning] {
+				seen[warning] = true
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	return accessWarningsMsg{warnings}
+}
+
+// accessWarningsView renders the token access warnings, if any
+func (m Model) accessWarningsView() string {
+	var b strings.Builder
+	for _, warning := range m.organization.warnings {
+		b.WriteString("\n")
+		b.WriteString(warningStyle.Render("Warning: " + warning))
+	}
 	return b.String()
 }
 
-// fetchRepositories is a command that fetches repositories for the organization
+// fetchRepositories is a command that fetches repositories for the organization.
+// Without a dependency filter, pages are streamed into the picker as they arrive.
 func (m Model) fetchRepositories() tea.Msg {
-	repos, err := m.client.ListFilteredRepositories(m.ctx, m.organization.name, m.filter)
+	if m.dependsOn == "" {
+		return m.streamRepositories()
+	}
+
+	repos, err := m.client.ListFilteredRepositoriesMulti(m.ctx, m.organization.orgs, m.filter, nil)
+	if err != nil {
+		return errMsg{err}
+	}
+	repos, err = m.client.FilterByDependency(m.ctx, repos, m.dependsOn)
 	if err != nil {
 		return errMsg{err}
 	}
 	return reposMsg{repos}
 }
 
+// streamRepositories starts listing in the background and returns the first page
+func (m Model) streamRepositories() tea.Msg {
+	pages := make(chan tea.Msg, 1)
+	// send delivers msg unless the TUI quit, which stops reading pages
+	send := func(msg tea.Msg) {
+		select {
+		case pages <- msg:
+		case <-m.ctx.Done():
+		}
+	}
+	go func() {
+		defer close(pages)
+		_, err := m.client.ListFilteredRepositoriesMulti(m.ctx, m.organization.orgs, m.filter, func(repos []*github.Repository) {
+			send(reposPageMsg{repos: repos, next: pages})
+		})
+		if err != nil {
+			send(errMsg{err})
+			return
+		}
+		send(reposDoneMsg{next: pages})
+	}()
+	return waitForPage(pages)()
+}
+
+// waitForPage returns a command receiving the next listing message
+func waitForPage(pages <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-pages
+	}
+}
+
 // Custom messages
 type (
 	errMsg struct {
@@ -105,4 +204,20 @@ type (
 	reposMsg struct {
 		repos []*github.Repository
 	}
+
+	// reposPageMsg carries one streamed page of repositories
+	reposPageMsg struct {
+		repos []*github.Repository
+		next  <-chan tea.Msg
+	}
+
+	// reposDoneMsg reports that a streamed listing has completed
+	reposDoneMsg struct {
+		next <-chan tea.Msg
+	}
+
+	// accessWarningsMsg carries likely token permission problems
+	accessWarningsMsg struct {
+		warnings []string
+	}
 )
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/sachin-duhan/zikrr/internal/auth"
 	"github.com/sachin-duhan/zikrr/internal/git"
 )
 
@@ -26,6 +28,7 @@ var (
 		git.SkipExisting:      "Skip",
 		git.OverwriteExisting: "Overwrite",
 		git.FetchOnly:         "Update",
+		git.Sync:              "Sync",
 	}
 )
 
@@ -33,39 +36,122 @@ var (
 type ProgressModel struct {
 	repoManager *git.RepositoryManager
 	progress    progress.Model
+	repoBar     progress.Model
 	width       int
 	height      int
 	done        bool
+	failedOnly  bool
 	err         error
 	updates     <-chan *git.Repository
+	notifier    *terminalNotifier
 	ctx         context.Context
 	cancel      context.CancelFunc
+	// runCancel stops the current CloneAll batch without quitting
+	runCancel context.CancelFunc
+	// ssoURL is set while cloning is paused waiting for SSO authorization
+	ssoURL     string
+	ssoPending bool
+	ssoErr     error
+	revalidate func(context.Context) error
 }
 
-// NewProgressModel creates a new progress model
-func NewProgressModel(baseDir string, maxConcurrent int) *ProgressModel {
+// NewProgressModel creates a progress model cloning with manager
+func NewProgressModel(manager *git.RepositoryManager) *ProgressModel {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &ProgressModel{
-		repoManager: git.NewRepositoryManager(baseDir, maxConcurrent),
+		repoManager: manager,
 		progress:    progress.New(progress.WithDefaultGradient()),
+		repoBar:     progress.New(progress.WithDefaultGradient(), progress.WithWidth(20), progress.WithoutPercentage()),
 		ctx:         ctx,
 		cancel:      cancel,
 	}
 }
 
+// SetNotify enables the completion bell and terminal title progress updates
+func (m *ProgressModel) SetNotify(enabled bool) {
+	m.notifier = newTerminalNotifier(enabled)
+}
+
+// notifyProgress reports the number of finished repositories to the notifier
+func (m *ProgressModel) notifyProgress() {
+	repos := m.repoManager.GetRepositories()
+	finished := 0
+	for _, repo := range repos {
+		switch status, _, _ := repo.GetStatus(); status {
+		case git.StatusSuccess, git.StatusSkipped, git.StatusFailed:
+			finished++
+		}
+	}
+	m.notifier.progress(finished, len(repos))
+}
+
+// SetRevalidate sets the function re-validating the token before cloning
+// resumes after an SSO pause
+func (m *ProgressModel) SetRevalidate(fn func(context.Context) error) {
+	m.revalidate = fn
+}
+
 // AddRepository adds a repository to be cloned
-func (m *ProgressModel) AddRepository(org, name, url, branch string, strategy git.ExistingRepoStrategy) {
-	m.repoManager.AddRepository(org, name, url, branch, strategy)
+func (m *ProgressModel) AddRepository(org, name, url, branch string, strategy git.ExistingRepoStrategy) *git.Repository {
+	return m.repoManager.AddRepository(org, name, url, branch, strategy)
 }
 
-// StartCloning starts the cloning process
+// StartCloning starts cloning all pending repositories and returns the command
+// delivering their updates
 func (m *ProgressModel) StartCloning() tea.Cmd {
+	m.done = false
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.runCancel = cancel
+	m.updates = m.repoManager.CloneAll(ctx)
+	return waitForRepoUpdate(m.updates)
+}
+
+// pauseForSSO stops the current batch when repo failed because the token
+// needs SSO authorization. The other repositories of the run would fail the
+// same way, so they are cancelled and retried together once authorized.
+func (m *ProgressModel) pauseForSSO(repo *git.Repository) {
+	if m.ssoPending {
+		return
+	}
+	_, err, _ := repo.GetStatus()
+	if url, ok := auth.IsSSOError(err); ok {
+		m.ssoPending, m.ssoURL, m.ssoErr = true, url, nil
+		m.runCancel()
+	}
+}
+
+// revalidateToken returns the command re-validating the token after an SSO pause
+func (m *ProgressModel) revalidateToken() tea.Msg {
+	if m.revalidate == nil {
+		return ssoRevalidatedMsg{}
+	}
+	return ssoRevalidatedMsg{m.revalidate(m.ctx)}
+}
+
+// waitForRepoUpdate returns a command receiving the next repository update
+func waitForRepoUpdate(updates <-chan *git.Repository) tea.Cmd {
 	return func() tea.Msg {
-		m.updates = m.repoManager.CloneAll(m.ctx)
-		return nil
+		repo, ok := <-updates
+		if !ok {
+			return cloneDoneMsg{}
+		}
+		return repoUpdateMsg{repo}
 	}
 }
 
+// Clone progress messages
+type (
+	repoUpdateMsg struct {
+		repo *git.Repository
+	}
+
+	cloneDoneMsg struct{}
+
+	ssoRevalidatedMsg struct {
+		err error
+	}
+)
+
 // Update handles model updates
 func (m *ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -82,21 +168,46 @@ func (m *ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c", "q":
 			m.cancel()
 			return m, tea.Quit
+		case "f":
+			m.failedOnly = !m.failedOnly
+			return m, nil
+		case "r":
+			// Retry failed repositories once the current run has finished
+			if m.done && m.ssoPending {
+				return m, m.revalidateToken
+			}
+			if m.done && m.repoManager.ResetFailed() > 0 {
+				m.done = false
+				m.failedOnly = false
+				return m, m.StartCloning()
+			}
+			return m, nil
 		}
-	}
 
-	if m.updates != nil {
-		select {
-		case repo, ok := <-m.updates:
-			if !ok {
-				m.done = true
-				return m, tea.Quit
-			}
-			if repo != nil {
-				return m, nil
+	case repoUpdateMsg:
+		m.pauseForSSO(msg.repo)
+		m.notifyProgress()
+		return m, waitForRepoUpdate(m.updates)
+
+	case ssoRevalidatedMsg:
+		if msg.err != nil {
+			if url, ok := auth.IsSSOError(msg.err); ok && url != "" {
+				m.ssoURL = url
 			}
-		default:
+			m.ssoErr = msg.err
+			return m, nil
 		}
+		m.ssoPending, m.ssoURL, m.ssoErr = false, "", nil
+		m.repoManager.ResetFailed()
+		m.failedOnly = false
+		return m, m.StartCloning()
+
+	case cloneDoneMsg:
+		m.done = true
+		m.updates = nil
+		m.notifyProgress()
+		m.notifier.complete()
+		return m, nil
 	}
 
 	prog, cmd := m.progress.Update(msg)
@@ -113,14 +224,15 @@ func (m *ProgressModel) View() string {
 	}
 
 	var s strings.Builder
-	s.WriteString("\n  Cloning Repositories\n\n")
+	s.WriteString("\n  Cloning Repositories\n")
+	if m.failedOnly {
+		s.WriteString("  (showing failed repositories only)\n")
+	}
+	s.WriteString("\n")
 
 	// Show repository status
 	repos := m.repoManager.GetRepositories()
 	total := len(repos)
-	completed := 0
-	skipped := 0
-	failed := 0
 
 	for _, repo := range repos {
 		status, err, progress := repo.GetStatus()
@@ -135,49 +247,146 @@ func (m *ProgressModel) View() string {
 		}
 
 		// Add progress or error information
-		if status == git.StatusCloning && progress != "" {
+		if phase, percent := repo.GetPercent(); status == git.StatusCloning && phase != "" {
+			repoLine += fmt.Sprintf(" %s %s %3.0f%%", m.repoBar.ViewAs(percent/100), phase, percent)
+		} else if status == git.StatusCloning && progress != "" {
 			repoLine += fmt.Sprintf(" - %s", progress)
 		} else if status == git.StatusUpdating && progress != "" {
 			repoLine += fmt.Sprintf(" - %s", progress)
 		}
+		if status == git.StatusSuccess {
+			snapshot := repo.Snapshot()
+			if snapshot.ClonedBranch != "" {
+				repoLine += fmt.Sprintf(" (%s)", snapshot.ClonedBranch)
+			} else if snapshot.Tag != "" {
+				repoLine += fmt.Sprintf(" (tag %s)", snapshot.Tag)
+			}
+			if snapshot.Empty {
+				repoLine += " (empty)"
+			}
+			if snapshot.Slow {
+				repoLine += " (slow)"
+			}
+		}
+		if status == git.StatusSuccess || status == git.StatusFailed {
+			if d := repo.Snapshot().DurationSeconds; d > 0 {
+				repoLine += fmt.Sprintf(" [%s]", formatDuration(d))
+			}
+		}
 		if err != nil {
 			repoLine += fmt.Sprintf(" - Error: %v", err)
 		}
 
-		s.WriteString(statusStyle.Render(repoLine) + "\n")
-
-		// Update counters
-		switch status {
-		case git.StatusSuccess:
-			completed++
-		case git.StatusSkipped:
-			skipped++
-		case git.StatusFailed:
-			failed++
+		if !m.failedOnly || status == git.StatusFailed {
+			s.WriteString(statusStyle.Render(repoLine) + "\n")
 		}
+
 	}
 
 	// Show overall progress
+	counts := countStatuses(repos)
 	s.WriteString("\n")
 	if total > 0 {
-		progress := float64(completed+skipped) / float64(total)
-		s.WriteString(fmt.Sprintf("  %s\n", m.progress.ViewAs(progress)))
-		s.WriteString(fmt.Sprintf("  Progress: %d/%d repositories\n", completed+skipped, total))
-		s.WriteString(fmt.Sprintf("  • Completed: %d\n", completed))
-		s.WriteString(fmt.Sprintf("  • Skipped: %d\n", skipped))
-		if failed > 0 {
-			s.WriteString(fmt.Sprintf("  • Failed: %d\n", failed))
+		s.WriteString(fmt.Sprintf("  %s\n", m.progress.ViewAs(float64(counts.finished())/float64(total))))
+		s.WriteString(renderCounts(counts, total))
+		s.WriteString(fmt.Sprintf("  Elapsed: %s\n", formatDuration(m.repoManager.Elapsed().Seconds())))
+	}
+
+	if m.ssoPending {
+		s.WriteString("\n  Cloning paused: your token must be authorized for the organization's SAML SSO.\n")
+		if m.ssoURL != "" {
+			s.WriteString(fmt.Sprintf("  Authorize it at:\n    %s\n", m.ssoURL))
+		}
+		if m.ssoErr != nil {
+			s.WriteString(fmt.Sprintf("  Token is still not authorized: %v\n", m.ssoErr))
+		}
+		if m.done {
+			s.WriteString("  Press r to retry after authorizing, q to quit\n")
+		} else {
+			s.WriteString("  Stopping the remaining repositories...\n")
 		}
+		return s.String()
 	}
 
 	// Show completion message
 	if m.done {
 		s.WriteString("\n  Done! Press q to exit\n")
+		if counts.failed > 0 {
+			s.WriteString("  Press f to toggle failed-only view, r to retry failed\n")
+		}
+	} else {
+		s.WriteString("\n  Press f to toggle failed-only view\n")
+	}
+
+	return s.String()
+}
+
+// statusCounts tallies repositories by status for the progress summary
+type statusCounts struct {
+	completed int
+	skipped   int
+	failed    int
+	empty     int
+	updating  int
+	retrying  int
+}
+
+// finished returns the number of repositories that reached a final status.
+// An update only counts once it has succeeded or failed.
+func (c statusCounts) finished() int {
+	return c.completed + c.skipped + c.failed
+}
+
+// countStatuses tallies the current status of each repository
+func countStatuses(repos []*git.Repository) statusCounts {
+	var counts statusCounts
+	for _, repo := range repos {
+		status, _, _ := repo.GetStatus()
+		switch status {
+		case git.StatusSuccess:
+			counts.completed++
+			if repo.IsEmpty() {
+				counts.empty++
+			}
+		case git.StatusSkipped:
+			counts.skipped++
+		case git.StatusFailed:
+			counts.failed++
+		case git.StatusUpdating:
+			counts.updating++
+		case git.StatusRetrying:
+			counts.retrying++
+		}
 	}
+	return counts
+}
 
+// renderCounts renders the progress summary lines for the tallies
+func renderCounts(counts statusCounts, total int) string {
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("  Progress: %d/%d repositories\n", counts.finished(), total))
+	s.WriteString(fmt.Sprintf("  • Completed: %d\n", counts.completed))
+	s.WriteString(fmt.Sprintf("  • Skipped: %d\n", counts.skipped))
+	if counts.empty > 0 {
+		s.WriteString(fmt.Sprintf("  • Empty: %d\n", counts.empty))
+	}
+	if counts.updating > 0 {
+		s.WriteString(fmt.Sprintf("  • Updating: %d\n", counts.updating))
+	}
+	if counts.retrying > 0 {
+		s.WriteString(fmt.Sprintf("  • Retrying: %d\n", counts.retrying))
+	}
+	if counts.failed > 0 {
+		s.WriteString(fmt.Sprintf("  • Failed: %d\n", counts.failed))
+	}
 	return s.String()
 }
 
+// formatDuration renders seconds rounded to a tenth of a second, e.g. "3.2s"
+func formatDuration(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(100 * time.Millisecond).String()
+}
+
 // Init initializes the model
 func (m *ProgressModel) Init() tea.Cmd {
 	return tea.Batch(
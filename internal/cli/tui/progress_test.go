@@ -0,0 +1,260 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+func TestProgressFailedOnlyView(t *testing.T) {
+	manager := git.NewRepositoryManager(t.TempDir(), 1)
+	m := NewProgressModel(manager)
+	statuses := map[string]git.RepositoryStatus{
+		"cloned":  git.StatusSuccess,
+		"broken":  git.StatusFailed,
+		"cloning": git.StatusCloning,
+		"skipped": git.StatusSkipped,
+		"denied":  git.StatusFailed,
+	}
+	for name, status := range statuses {
+		repo := m.AddRepository("acme", name, "https://github.com/acme/"+name+".git", "", git.SkipExisting)
+		var err error
+		if status == git.StatusFailed {
+			err = errors.New("clone failed")
+		}
+		repo.UpdateStatus(status, err)
+	}
+
+	tests := []struct {
+		name string
+		// toggles is the number of times f is pressed
+		toggles int
+		shown   []string
+		hidden  []string
+		notice  bool
+	}{
+		{"all repositories", 0, []string{"cloned", "broken", "cloning", "skipped", "denied"}, nil, false},
+		{"failed only", 1, []string{"broken", "denied"}, []string{"cloned", "cloning", "skipped"}, true},
+		{"toggled back", 2, []string{"cloned", "broken", "cloning", "skipped", "denied"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.failedOnly = false
+			for i := 0; i < tt.toggles; i++ {
+				m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+			}
+			view := m.View()
+			for _, name := range tt.shown {
+				if !strings.Contains(view, "acme/"+name) {
+					t.Errorf("view does not show acme/%s:\n%s", name, view)
+				}
+			}
+			for _, name := range tt.hidden {
+				if strings.Contains(view, "acme/"+name) {
+					t.Errorf("view shows acme/%s:\n%s", name, view)
+				}
+			}
+			if got := strings.Contains(view, "showing failed repositories only"); got != tt.notice {
+				t.Errorf("failed-only notice shown = %v, want %v", got, tt.notice)
+			}
+			// The overall counts always cover every repository
+			if !strings.Contains(view, "Failed: 2") {
+				t.Errorf("view does not count both failures:\n%s", view)
+			}
+		})
+	}
+}
+
+// drain runs cmd and the update commands following it until the clone run is done
+func drain(t *testing.T, m *ProgressModel, cmd tea.Cmd) {
+	t.Helper()
+	deadline := time.After(time.Minute)
+	for cmd != nil {
+		msgs := make(chan tea.Msg, 1)
+		go func() { msgs <- cmd() }()
+		select {
+		case msg := <-msgs:
+			_, cmd = m.Update(msg)
+			if _, ok := msg.(cloneDoneMsg); ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("clone run did not finish")
+		}
+	}
+}
+
+func TestProgressSSOPauseAndRetry(t *testing.T) {
+	const ssoURL = "https://github.com/orgs/acme/sso?authorization_request=abc"
+	stillBlocked := &auth.SSOError{URL: ssoURL + "2", Err: errors.New("403")}
+
+	tests := []struct {
+		name string
+		// revalidations are the results of successive revalidations
+		revalidations []error
+		resumed       bool
+	}{
+		{"authorized", []error{nil}, true},
+		{"authorized on second attempt", []error{stillBlocked, nil}, true},
+		{"still blocked", []error{stillBlocked}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := git.NewRepositoryManager(t.TempDir(), 1)
+			opts := git.DefaultCloneOptions()
+			opts.MaxRetries = 0
+			manager.SetCloneDefaults(opts)
+			m := NewProgressModel(manager)
+			calls := 0
+			m.SetRevalidate(func(context.Context) error {
+				err := tt.revalidations[calls]
+				calls++
+				return err
+			})
+
+			// Both repositories point at missing paths, so a resumed run fails fast
+			missing := "file://" + t.TempDir()
+			blocked := m.AddRepository("acme", "app", missing+"/app.git", "", git.SkipExisting)
+			m.AddRepository("acme", "lib", missing+"/lib.git", "", git.SkipExisting)
+			cancelled := false
+			m.runCancel = func() { cancelled = true }
+
+			blocked.UpdateStatus(git.StatusFailed, &auth.SSOError{URL: ssoURL, Err: git.ErrAuthRequired})
+			m.Update(repoUpdateMsg{blocked})
+			if !m.ssoPending || m.ssoURL != ssoURL || !cancelled {
+				t.Fatalf("after SSO failure: pending %v, url %q, cancelled %v; want paused with %q", m.ssoPending, m.ssoURL, cancelled, ssoURL)
+			}
+			if view := m.View(); !strings.Contains(view, ssoURL) || !strings.Contains(view, "Stopping") {
+				t.Errorf("view while stopping does not show the pause:\n%s", view)
+			}
+
+			// Retrying is only offered once the cancelled run has wound down
+			if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}); cmd != nil {
+				t.Error("r before the run finished returned a command")
+			}
+			m.Update(cloneDoneMsg{})
+			if view := m.View(); !strings.Contains(view, "Press r to retry") {
+				t.Errorf("view after the run does not offer a retry:\n%s", view)
+			}
+
+			for i, want := range tt.revalidations {
+				_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+				if cmd == nil {
+					t.Fatalf("retry %d: r returned no command", i+1)
+				}
+				_, cmd = m.Update(cmd())
+				if want != nil {
+					if !m.ssoPending || cmd != nil {
+						t.Fatalf("retry %d: revalidation failed but cloning resumed", i+1)
+					}
+					if m.ssoURL != stillBlocked.URL || !strings.Contains(m.View(), "still not authorized") {
+						t.Errorf("retry %d: url %q, want %q with the error shown", i+1, m.ssoURL, stillBlocked.URL)
+					}
+					continue
+				}
+				if m.ssoPending || cmd == nil {
+					t.Fatalf("retry %d: pending %v after successful revalidation, want cloning resumed", i+1, m.ssoPending)
+				}
+				drain(t, m, cmd)
+			}
+
+			if m.ssoPending == tt.resumed {
+				t.Errorf("pending = %v, want %v", m.ssoPending, !tt.resumed)
+			}
+			if calls != len(tt.revalidations) {
+				t.Errorf("revalidated %d times, want %d", calls, len(tt.revalidations))
+			}
+			if tt.resumed {
+				// The resumed run retried both repositories, which now fail on the missing remote
+				for _, repo := range manager.GetRepositories() {
+					if _, err, _ := repo.GetStatus(); err == nil {
+						t.Errorf("%s was not retried", repo.Name)
+					} else if _, sso := auth.IsSSOError(err); sso {
+						t.Errorf("%s still carries the SSO failure after the retry", repo.Name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "0s"},
+		{0.04, "0s"},
+		{0.06, "100ms"},
+		{3.24, "3.2s"},
+		{3.26, "3.3s"},
+		{75.5, "1m15.5s"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.seconds); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestProgressCounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []git.RepositoryStatus
+		// want are lines the summary shows, absent lines it omits
+		want   []string
+		absent []string
+	}{
+		{
+			"fetch-only run in flight",
+			[]git.RepositoryStatus{git.StatusUpdating, git.StatusUpdating, git.StatusSuccess, git.StatusPending},
+			[]string{"Progress: 1/4 repositories", "• Completed: 1", "• Updating: 2"},
+			[]string{"Retrying", "Failed"},
+		},
+		{
+			"retries and failures",
+			[]git.RepositoryStatus{git.StatusRetrying, git.StatusFailed, git.StatusSkipped, git.StatusCloning},
+			[]string{"Progress: 2/4 repositories", "• Skipped: 1", "• Retrying: 1", "• Failed: 1"},
+			[]string{"Updating"},
+		},
+		{
+			"all finished",
+			[]git.RepositoryStatus{git.StatusSuccess, git.StatusSuccess, git.StatusFailed},
+			[]string{"Progress: 3/3 repositories", "• Completed: 2", "• Failed: 1"},
+			[]string{"Updating", "Retrying"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := git.NewRepositoryManager(t.TempDir(), 1)
+			for i, status := range tt.statuses {
+				name := fmt.Sprintf("repo%d", i)
+				repo := manager.AddRepository("acme", name, "https://github.com/acme/"+name+".git", "", git.SkipExisting)
+				var err error
+				if status == git.StatusFailed {
+					err = errors.New("clone failed")
+				}
+				repo.UpdateStatus(status, err)
+			}
+
+			view := NewProgressModel(manager).View()
+			for _, want := range tt.want {
+				if !strings.Contains(view, want) {
+					t.Errorf("view does not show %q:\n%s", want, view)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(view, "• "+absent) {
+					t.Errorf("view shows a %s tally:\n%s", absent, view)
+				}
+			}
+		})
+	}
+}
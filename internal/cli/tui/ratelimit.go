@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gh "github.com/sachin-duhan/zikrr/internal/github"
+)
+
+// rateLimitRefreshInterval is how often the rate limit status line is refreshed
+// when no API response has updated it in the meantime
+const rateLimitRefreshInterval = 30 * time.Second
+
+// Rate limit refresh messages
+type (
+	// rateLimitTickMsg triggers a rate limit refresh
+	rateLimitTickMsg struct{}
+
+	// rateLimitMsg carries the outcome of a rate limit refresh
+	rateLimitMsg struct {
+		err error
+	}
+)
+
+// renderRateLimit renders the status line for a rate limit status. A failed
+// refresh keeps the last known status and notes that it may be stale.
+func renderRateLimit(info *gh.RateLimitInfo, err error) string {
+	if info == nil {
+		if err != nil {
+			return infoStyle.Render("Rate limit: unavailable")
+		}
+		return ""
+	}
+	line := fmt.Sprintf("Rate limit: %d/%d remaining, resets at %s",
+		info.Remaining, info.Limit, info.Reset.Local().Format("15:04:05"))
+	if err != nil {
+		line += " (refresh failed)"
+	}
+	return infoStyle.Render(line)
+}
+
+// rateLimitFooter renders the rate limit seen by the client's most recent response or check
+func (m Model) rateLimitFooter() string {
+	if m.client == nil {
+		return ""
+	}
+	if footer := renderRateLimit(m.client.LastRateLimit(), m.rateLimitErr); footer != "" {
+		return "\n\n" + footer
+	}
+	return ""
+}
+
+// refreshRateLimit is a command querying the current rate limit in the background
+func (m Model) refreshRateLimit() tea.Msg {
+	_, err := m.client.GetRateLimit(m.ctx)
+	return rateLimitMsg{err: err}
+}
+
+// scheduleRateLimit returns a command triggering the next rate limit refresh
+func scheduleRateLimit() tea.Cmd {
+	return tea.Tick(rateLimitRefreshInterval, func(time.Time) tea.Msg {
+		return rateLimitTickMsg{}
+	})
+}
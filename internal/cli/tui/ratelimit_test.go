@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/internal/git"
+	gh "github.com/sachin-duhan/zikrr/internal/github"
+)
+
+func TestRenderRateLimit(t *testing.T) {
+	reset := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	info := &gh.RateLimitInfo{Remaining: 4321, Limit: 5000, Reset: reset}
+
+	tests := []struct {
+		name string
+		info *gh.RateLimitInfo
+		err  error
+		want string
+	}{
+		{"status", info, nil, "Rate limit: 4321/5000 remaining, resets at 03:04:05"},
+		{"stale status", info, errors.New("timeout"), "Rate limit: 4321/5000 remaining, resets at 03:04:05 (refresh failed)"},
+		{"no status yet", nil, nil, ""},
+		{"unavailable", nil, errors.New("timeout"), "Rate limit: unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderRateLimit(tt.info, tt.err)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("renderRateLimit() = %q, want empty", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("renderRateLimit() = %q, want it to show %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitTickRefreshesOnlyWhenIdle(t *testing.T) {
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/rate_limit":
+			checks++
+			w.Write([]byte(`{"resources":{"core":{"limit":5000,"remaining":5000,"reset":0}}}`))
+		default:
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "4000")
+			w.Header().Set("X-RateLimit-Reset", "0")
+			w.Write([]byte(`{"login":"acme"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := gh.NewClient(t.Context(), &auth.Token{Value: "test-token", BaseURL: server.URL})
+	m := NewModel(t.Context(), client, git.NewRepositoryManager(t.TempDir(), 1))
+
+	// tick delivers a tick and runs the refresh it starts; the command scheduling
+	// the next tick instead is not run, as it waits for the refresh interval
+	tick := func(refresh bool) {
+		t.Helper()
+		model, cmd := m.Update(rateLimitTickMsg{})
+		m = model.(Model)
+		if refresh {
+			model, _ = m.Update(cmd())
+			m = model.(Model)
+		} else if m.rateLimitSeen != client.LastRateLimit() {
+			t.Error("tick did not take the status from the latest response")
+		}
+	}
+
+	tests := []struct {
+		name string
+		// call makes an API call before the tick
+		call       bool
+		checks     int
+		remaining  int
+		footerText string
+	}{
+		{"idle start refreshes", false, 1, 5000, "5000/5000"},
+		{"response updated the status", true, 1, 4000, "4000/5000"},
+		{"idle again refreshes", false, 2, 5000, "5000/5000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.call {
+				if _, err := client.GetOrganization(t.Context(), "acme"); err != nil {
+					t.Fatalf("GetOrganization() error = %v", err)
+				}
+			}
+			tick(!tt.call)
+			if checks != tt.checks {
+				t.Errorf("rate limit queried %d times, want %d", checks, tt.checks)
+			}
+			if got := client.LastRateLimit(); got == nil || got.Remaining != tt.remaining {
+				t.Errorf("LastRateLimit() = %+v, want %d remaining", got, tt.remaining)
+			}
+			if footer := m.rateLimitFooter(); !strings.Contains(footer, tt.footerText) {
+				t.Errorf("footer = %q, want it to show %s", footer, tt.footerText)
+			}
+		})
+	}
+}
@@ -6,10 +6,15 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	gh "github.com/sachin-duhan/zikrr/internal/github"
 )
 
 const reposPerPage = 10
 
+// starThresholds are the minimum star counts the s key cycles through
+var starThresholds = []int{0, 10, 100, 1000}
+
 // RepositoriesModel represents the repository selection view
 type RepositoriesModel struct {
 	repositories  []*github.Repository
@@ -17,50 +22,242 @@ type RepositoriesModel struct {
 	cursor        int
 	page          int
 	totalPages    int
-	filterVisible bool
 	error         error
+
+	// visible is the subset of repositories matching query; filterVisible is
+	// set while the search input has focus
+	visible       []*github.Repository
+	query         string
+	filterVisible bool
+	// minStars hides repositories with fewer stars (0 = show all)
+	minStars int
+
+	// branches holds the branch chosen per repository (full name); absent means the default branch
+	branches map[string]string
+	picker   *branchPicker
+
+	// listing is the stream currently delivering pages; loading is set until it completes
+	listing <-chan tea.Msg
+	loading bool
+
+	// pendingRestore is a saved selection awaiting confirmation; restoreChecked
+	// is set once the state file has been looked up
+	pendingRestore *SelectionState
+	restoreChecked bool
+
+	// preselect maps lowercased full names from a selection file, not yet listed, to their names
+	preselect map[string]string
+	// notice is an informational message shown below the list
+	notice string
+
+	// ssoURL is set while listing is paused waiting for SSO authorization
+	ssoURL     string
+	ssoPending bool
 }
 
 // NewRepositoriesModel creates a new repositories model
 func NewRepositoriesModel() *RepositoriesModel {
 	return &RepositoriesModel{
 		selectedRepos: make(map[string]bool),
+		branches:      make(map[string]string),
 	}
 }
 
 // SetRepositories updates the repositories list and recalculates pages
 func (r *RepositoriesModel) SetRepositories(repos []*github.Repository) {
 	r.repositories = repos
-	r.totalPages = (len(repos) + reposPerPage - 1) / reposPerPage
+	r.applyPreselected(repos)
+	r.refilter()
+	r.page = 0
+	r.cursor = 0
+}
+
+// AppendRepositories adds newly listed repositories, keeping the current page,
+// cursor and selections
+func (r *RepositoriesModel) AppendRepositories(repos []*github.Repository) {
+	r.repositories = append(r.repositories, repos...)
+	r.applyPreselected(repos)
+	r.refilter()
+}
+
+// SetQuery narrows the displayed repositories to those matching query and
+// returns to the first page. Selections are kept for hidden repositories.
+func (r *RepositoriesModel) SetQuery(query string) {
+	r.query = query
+	r.refilter()
 	r.page = 0
 	r.cursor = 0
 }
 
-// GetPageRepos returns the repositories for the current page
+// CycleMinStars moves to the next minimum star threshold and returns to the first page
+func (r *RepositoriesModel) CycleMinStars() {
+	next := starThresholds[0]
+	for i, threshold := range starThresholds {
+		if threshold == r.minStars && i+1 < len(starThresholds) {
+			next = starThresholds[i+1]
+		}
+	}
+	r.minStars = next
+	r.refilter()
+	r.page = 0
+	r.cursor = 0
+}
+
+// refilter recomputes the visible repositories and page count
+func (r *RepositoriesModel) refilter() {
+	r.visible = filterRepositories(r.repositories, r.query)
+	if r.minStars > 0 {
+		r.visible = gh.FilterRepositories(r.visible, &gh.RepositoryFilter{MinStars: r.minStars})
+	}
+	r.totalPages = (len(r.visible) + reposPerPage - 1) / reposPerPage
+}
+
+// BranchFor returns the branch chosen for a repository, or "" for its default branch
+func (r *RepositoriesModel) BranchFor(repo *github.Repository) string {
+	return r.branches[repo.GetFullName()]
+}
+
+// SetBranch records the branch to clone for a repository; choosing the default
+// branch clears the selection
+func (r *RepositoriesModel) SetBranch(repo *github.Repository, branch string) {
+	if branch == "" || branch == repo.GetDefaultBranch() {
+		delete(r.branches, repo.GetFullName())
+		return
+	}
+	r.branches[repo.GetFullName()] = branch
+}
+
+// GetPageRepos returns the visible repositories for the current page
 func (r *RepositoriesModel) GetPageRepos() []*github.Repository {
 	start := r.page * reposPerPage
 	end := start + reposPerPage
-	if end > len(r.repositories) {
-		end = len(r.repositories)
+	if end > len(r.visible) {
+		end = len(r.visible)
 	}
-	if start >= len(r.repositories) {
+	if start >= len(r.visible) {
 		return nil
 	}
-	return r.repositories[start:end]
+	return r.visible[start:end]
+}
+
+// SelectAll selects every visible repository across all pages
+func (r *RepositoriesModel) SelectAll() {
+	for _, repo := range r.visible {
+		r.selectedRepos[repo.GetFullName()] = true
+	}
+}
+
+// ClearSelection deselects every repository, including those hidden by the search
+func (r *RepositoriesModel) ClearSelection() {
+	r.selectedRepos = make(map[string]bool)
+}
+
+// InvertSelection flips the selection state of every visible repository
+func (r *RepositoriesModel) InvertSelection() {
+	for _, repo := range r.visible {
+		fullName := repo.GetFullName()
+		if r.selectedRepos[fullName] {
+			delete(r.selectedRepos, fullName)
+		} else {
+			r.selectedRepos[fullName] = true
+		}
+	}
+}
+
+// SelectedCount returns the number of selected repositories
+func (r *RepositoriesModel) SelectedCount() int {
+	count := 0
+	for _, selected := range r.selectedRepos {
+		if selected {
+			count++
+		}
+	}
+	return count
 }
 
 // updateRepositoriesView handles updates for the repository selection view
 func (m Model) updateRepositoriesView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case reposMsg:
+		m.repositories.ssoPending = false
+		m.repositories.error = nil
 		m.repositories.SetRepositories(msg.repos)
+		m.repositories.finishPreselected()
+		m.offerRestore()
+		return m, nil
+
+	case reposPageMsg:
+		if msg.next != m.repositories.listing {
+			// First page of a new listing replaces any earlier results
+			m.repositories.SetRepositories(nil)
+			m.repositories.listing = msg.next
+		}
+		m.repositories.ssoPending = false
+		m.repositories.error = nil
+		m.repositories.loading = true
+		m.repositories.AppendRepositories(msg.repos)
+		m.offerRestore()
+		return m, waitForPage(msg.next)
+
+	case reposDoneMsg:
+		if msg.next != m.repositories.listing {
+			// Nothing matched, so no page started this listing
+			m.repositories.SetRepositories(nil)
+		}
+		m.repositories.listing = nil
+		m.repositories.loading = false
+		m.repositories.finishPreselected()
 		return m, nil
 
 	case errMsg:
+		m.repositories.listing = nil
+		m.repositories.loading = false
+		if url, ok := auth.IsSSOError(msg.error); ok {
+			// Pause until the user authorizes the token and asks to retry
+			m.repositories.ssoPending = true
+			m.repositories.ssoURL = url
+			m.repositories.error = nil
+			return m, nil
+		}
 		m.repositories.error = msg.error
 		return m, nil
 
+	case branchesMsg:
+		if picker := m.repositories.picker; picker != nil && picker.repo.GetFullName() == msg.repo {
+			picker.loading = false
+			picker.err = msg.err
+			picker.branches = msg.branches
+			picker.cursor = picker.indexOf(m.repositories.BranchFor(picker.repo), picker.repo.GetDefaultBranch())
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.repositories.picker != nil {
+			return m.updateBranchPicker(msg)
+		}
+		if m.repositories.filterVisible {
+			return m.updateSearchInput(msg)
+		}
+		if state := m.repositories.pendingRestore; state != nil {
+			switch msg.String() {
+			case "y":
+				for _, name := range state.Repos {
+					m.repositories.selectedRepos[name] = true
+				}
+				m.repositories.pendingRestore = nil
+			case "n":
+				m.repositories.pendingRestore = nil
+				clearSelection(m.selectionStateDir, m.organization.name)
+			}
+			return m, nil
+		}
+		if m.repositories.ssoPending {
+			if msg.String() == "r" {
+				m.repositories.ssoPending = false
+				return m, m.retryAfterSSO
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "up", "k":
 			if m.repositories.cursor > 0 {
@@ -87,29 +284,115 @@ func (m Model) updateRepositoriesView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(repos) > m.repositories.cursor {
 				repo := repos[m.repositories.cursor]
 				fullName := repo.GetFullName()
-				m.repositories.selectedRepos[fullName] = !m.repositories.selectedRepos[fullName]
+				if m.repositories.selectedRepos[fullName] {
+					delete(m.repositories.selectedRepos, fullName)
+				} else {
+					m.repositories.selectedRepos[fullName] = true
+				}
+				m.persistSelection()
 			}
-		case "f":
-			m.repositories.filterVisible = !m.repositories.filterVisible
+		case "b":
+			repos := m.repositories.GetPageRepos()
+			if len(repos) > m.repositories.cursor {
+				repo := repos[m.repositories.cursor]
+				m.repositories.picker = &branchPicker{repo: repo, loading: true}
+				return m, m.fetchBranches(repo)
+			}
+		case "r":
+			m.saveSelectionFile()
+		case "a":
+			m.repositories.SelectAll()
+			m.persistSelection()
+		case "n":
+			m.repositories.ClearSelection()
+			m.persistSelection()
+		case "i":
+			m.repositories.InvertSelection()
+			m.persistSelection()
+		case "f", "/":
+			m.repositories.filterVisible = true
+		case "s":
+			m.repositories.CycleMinStars()
 		case "enter":
-			if len(m.repositories.selectedRepos) > 0 {
-				m.currentView = ViewProgress
-				return m, m.startCloning
+			if m.repositories.SelectedCount() > 0 {
+				m.currentView = ViewConfirm
 			}
 		}
 	}
 	return m, nil
 }
 
+// updateSearchInput edits the search query while the search input has focus.
+// Enter keeps the query, Esc clears it.
+func (m Model) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.repositories.filterVisible = false
+	case tea.KeyEsc:
+		m.repositories.filterVisible = false
+		m.repositories.SetQuery("")
+	case tea.KeyBackspace:
+		if query := []rune(m.repositories.query); len(query) > 0 {
+			m.repositories.SetQuery(string(query[:len(query)-1]))
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.repositories.SetQuery(m.repositories.query + string(msg.Runes))
+	}
+	return m, nil
+}
+
+// retryAfterSSO re-validates the token and fetches the repositories again
+func (m Model) retryAfterSSO() tea.Msg {
+	if err := m.client.Revalidate(m.ctx); err != nil {
+		return errMsg{err}
+	}
+	return m.fetchRepositories()
+}
+
 // repositoriesView renders the repository selection screen
 func (m Model) repositoriesView() string {
 	var b strings.Builder
 
+	if m.repositories.picker != nil {
+		return m.branchPickerView()
+	}
+
+	if m.repositories.ssoPending {
+		b.WriteString(titleStyle.Render(fmt.Sprintf("%s - SSO authorization required", m.organization.name)))
+		b.WriteString("\n\n")
+		b.WriteString("Your token must be authorized for this organization's SAML SSO.\n")
+		if m.repositories.ssoURL != "" {
+			b.WriteString(fmt.Sprintf("Authorize it at:\n  %s\n", m.repositories.ssoURL))
+		}
+		b.WriteString("\n")
+		b.WriteString(infoStyle.Render("Press r to retry after authorizing, q to quit"))
+		return b.String()
+	}
+
 	// Title
 	title := fmt.Sprintf("%s - Select Repositories", m.organization.name)
 	b.WriteString(titleStyle.Render(title))
 	b.WriteString("\n\n")
 
+	if state := m.repositories.pendingRestore; state != nil {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("Restore the previous selection of %d repositories? (y/n)", len(state.Repos))))
+		b.WriteString("\n\n")
+	}
+
+	// Search input
+	if m.repositories.filterVisible || m.repositories.query != "" {
+		search := fmt.Sprintf("Search: %s", m.repositories.query)
+		if m.repositories.filterVisible {
+			search += "_"
+		}
+		b.WriteString(infoStyle.Render(fmt.Sprintf("%s  (%d/%d shown)", search, len(m.repositories.visible), len(m.repositories.repositories))))
+		b.WriteString("\n\n")
+	}
+	if m.repositories.minStars > 0 {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("Stars: at least %d  (%d/%d shown)", m.repositories.minStars, len(m.repositories.visible), len(m.repositories.repositories))))
+		b.WriteString("\n\n")
+	}
+
 	// Repository list
 	repos := m.repositories.GetPageRepos()
 	for i, repo := range repos {
@@ -125,13 +408,21 @@ func (m Model) repositoriesView() string {
 
 		// Repository info
 		repoInfo := fmt.Sprintf(
-			"%s [%s] %s (%d ⭐️, %s)",
+			"%s [%s] %s%s (%d ⭐️, %s)",
 			cursor,
 			selected,
 			repo.GetFullName(),
+			repoMarkers(repo),
 			repo.GetStargazersCount(),
 			repo.GetLanguage(),
 		)
+		if branch := m.repositories.BranchFor(repo); branch != "" {
+			repoInfo += fmt.Sprintf(" @%s", branch)
+		}
+
+		if repo.GetIsTemplate() {
+			repoInfo += " (template)"
+		}
 
 		// Style based on cursor position
 		if m.repositories.cursor == i {
@@ -148,6 +439,9 @@ func (m Model) repositoriesView() string {
 	// Pagination info
 	pageInfo := fmt.Sprintf("\nPage %d/%d", m.repositories.page+1, m.repositories.totalPages)
 	b.WriteString(infoStyle.Render(pageInfo))
+	if m.repositories.loading {
+		b.WriteString(infoStyle.Render("  loading more..."))
+	}
 	b.WriteString("\n\n")
 
 	// Instructions
@@ -155,8 +449,13 @@ func (m Model) repositoriesView() string {
 		"↑/k, ↓/j: Navigate",
 		"←/h, →/l: Change page",
 		"Space: Toggle selection",
-		"f: Toggle filters",
-		"Enter: Start cloning",
+		"a: Select all shown, n: Select none, i: Invert shown",
+		"r: Save selection to file",
+		"b: Choose branch",
+		"f or /: Search by name (Enter: keep, Esc: clear)",
+		"s: Cycle minimum stars (0, 10, 100, 1000)",
+		"[A]: archived, [F]: fork",
+		"Enter: Review and start cloning",
 		"q: Quit",
 	}
 	for _, instruction := range instructions {
@@ -165,20 +464,65 @@ func (m Model) repositoriesView() string {
 	}
 
 	// Selection summary
-	summary := fmt.Sprintf("\nSelected: %d repositories", len(m.repositories.selectedRepos))
+	summary := fmt.Sprintf("\nSelected: %d repositories", m.repositories.SelectedCount())
 	b.WriteString(infoStyle.Render(summary))
 
+	if m.repositories.notice != "" {
+		b.WriteString("\n")
+		b.WriteString(infoStyle.Render(m.repositories.notice))
+	}
+	b.WriteString(m.accessWarningsView())
+
 	// Error message
 	if m.repositories.error != nil {
 		b.WriteString("\n\n")
 		b.WriteString(errorStyle.Render(m.repositories.error.Error()))
 	}
 
+	b.WriteString(m.rateLimitFooter())
 	return b.String()
 }
 
-// startCloning is a command that initiates the cloning process
-func (m Model) startCloning() tea.Msg {
-	// This will be implemented in the next phase
-	return nil
+// repoMarkers returns compact markers for archived ([A]) and forked ([F]) repositories
+func repoMarkers(repo *github.Repository) string {
+	var markers string
+	if repo.GetArchived() {
+		markers += "[A]"
+	}
+	if repo.GetFork() {
+		markers += "[F]"
+	}
+	if markers != "" {
+		markers = " " + markers
+	}
+	return markers
+}
+
+// startCloning queues the selected repositories in the progress view and
+// returns the command that starts cloning them. resolved holds the branches
+// chosen by the branch fallbacks, by full name, replacing those picked with b.
+func (m Model) startCloning(resolved map[string]string) tea.Cmd {
+	if m.selectionStateDir != "" {
+		clearSelection(m.selectionStateDir, m.organization.name)
+	}
+	for _, repo := range m.repositories.repositories {
+		if !m.repositories.selectedRepos[repo.GetFullName()] {
+			continue
+		}
+		branch, ok := resolved[repo.GetFullName()]
+		if !ok {
+			branch = m.repositories.BranchFor(repo)
+		}
+		queued := m.progress.AddRepository(
+			repo.GetOwner().GetLogin(),
+			repo.GetName(),
+			gh.CloneURL(repo, m.useSSH),
+			branch,
+			m.strategy,
+		)
+		queued.SetLanguage(repo.GetLanguage())
+		queued.SetDefaultBranch(repo.GetDefaultBranch())
+		queued.SetSize(repo.GetSize())
+	}
+	return m.progress.StartCloning()
 }
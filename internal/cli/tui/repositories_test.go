@@ -0,0 +1,434 @@
+package tui
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/internal/git"
+	gh "github.com/sachin-duhan/zikrr/internal/github"
+)
+
+func TestRepositoriesSSOPauseAndRetry(t *testing.T) {
+	const ssoURL = "https://github.com/orgs/acme/sso?authorization_request=abc"
+
+	tests := []struct {
+		name string
+		// blocked is the number of listings answered with the SSO header
+		blocked int
+	}{
+		{"authorized after one prompt", 1},
+		{"authorized after two prompts", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var listings, validations atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v3/rate_limit":
+					w.Write([]byte(`{"resources":{"core":{"limit":5000,"remaining":5000,"reset":0}}}`))
+				case "/api/v3/user":
+					validations.Add(1)
+					w.Write([]byte(`{"login":"octocat"}`))
+				case "/api/v3/orgs/acme/repos":
+					if int(listings.Add(1)) <= tt.blocked {
+						w.Header().Set("X-GitHub-SSO", "required; url="+ssoURL)
+						w.WriteHeader(http.StatusForbidden)
+						w.Write([]byte(`{"message":"Resource protected by organization SAML enforcement."}`))
+						return
+					}
+					w.Write([]byte(`[{"name":"app","full_name":"acme/app","owner":{"login":"acme"}}]`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			client := gh.NewClient(t.Context(), &auth.Token{Value: "test-token", BaseURL: server.URL})
+			m := NewModel(t.Context(), client, git.NewRepositoryManager(t.TempDir(), 1))
+			m.currentView = ViewRepositories
+			m.organization.name = "acme"
+			m.organization.orgs = []string{"acme"}
+
+			var model tea.Model = m
+			msg := m.fetchRepositories()
+			for prompt := 1; prompt <= tt.blocked; prompt++ {
+				model, _ = model.Update(msg)
+				m = model.(Model)
+				if !m.repositories.ssoPending || m.repositories.ssoURL != ssoURL {
+					t.Fatalf("prompt %d: pending %v, url %q; want paused with %q", prompt, m.repositories.ssoPending, m.repositories.ssoURL, ssoURL)
+				}
+
+				// Other keys leave the listing paused
+				model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+				if cmd != nil || !model.(Model).repositories.ssoPending {
+					t.Fatalf("prompt %d: j resumed the listing", prompt)
+				}
+				model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+				if cmd == nil {
+					t.Fatalf("prompt %d: r returned no command", prompt)
+				}
+				msg = cmd()
+			}
+
+			for {
+				var cmd tea.Cmd
+				model, cmd = model.Update(msg)
+				if _, done := msg.(reposDoneMsg); done || cmd == nil {
+					break
+				}
+				msg = cmd()
+			}
+			m = model.(Model)
+			if m.repositories.ssoPending || m.repositories.error != nil {
+				t.Fatalf("after retry: pending %v, error %v; want the listing", m.repositories.ssoPending, m.repositories.error)
+			}
+			if got := len(m.repositories.repositories); got != 1 {
+				t.Errorf("listed %d repositories, want 1", got)
+			}
+			if got := validations.Load(); int(got) != tt.blocked {
+				t.Errorf("token validated %d times, want %d", got, tt.blocked)
+			}
+		})
+	}
+}
+
+// newRepositoriesTestModel returns a model showing the repository list of acme with the given repositories
+func newRepositoriesTestModel(t *testing.T, names ...string) Model {
+	t.Helper()
+
+	m := NewModel(t.Context(), nil, git.NewRepositoryManager(t.TempDir(), 1))
+	m.currentView = ViewRepositories
+	m.organization.name = "acme"
+	m.repositories.SetRepositories(testRepositories(names...))
+	return m
+}
+
+// testRepositories returns acme repositories with the given names
+func testRepositories(names ...string) []*github.Repository {
+	repos := make([]*github.Repository, len(names))
+	for i, name := range names {
+		repos[i] = &github.Repository{
+			Name:     github.String(name),
+			FullName: github.String("acme/" + name),
+			Owner:    &github.User{Login: github.String("acme")},
+		}
+	}
+	return repos
+}
+
+// keyMsg returns the key message for key, which is "enter", "esc", " " or runes
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// pressKeys sends each key to the model in turn
+func pressKeys(m Model, keys ...string) Model {
+	for _, key := range keys {
+		model, _ := m.Update(keyMsg(key))
+		m = model.(Model)
+	}
+	return m
+}
+
+// selectedNames returns the sorted full names of the selected repositories
+func selectedNames(m Model) []string {
+	var names []string
+	for name, selected := range m.repositories.selectedRepos {
+		if selected {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestInvertSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{"nothing selected", []string{"i"}, []string{"acme/api", "acme/web", "acme/worker"}},
+		{"everything selected", []string{"a", "i"}, nil},
+		{"one selected", []string{" ", "i"}, []string{"acme/web", "acme/worker"}},
+		{"twice restores", []string{" ", "i", "i"}, []string{"acme/api"}},
+		// Only the filtered repositories flip; acme/api stays selected while hidden
+		{"filtered", []string{" ", "/", "w", "enter", "i"}, []string{"acme/api", "acme/web", "acme/worker"}},
+		{"filtered after select all", []string{"a", "/", "wor", "enter", "i"}, []string{"acme/api", "acme/web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := pressKeys(newRepositoriesTestModel(t, "api", "web", "worker"), tt.keys...)
+			got := selectedNames(m)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("selected = %v, want %v", got, tt.want)
+			}
+			if m.repositories.SelectedCount() != len(tt.want) {
+				t.Errorf("SelectedCount() = %d, want %d", m.repositories.SelectedCount(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSelectAllAndNone(t *testing.T) {
+	tests := []struct {
+		name  string
+		repos []string
+		keys  []string
+		want  []string
+	}{
+		{"select all across pages", repoNames(0, 25), []string{"a"}, repoFullNames(0, 25)},
+		{"select all filtered", repoNames(0, 25), []string{"/", "repo2", "enter", "a"}, repoFullNames(20, 5)},
+		{"select all keeps hidden selections", repoNames(0, 25), []string{" ", "/", "repo2", "enter", "a"}, append([]string{"acme/repo00"}, repoFullNames(20, 5)...)},
+		{"select none", repoNames(0, 25), []string{"a", "n"}, nil},
+		{"select none clears hidden", repoNames(0, 25), []string{"a", "/", "repo2", "enter", "n", "esc"}, nil},
+		{"empty list", nil, []string{"a", "i", "n", "a", " ", "enter"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := pressKeys(newRepositoriesTestModel(t, tt.repos...), tt.keys...)
+			if got := selectedNames(m); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("selected = %v, want %v", got, tt.want)
+			}
+			if m.currentView != ViewRepositories {
+				t.Errorf("view = %v, want the repository list", m.currentView)
+			}
+		})
+	}
+
+	m := newRepositoriesTestModel(t, "api")
+	if view := m.View(); !strings.Contains(view, "a: Select all shown, n: Select none, i: Invert shown") {
+		t.Errorf("footer does not list the selection shortcuts:\n%s", view)
+	}
+}
+
+// repoNames returns n repository names starting at repo<start>
+func repoNames(start, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("repo%02d", start+i)
+	}
+	return names
+}
+
+// repoFullNames returns the full names of repoNames(start, n)
+func repoFullNames(start, n int) []string {
+	names := repoNames(start, n)
+	for i, name := range names {
+		names[i] = "acme/" + name
+	}
+	return names
+}
+
+func TestIncrementalAppendKeepsSelectionAndCursor(t *testing.T) {
+	tests := []struct {
+		name string
+		// keys are pressed after the first page arrives
+		keys       []string
+		wantPage   int
+		wantCursor int
+		wantSel    []string
+	}{
+		{"cursor on first page", []string{"j", "j", " "}, 0, 2, []string{"acme/repo02"}},
+		{"cursor on second page", []string{"l", "j", " "}, 1, 1, []string{"acme/repo11"}},
+		{"select all before more arrive", []string{"a"}, 0, 0, repoFullNames(0, 12)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t)
+			pages := make(chan tea.Msg)
+			model, cmd := m.Update(reposPageMsg{repos: testRepositories(repoNames(0, 12)...), next: pages})
+			if cmd == nil {
+				t.Fatal("first page did not wait for the next one")
+			}
+			m = pressKeys(model.(Model), tt.keys...)
+			if !strings.Contains(m.View(), "loading more...") {
+				t.Error("view does not show that more repositories are loading")
+			}
+
+			model, _ = m.Update(reposPageMsg{repos: testRepositories(repoNames(12, 13)...), next: pages})
+			m = model.(Model)
+			if got := len(m.repositories.repositories); got != 25 {
+				t.Fatalf("listed %d repositories, want 25", got)
+			}
+			if m.repositories.totalPages != 3 {
+				t.Errorf("totalPages = %d, want 3", m.repositories.totalPages)
+			}
+			if m.repositories.page != tt.wantPage || m.repositories.cursor != tt.wantCursor {
+				t.Errorf("page/cursor = %d/%d, want %d/%d", m.repositories.page, m.repositories.cursor, tt.wantPage, tt.wantCursor)
+			}
+			if got := selectedNames(m); strings.Join(got, ",") != strings.Join(tt.wantSel, ",") {
+				t.Errorf("selected = %v, want %v", got, tt.wantSel)
+			}
+
+			model, _ = m.Update(reposDoneMsg{next: pages})
+			m = model.(Model)
+			if m.repositories.loading || strings.Contains(m.View(), "loading more...") {
+				t.Error("still loading after the listing finished")
+			}
+		})
+	}
+}
+
+func TestNewListingReplacesStreamedRepositories(t *testing.T) {
+	m := newRepositoriesTestModel(t)
+	first, second := make(chan tea.Msg), make(chan tea.Msg)
+	model, _ := m.Update(reposPageMsg{repos: testRepositories("old"), next: first})
+	model, _ = model.Update(reposPageMsg{repos: testRepositories("new"), next: second})
+	m = model.(Model)
+	if got := len(m.repositories.repositories); got != 1 || m.repositories.repositories[0].GetName() != "new" {
+		t.Errorf("repositories = %v, want only the new listing", m.repositories.repositories)
+	}
+
+	// A listing that matched nothing clears the previous results
+	model, _ = m.Update(reposDoneMsg{next: make(chan tea.Msg)})
+	if got := len(model.(Model).repositories.repositories); got != 0 {
+		t.Errorf("listed %d repositories after an empty listing, want 0", got)
+	}
+}
+
+func TestStartCloningQueuesSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		strategy git.ExistingRepoStrategy
+		want     []string
+	}{
+		{"nothing selected stays", []string{"enter"}, git.SkipExisting, nil},
+		{"cancelled at confirmation", []string{" ", "enter", "esc"}, git.SkipExisting, nil},
+		{"selected repositories", []string{" ", "j", "j", " ", "enter", "y"}, git.SkipExisting, []string{"acme/api", "acme/worker"}},
+		{"strategy chosen at confirmation", []string{"a", "enter", "s", "enter"}, git.OverwriteExisting, []string{"acme/api", "acme/web", "acme/worker"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t, "api", "web", "worker")
+			for _, repo := range m.repositories.repositories {
+				repo.CloneURL = github.String("file://" + t.TempDir() + "/missing.git")
+				repo.DefaultBranch = github.String("main")
+			}
+
+			opts := git.DefaultCloneOptions()
+			opts.MaxRetries = 0
+			m.progress.repoManager.SetCloneDefaults(opts)
+
+			// The last key press returns the command starting the clone run
+			m = pressKeys(m, tt.keys[:len(tt.keys)-1]...)
+			model, cmd := m.Update(keyMsg(tt.keys[len(tt.keys)-1]))
+			m = model.(Model)
+
+			queued := m.progress.repoManager.GetRepositories()
+			if tt.want == nil {
+				if m.currentView == ViewProgress || len(queued) > 0 {
+					t.Fatalf("view = %v with %d queued, want nothing started", m.currentView, len(queued))
+				}
+				return
+			}
+			if m.currentView != ViewProgress || cmd == nil {
+				t.Fatalf("view = %v, cmd = %v, want the progress view with a clone command", m.currentView, cmd)
+			}
+			drain(t, m.progress, cmd)
+
+			var names []string
+			for _, repo := range queued {
+				names = append(names, repo.Organization+"/"+repo.Name)
+				if repo.ExistingRepo != tt.strategy || repo.DefaultBranch != "main" || !strings.HasSuffix(repo.URL, "missing.git") {
+					t.Errorf("%s queued with strategy %v, default branch %q and URL %q", repo.Name, repo.ExistingRepo, repo.DefaultBranch, repo.URL)
+				}
+			}
+			if strings.Join(names, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("queued %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestCycleMinStars(t *testing.T) {
+	tests := []struct {
+		name     string
+		presses  int
+		minStars int
+		visible  string
+	}{
+		{"all repositories", 0, 0, "app,lib,web,docs"},
+		{"at least 10", 1, 10, "lib,web,docs"},
+		{"at least 100", 2, 100, "web,docs"},
+		{"at least 1000", 3, 1000, "docs"},
+		{"wraps around", 4, 0, "app,lib,web,docs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t)
+			repos := testRepositories("app", "lib", "web", "docs")
+			for i, stars := range []int{0, 10, 500, 1000} {
+				repos[i].StargazersCount = github.Int(stars)
+			}
+			m.repositories.SetRepositories(repos)
+
+			for i := 0; i < tt.presses; i++ {
+				m = pressKeys(m, "s")
+			}
+			if m.repositories.minStars != tt.minStars {
+				t.Errorf("minStars = %d, want %d", m.repositories.minStars, tt.minStars)
+			}
+			var visible []string
+			for _, repo := range m.repositories.visible {
+				visible = append(visible, repo.GetName())
+			}
+			if got := strings.Join(visible, ","); got != tt.visible {
+				t.Errorf("visible = %s, want %s", got, tt.visible)
+			}
+			if got := strings.Contains(m.View(), "Stars: at least"); got != (tt.minStars > 0) {
+				t.Errorf("star threshold shown = %v with minStars %d", got, tt.minStars)
+			}
+		})
+	}
+}
+
+func TestRepoMarkers(t *testing.T) {
+	repos := testRepositories("app", "old", "copy", "old-copy")
+	repos[1].Archived = github.Bool(true)
+	repos[2].Fork = github.Bool(true)
+	repos[3].Archived, repos[3].Fork = github.Bool(true), github.Bool(true)
+
+	tests := []struct {
+		repo *github.Repository
+		want string
+	}{
+		{repos[0], ""},
+		{repos[1], " [A]"},
+		{repos[2], " [F]"},
+		{repos[3], " [A][F]"},
+	}
+	for _, tt := range tests {
+		if got := repoMarkers(tt.repo); got != tt.want {
+			t.Errorf("repoMarkers(%s) = %q, want %q", tt.repo.GetName(), got, tt.want)
+		}
+	}
+
+	// The list shows the markers after each repository's name
+	m := newRepositoriesTestModel(t)
+	m.repositories.SetRepositories(repos)
+	view := m.View()
+	for _, line := range []string{"acme/app (", "acme/old [A] (", "acme/copy [F] (", "acme/old-copy [A][F] ("} {
+		if !strings.Contains(view, line) {
+			t.Errorf("view does not show %q:\n%s", line, view)
+		}
+	}
+}
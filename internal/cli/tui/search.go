@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// matchesQuery reports whether a repository full name matches a search query,
+// case-insensitively. Queries containing glob characters are matched with
+// path.Match against the full name or the bare repository name; any other
+// query is a substring match on the full name.
+func matchesQuery(fullName, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	fullName = strings.ToLower(fullName)
+
+	if strings.ContainsAny(query, "*?[") {
+		if ok, _ := path.Match(query, fullName); ok {
+			return true
+		}
+		ok, _ := path.Match(query, path.Base(fullName))
+		return ok
+	}
+	return strings.Contains(fullName, query)
+}
+
+// filterRepositories returns the repositories whose full name matches query
+func filterRepositories(repos []*github.Repository, query string) []*github.Repository {
+	if strings.TrimSpace(query) == "" {
+		return repos
+	}
+
+	var matched []*github.Repository
+	for _, repo := range repos {
+		if matchesQuery(repo.GetFullName(), query) {
+			matched = append(matched, repo)
+		}
+	}
+	return matched
+}
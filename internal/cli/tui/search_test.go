@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMatchesQuery(t *testing.T) {
+	tests := []struct {
+		fullName string
+		query    string
+		want     bool
+	}{
+		{"acme/api-gateway", "", true},
+		{"acme/api-gateway", "  ", true},
+		{"acme/api-gateway", "gate", true},
+		{"acme/api-gateway", "GATE", true},
+		{"Acme/API-Gateway", "api-g", true},
+		{"acme/api-gateway", "acme/api", true},
+		{"acme/api-gateway", "worker", false},
+		{"acme/api-gateway", "api-*", true},
+		{"acme/api-gateway", "acme/api-*", true},
+		{"acme/api-gateway", "*-gateway", true},
+		{"acme/api-gateway", "web-*", false},
+		{"acme/api-gateway", "api-gatewa?", true},
+		{"acme/api-gateway", "[ab]pi-gateway", true},
+		{"acme/api-gateway", "[", false},
+	}
+	for _, tt := range tests {
+		if got := matchesQuery(tt.fullName, tt.query); got != tt.want {
+			t.Errorf("matchesQuery(%q, %q) = %v, want %v", tt.fullName, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilterRepositories(t *testing.T) {
+	repos := testRepositories("api", "api-gateway", "web", "worker")
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "api api-gateway web worker"},
+		{"api", "api api-gateway"},
+		{"W", "api-gateway web worker"},
+		{"w*", "web worker"},
+		{"missing", ""},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, repo := range filterRepositories(repos, tt.query) {
+			names = append(names, repo.GetName())
+		}
+		if got := strings.Join(names, " "); got != tt.want {
+			t.Errorf("filterRepositories(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchInput(t *testing.T) {
+	names := append(repoNames(0, 25), "worker", "web")
+	tests := []struct {
+		name       string
+		keys       []string
+		query      string
+		visible    int
+		totalPages int
+		selected   []string
+	}{
+		{"no search", nil, "", 27, 3, nil},
+		{"typed query", []string{"/", "r", "e", "p", "o", "1", "enter"}, "repo1", 10, 1, nil},
+		{"backspace widens", []string{"/", "w", "o", "backspace", "enter"}, "w", 2, 1, nil},
+		{"esc clears", []string{"/", "w", "esc"}, "", 27, 3, nil},
+		{"page and cursor reset", []string{"l", "j", "/", "w", "enter"}, "w", 2, 1, nil},
+		// acme/repo00 stays selected while the search hides it
+		{"selection kept", []string{" ", "/", "w", "e", "b", "enter", " "}, "web", 1, 1, []string{"acme/repo00", "acme/web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRepositoriesTestModel(t, names...)
+			for _, key := range tt.keys {
+				if key == "backspace" {
+					model, _ := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+					m = model.(Model)
+					continue
+				}
+				m = pressKeys(m, key)
+			}
+
+			r := m.repositories
+			if r.query != tt.query || len(r.visible) != tt.visible || r.totalPages != tt.totalPages {
+				t.Errorf("query %q shows %d repositories on %d pages, want %q, %d and %d", r.query, len(r.visible), r.totalPages, tt.query, tt.visible, tt.totalPages)
+			}
+			if tt.query != "" && (r.page != 0 || r.cursor != 0) {
+				t.Errorf("page %d, cursor %d after searching, want the first entry", r.page, r.cursor)
+			}
+			if got := strings.Join(selectedNames(m), " "); got != strings.Join(tt.selected, " ") {
+				t.Errorf("selected = %q, want %v", got, tt.selected)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// SelectionState records the repositories selected for an organization, to
+// restore an unfinished selection or to export it to a selection file
+type SelectionState struct {
+	Organization string   `json:"organization" yaml:"organization"`
+	Repos        []string `json:"repos" yaml:"repos"`
+}
+
+// selectionPath returns the state file used for the given organization
+func selectionPath(dir, org string) string {
+	return filepath.Join(dir, fmt.Sprintf("selection-%s.json", org))
+}
+
+// loadSelection reads a saved selection for the organization. A missing file yields a nil state.
+func loadSelection(dir, org string) (*SelectionState, error) {
+	data, err := os.ReadFile(selectionPath(dir, org))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read selection state: %w", err)
+	}
+
+	var state SelectionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse selection state: %w", err)
+	}
+	if state.Organization != org || len(state.Repos) == 0 {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// saveSelection persists the selected repositories for an organization,
+// removing the state file when nothing is selected
+func saveSelection(dir, org string, selected map[string]bool) error {
+	state := SelectionState{Organization: org}
+	for name, ok := range selected {
+		if ok {
+			state.Repos = append(state.Repos, name)
+		}
+	}
+	if len(state.Repos) == 0 {
+		clearSelection(dir, org)
+		return nil
+	}
+	sort.Strings(state.Repos)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create selection state directory: %w", err)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode selection state: %w", err)
+	}
+	if err := os.WriteFile(selectionPath(dir, org), data, 0644); err != nil {
+		return fmt.Errorf("failed to write selection state: %w", err)
+	}
+	return nil
+}
+
+// clearSelection removes the saved selection once cloning has started
+func clearSelection(dir, org string) {
+	if err := os.Remove(selectionPath(dir, org)); err != nil && !os.IsNotExist(err) {
+		util.Warn(fmt.Sprintf("Failed to remove selection state for %s: %v", org, err))
+	}
+}
+
+// offerRestore loads a saved selection for the current organization, if any,
+// so the repository view can ask whether to restore it
+func (m Model) offerRestore() {
+	if m.selectionStateDir == "" || m.repositories.restoreChecked {
+		return
+	}
+	m.repositories.restoreChecked = true
+
+	state, err := loadSelection(m.selectionStateDir, m.organization.name)
+	if err != nil {
+		util.Warn(err.Error())
+		return
+	}
+	m.repositories.pendingRestore = state
+}
+
+// persistSelection saves the current selection for the organization
+func (m Model) persistSelection() {
+	if m.selectionStateDir == "" {
+		return
+	}
+	if err := saveSelection(m.selectionStateDir, m.organization.name, m.repositories.selectedRepos); err != nil {
+		util.Warn(err.Error())
+	}
+}
+
+// LoadSelectionFile reads a selection exported with SaveSelectionFile. Files
+// ending in .yaml or .yml are YAML, anything else JSON.
+func LoadSelectionFile(path string) (*SelectionState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read selection file: %w", err)
+	}
+
+	var state SelectionState
+	if isYAMLPath(path) {
+		err = yaml.Unmarshal(data, &state)
+	} else {
+		err = json.Unmarshal(data, &state)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse selection file %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// SaveSelectionFile exports the selected repositories, by full name, to path
+func SaveSelectionFile(path, org string, selected map[string]bool) error {
+	state := SelectionState{Organization: org, Repos: []string{}}
+	for name, ok := range selected {
+		if ok {
+			state.Repos = append(state.Repos, name)
+		}
+	}
+	sort.Strings(state.Repos)
+
+	var data []byte
+	var err error
+	if isYAMLPath(path) {
+		data, err = yaml.Marshal(state)
+	} else {
+		data, err = json.MarshalIndent(state, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode selection: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write selection file: %w", err)
+	}
+	return nil
+}
+
+// isYAMLPath reports whether path has a YAML extension
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// SetPreselected marks repositories, by case-insensitive full name, to be
+// selected as soon as they are listed
+func (r *RepositoriesModel) SetPreselected(names []string) {
+	r.preselect = make(map[string]string, len(names))
+	for _, name := range names {
+		r.preselect[strings.ToLower(name)] = name
+	}
+	r.applyPreselected(r.repositories)
+}
+
+// applyPreselected selects the listed repositories named in the preselection
+func (r *RepositoriesModel) applyPreselected(repos []*github.Repository) {
+	for _, repo := range repos {
+		key := strings.ToLower(repo.GetFullName())
+		if _, ok := r.preselect[key]; ok {
+			r.selectedRepos[repo.GetFullName()] = true
+			delete(r.preselect, key)
+		}
+	}
+}
+
+// finishPreselected warns about preselected repositories that were not listed,
+// once the listing is complete, and drops them
+func (r *RepositoriesModel) finishPreselected() {
+	if len(r.preselect) == 0 {
+		return
+	}
+	missing := make([]string, 0, len(r.preselect))
+	for _, name := range r.preselect {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	util.Warn(fmt.Sprintf("Skipping %d previously selected repositories that no longer exist: %s", len(missing), strings.Join(missing, ", ")))
+	r.notice = fmt.Sprintf("%d previously selected repositories no longer exist and were skipped", len(missing))
+	r.preselect = nil
+}
+
+// defaultSelectionFile is used by "r" when no selection file was given
+const defaultSelectionFile = "zikrr-selection.json"
+
+// saveSelectionFile exports the current selection, reporting the outcome in the view
+func (m Model) saveSelectionFile() {
+	path := m.selectionFile
+	if path == "" {
+		path = defaultSelectionFile
+	}
+	if err := SaveSelectionFile(path, m.organization.name, m.repositories.selectedRepos); err != nil {
+		m.repositories.error = err
+		return
+	}
+	m.repositories.notice = fmt.Sprintf("Saved %d selected repositories to %s", m.repositories.SelectedCount(), path)
+}
@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveAndLoadSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		org      string
+		selected map[string]bool
+		loadOrg  string
+		want     []string
+	}{
+		{"same organization", "acme", map[string]bool{"acme/web": true, "acme/api": true, "acme/old": false}, "acme", []string{"acme/api", "acme/web"}},
+		{"other organization", "acme", map[string]bool{"acme/api": true}, "widgets", nil},
+		{"nothing selected", "acme", map[string]bool{"acme/api": false}, "acme", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := saveSelection(dir, tt.org, tt.selected); err != nil {
+				t.Fatalf("saveSelection() error = %v", err)
+			}
+			state, err := loadSelection(dir, tt.loadOrg)
+			if err != nil {
+				t.Fatalf("loadSelection() error = %v", err)
+			}
+			if tt.want == nil {
+				if state != nil {
+					t.Errorf("loadSelection() = %+v, want no saved selection", state)
+				}
+				return
+			}
+			if state == nil || state.Organization != tt.loadOrg || strings.Join(state.Repos, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("loadSelection() = %+v, want %v for %s", state, tt.want, tt.loadOrg)
+			}
+		})
+	}
+}
+
+func TestSaveSelectionReplacesEarlierSelection(t *testing.T) {
+	dir := t.TempDir()
+	if err := saveSelection(dir, "acme", map[string]bool{"acme/api": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveSelection(dir, "widgets", map[string]bool{"widgets/ui": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveSelection(dir, "acme", map[string]bool{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(selectionPath(dir, "acme")); !os.IsNotExist(err) {
+		t.Errorf("an empty selection left the state file in place: %v", err)
+	}
+	if state, _ := loadSelection(dir, "widgets"); state == nil || state.Repos[0] != "widgets/ui" {
+		t.Errorf("loadSelection(widgets) = %+v, want the other organization untouched", state)
+	}
+}
+
+func TestRestoreSelectionAfterRestart(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   []string
+		// kept reports whether the state file survives the answer
+		kept bool
+	}{
+		{"restored", "y", []string{"acme/api", "acme/worker"}, true},
+		{"declined", "n", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			names := []string{"api", "web", "worker"}
+
+			// The first session selects two repositories and quits
+			first := newRepositoriesTestModel(t, names...)
+			first.SetSelectionStateDir(dir)
+			pressKeys(first, " ", "j", "j", " ", "q")
+
+			// The next session for the same organization offers the selection back
+			second := NewModel(t.Context(), nil, first.progress.repoManager)
+			second.SetSelectionStateDir(dir)
+			second.currentView = ViewRepositories
+			second.organization.name = "acme"
+			model, _ := second.Update(reposMsg{repos: testRepositories(names...)})
+			second = model.(Model)
+			if second.repositories.pendingRestore == nil {
+				t.Fatal("no saved selection was offered")
+			}
+			if !strings.Contains(second.View(), "Restore the previous selection of 2 repositories?") {
+				t.Errorf("view does not offer the saved selection:\n%s", second.View())
+			}
+
+			second = pressKeys(second, tt.answer)
+			if got := selectedNames(second); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("selected = %v, want %v", got, tt.want)
+			}
+			if _, err := os.Stat(selectionPath(dir, "acme")); (err == nil) != tt.kept {
+				t.Errorf("state file kept = %v, want %v", err == nil, tt.kept)
+			}
+		})
+	}
+}
+
+func TestSelectionFileRoundTrip(t *testing.T) {
+	selected := map[string]bool{"acme/web": true, "acme/api": true, "acme/old": false}
+	tests := []struct {
+		name   string
+		file   string
+		prefix string
+	}{
+		{"json", "selection.json", "{"},
+		{"yaml", "selection.yaml", "organization: acme"},
+		{"yml", "selection.yml", "organization: acme"},
+		{"other extension is json", "selection.txt", "{"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := SaveSelectionFile(path, "acme", selected); err != nil {
+				t.Fatalf("SaveSelectionFile() error = %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), tt.prefix) {
+				t.Errorf("file starts %q, want %q", data, tt.prefix)
+			}
+
+			state, err := LoadSelectionFile(path)
+			if err != nil {
+				t.Fatalf("LoadSelectionFile() error = %v", err)
+			}
+			if state.Organization != "acme" || strings.Join(state.Repos, " ") != "acme/api acme/web" {
+				t.Errorf("LoadSelectionFile() = %+v, want acme/api and acme/web of acme", state)
+			}
+		})
+	}
+
+	if _, err := LoadSelectionFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadSelectionFile() of a missing file succeeded")
+	}
+	broken := filepath.Join(t.TempDir(), "broken.yaml")
+	if err := os.WriteFile(broken, []byte("repos: {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSelectionFile(broken); err == nil {
+		t.Error("LoadSelectionFile() of a malformed file succeeded")
+	}
+}
+
+func TestPreselectedRepositories(t *testing.T) {
+	tests := []struct {
+		name        string
+		preselected []string
+		want        []string
+		notice      string
+	}{
+		{"listed", []string{"acme/api", "ACME/Worker"}, []string{"acme/api", "acme/worker"}, ""},
+		{"no longer exists", []string{"acme/api", "acme/gone", "acme/removed"}, []string{"acme/api"}, "2 previously selected repositories no longer exist and were skipped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(t.Context(), nil, nil)
+			m.currentView = ViewRepositories
+			m.organization.name = "acme"
+			m.repositories.SetPreselected(tt.preselected)
+			model, _ := m.Update(reposMsg{repos: testRepositories("api", "web", "worker")})
+			m = model.(Model)
+
+			if got := selectedNames(m); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("selected = %v, want %v", got, tt.want)
+			}
+			if m.repositories.notice != tt.notice {
+				t.Errorf("notice = %q, want %q", m.repositories.notice, tt.notice)
+			}
+		})
+	}
+}
+
+func TestSaveSelectionKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "picked.yaml")
+	m := newRepositoriesTestModel(t, "api", "web")
+	if err := m.SetSelectionFile(path); err != nil {
+		t.Fatalf("SetSelectionFile() of a new file error = %v", err)
+	}
+	m = pressKeys(m, " ", "r")
+
+	state, err := LoadSelectionFile(path)
+	if err != nil {
+		t.Fatalf("r did not save the selection: %v", err)
+	}
+	if strings.Join(state.Repos, " ") != "acme/api" {
+		t.Errorf("saved %v, want acme/api", state.Repos)
+	}
+	if want := "Saved 1 selected repositories to " + path; m.repositories.notice != want {
+		t.Errorf("notice = %q, want %q", m.repositories.notice, want)
+	}
+
+	// A later session started with the same file pre-selects the saved repositories
+	next := NewModel(t.Context(), nil, nil)
+	if err := next.SetSelectionFile(path); err != nil {
+		t.Fatalf("SetSelectionFile() error = %v", err)
+	}
+	next.repositories.SetRepositories(testRepositories("api", "web"))
+	if got := selectedNames(next); strings.Join(got, " ") != "acme/api" {
+		t.Errorf("pre-selected %v, want acme/api", got)
+	}
+}
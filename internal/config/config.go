@@ -4,15 +4,38 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// SMTPConfig configures email notifications
+type SMTPConfig struct {
+	Host     string   `mapstructure:"host"`
+	Port     int      `mapstructure:"port"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+}
+
+// StrategyOverride sets the existing-repository strategy of repositories
+// whose "org/name" matches Repo, a glob such as "acme/legacy-*"
+type StrategyOverride struct {
+	Repo     string `mapstructure:"repo"`
+	Strategy string `mapstructure:"strategy"` // skip, overwrite, fetch-only, sync
+}
+
 // Config holds all configuration for the application
 type Config struct {
 	// GitHub configuration
 	GitHub struct {
-		Token string `mapstructure:"token"`
+		Token   string `mapstructure:"token"`
+		BaseURL string `mapstructure:"base_url"` // GitHub Enterprise Server URL, empty for github.com
+		// CacheTTL enables caching organization listings for this long (0 = no cache)
+		CacheTTL time.Duration `mapstructure:"cache_ttl"`
+		// Proxy routes API requests and clones through this proxy ("" = HTTPS_PROXY)
+		Proxy string `mapstructure:"proxy"`
 	} `mapstructure:"github"`
 
 	// Clone configuration
@@ -20,8 +43,27 @@ type Config struct {
 		MaxConcurrent    int    `mapstructure:"max_concurrent"`
 		ConnectTimeout   int    `mapstructure:"connect_timeout"`
 		OperationTimeout int    `mapstructure:"operation_timeout"`
+		UpdateTimeout    int    `mapstructure:"update_timeout"` // seconds; 0 = operation_timeout
 		OutputDir        string `mapstructure:"output_dir"`
-		ExistingRepos    string `mapstructure:"existing_repos"` // skip, overwrite, fetch-only
+		Layout           string `mapstructure:"layout"`         // nested, flat, flat-org
+		ExistingRepos    string `mapstructure:"existing_repos"` // skip, overwrite, fetch-only, sync
+		Backend          string `mapstructure:"backend"`        // exec, go-git
+		Submodules       bool   `mapstructure:"submodules"`
+		// BackupRetention is the number of backups kept per repository on overwrite (0 = keep all)
+		BackupRetention int `mapstructure:"backup_retention"`
+		// SkipIfNewerThan skips existing clones cloned or fetched within this window
+		SkipIfNewerThan time.Duration `mapstructure:"skip_if_newer_than"`
+		// PathTemplate computes clone targets instead of Layout, e.g. "{{.Org}}/{{.Language}}/{{.Repo}}"
+		PathTemplate string `mapstructure:"path_template"`
+		// PostHook is the post-clone hook used when --post-clone-hook is not given
+		PostHook string `mapstructure:"post_hook"`
+		// PostHookRequired fails a repository whose post-clone hook fails
+		PostHookRequired bool `mapstructure:"post_hook_required"`
+		// LanguageHooks maps a primary language to the post-clone hook for its repositories
+		LanguageHooks map[string]string `mapstructure:"language_hooks"`
+		// StrategyOverrides set existing_repos per repository; a list rather than a
+		// map because repository names may contain dots, viper's key separator
+		StrategyOverrides []StrategyOverride `mapstructure:"strategy_overrides"`
 	} `mapstructure:"clone"`
 
 	// Logging configuration
@@ -29,8 +71,24 @@ type Config struct {
 		Level  string `mapstructure:"level"`
 		Format string `mapstructure:"format"`
 		File   string `mapstructure:"file"`
+		// MaxSizeMB rotates the log file once it would grow past this size (0 = never)
+		MaxSizeMB int `mapstructure:"max_size_mb"`
+		// MaxBackups is the number of rotated log files kept (0 = keep all)
+		MaxBackups int `mapstructure:"max_backups"`
+		// MaxAgeDays removes rotated log files older than this (0 = keep all)
+		MaxAgeDays int `mapstructure:"max_age_days"`
 	} `mapstructure:"log"`
 
+	// Security configuration
+	Security struct {
+		AllowedOrgs []string `mapstructure:"allowed_orgs"` // empty means no restriction
+	} `mapstructure:"security"`
+
+	// Notification configuration
+	Notify struct {
+		SMTP SMTPConfig `mapstructure:"smtp"`
+	} `mapstructure:"notify"`
+
 	// Output configuration
 	Output struct {
 		Format string `mapstructure:"format"` // json, yaml
@@ -38,39 +96,43 @@ type Config struct {
 	} `mapstructure:"output"`
 }
 
-// LoadConfig loads the configuration from various sources
-func LoadConfig() (*Config, error) {
+// LoadConfig loads the configuration from various sources. A non-empty path
+// is read instead of searching for .zikrr.yaml and must exist.
+func LoadConfig(path string) (*Config, error) {
 	config := &Config{}
 
-	viper.SetDefault("clone.max_concurrent", 5)
-	viper.SetDefault("clone.connect_timeout", 60)
-	viper.SetDefault("clone.operation_timeout", 600)
-	viper.SetDefault("clone.existing_repos", "skip")
-	viper.SetDefault("log.level", "info")
-	viper.SetDefault("log.format", "text")
+	for key, value := range defaults {
+		viper.SetDefault(key, value)
+	}
 
 	// Environment variables
 	viper.SetEnvPrefix("ZIKRR")
 	viper.AutomaticEnv()
 
 	// Config file
-	configHome := os.Getenv("XDG_CONFIG_HOME")
-	if configHome == "" {
-		home, err := os.UserHomeDir()
+	if path != "" {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, fmt.Errorf("config file %s does not exist", path)
+		}
+		viper.SetConfigFile(path)
+		if err := viper.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	} else {
+		configHome, err := configDir()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get user home directory: %w", err)
+			return nil, err
 		}
-		configHome = filepath.Join(home, ".config")
-	}
 
-	viper.AddConfigPath(configHome)
-	viper.AddConfigPath(".")
-	viper.SetConfigName(".zikrr")
-	viper.SetConfigType("yaml")
+		viper.AddConfigPath(configHome)
+		viper.AddConfigPath(".")
+		viper.SetConfigName(".zikrr")
+		viper.SetConfigType("yaml")
 
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+		if err := viper.ReadInConfig(); err != nil {
+			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+				return nil, fmt.Errorf("failed to read config file: %w", err)
+			}
 		}
 	}
 
@@ -81,18 +143,33 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// configDir returns $XDG_CONFIG_HOME, or ~/.config when it is unset
+func configDir() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return configHome, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+// DefaultPath returns the config file written by SaveConfig and found first by LoadConfig
+func DefaultPath() (string, error) {
+	configHome, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configHome, ".zikrr.yaml"), nil
+}
+
 // SaveConfig saves the current configuration to file
 func SaveConfig(config *Config) error {
-	configHome := os.Getenv("XDG_CONFIG_HOME")
-	if configHome == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get user home directory: %w", err)
-		}
-		configHome = filepath.Join(home, ".config")
+	configFile, err := DefaultPath()
+	if err != nil {
+		return err
 	}
-
-	configFile := filepath.Join(configHome, ".zikrr.yaml")
 	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
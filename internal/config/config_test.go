@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadConfigPath(t *testing.T) {
+	dir := t.TempDir()
+	explicit := filepath.Join(dir, "custom.yaml")
+	writeConfig(t, explicit, "clone:\n  output_dir: /srv/explicit\n")
+	malformed := filepath.Join(dir, "broken.yaml")
+	writeConfig(t, malformed, "clone: [unclosed\n")
+	xdg := filepath.Join(dir, "xdg")
+	writeConfig(t, filepath.Join(xdg, ".zikrr.yaml"), "clone:\n  output_dir: /srv/searched\n")
+
+	tests := []struct {
+		name       string
+		path       string
+		configHome string
+		want       string
+		wantErr    string
+	}{
+		{"explicit file", explicit, xdg, "/srv/explicit", ""},
+		{"explicit file missing", filepath.Join(dir, "missing.yaml"), xdg, "", "does not exist"},
+		{"explicit file malformed", malformed, xdg, "", "failed to read config file"},
+		{"searched file", "", xdg, "/srv/searched", ""},
+		{"no file found", "", filepath.Join(dir, "empty"), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			t.Setenv("XDG_CONFIG_HOME", tt.configHome)
+
+			cfg, err := LoadConfig(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig(%q) error = %v, want %q", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig(%q) error = %v", tt.path, err)
+			}
+			if cfg.Clone.OutputDir != tt.want {
+				t.Errorf("output_dir = %q, want %q", cfg.Clone.OutputDir, tt.want)
+			}
+			// Defaults apply whichever file was read
+			if cfg.Clone.MaxConcurrent != 5 {
+				t.Errorf("max_concurrent = %d, want the default 5", cfg.Clone.MaxConcurrent)
+			}
+		})
+	}
+}
+
+// writeConfig writes a config file, creating its directory
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
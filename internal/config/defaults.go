@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// defaults are the values of settings missing from the config file
+var defaults = map[string]interface{}{
+	"clone.max_concurrent":    5,
+	"clone.connect_timeout":   60,
+	"clone.operation_timeout": 600,
+	"clone.existing_repos":    "skip",
+	"clone.backend":           "exec",
+	"clone.backup_retention":  3,
+	"log.level":               "info",
+	"log.format":              "text",
+}
+
+// defaultFile is the commented config file written by WriteDefault; optional
+// settings are left commented out
+var defaultFile = template.Must(template.New("config").Parse(`# zikrr configuration
+# Flags given on the command line override these settings.
+
+github:
+  # Personal access token; GITHUB_TOKEN and --token take precedence
+  # token: ghp_...
+  # GitHub Enterprise Server URL, empty for github.com
+  # base_url: https://github.example.com
+  # Cache organization listings for this long
+  # cache_ttl: 1h
+  # Proxy for API requests and clones (default HTTPS_PROXY)
+  # proxy: http://proxy.example.com:3128
+
+clone:
+  max_concurrent: {{index . "clone.max_concurrent"}}
+  # Seconds a transfer may stall before it is aborted, and a whole clone may take
+  connect_timeout: {{index . "clone.connect_timeout"}}
+  operation_timeout: {{index . "clone.operation_timeout"}}
+  # Seconds the fetch of an existing clone may take (default operation_timeout)
+  # update_timeout: 300
+  # output_dir: ~/src
+  # nested (<org>/<repo>), flat (<repo>) or flat-org (<org>-<repo>)
+  # layout: nested
+  # path_template: "{{"{{.Org}}/{{.Language}}/{{.Repo}}"}}"
+  # skip, overwrite, fetch-only or sync
+  existing_repos: {{index . "clone.existing_repos"}}
+  # strategy_overrides:
+  #   - repo: "acme/legacy-*"
+  #     strategy: overwrite
+  # Backups kept per repository by --backup-on-overwrite (0 = keep all)
+  backup_retention: {{index . "clone.backup_retention"}}
+  # exec or go-git
+  backend: {{index . "clone.backend"}}
+  # submodules: false
+  # Skip existing clones cloned or fetched within this window
+  # skip_if_newer_than: 30m
+  # post_hook: make setup
+  # post_hook_required: false
+  # language_hooks:
+  #   go: go mod download
+
+log:
+  # debug, info, warn or error
+  level: {{index . "log.level"}}
+  # text or json
+  format: {{index . "log.format"}}
+  # file: zikrr.log
+  # max_size_mb: 10
+  # max_backups: 5
+  # max_age_days: 30
+
+# security:
+#   allowed_orgs: [acme]
+
+# output:
+#   format: json
+#   file: summary.json
+`))
+
+// WriteDefault writes a commented config file with the default settings to
+// path, refusing to replace an existing file unless force is set
+func WriteDefault(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("config file %s already exists (use --force to overwrite it)", path)
+	}
+
+	var buf bytes.Buffer
+	if err := defaultFile.Execute(&buf, defaults); err != nil {
+		return fmt.Errorf("failed to render default config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
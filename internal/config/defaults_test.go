@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestWriteDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zikrr", ".zikrr.yaml")
+	if err := WriteDefault(path, false); err != nil {
+		t.Fatalf("WriteDefault() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"max_concurrent: 5", "existing_repos: skip", "backend: exec", "backup_retention: 3", "level: info", "# token: ghp_...", "# strategy_overrides:"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("default config does not contain %q:\n%s", want, content)
+		}
+	}
+
+	// The written file loads back to the defaults
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Clone.MaxConcurrent != 5 || cfg.Clone.ExistingRepos != "skip" || cfg.Log.Format != "text" || cfg.Clone.BackupRetention != 3 {
+		t.Errorf("loaded defaults = %d, %q, %q, %d", cfg.Clone.MaxConcurrent, cfg.Clone.ExistingRepos, cfg.Log.Format, cfg.Clone.BackupRetention)
+	}
+
+	tests := []struct {
+		name    string
+		force   bool
+		wantErr bool
+	}{
+		{"existing file kept", false, true},
+		{"existing file forced", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte("edited\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			err := WriteDefault(path, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteDefault(force %v) error = %v, wantErr %v", tt.force, err, tt.wantErr)
+			}
+			content, _ := os.ReadFile(path)
+			if kept := string(content) == "edited\n"; kept != tt.wantErr {
+				t.Errorf("existing file kept = %v, want %v", kept, tt.wantErr)
+			}
+		})
+	}
+}
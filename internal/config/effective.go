@@ -0,0 +1,48 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// redactedValue replaces secrets in the effective configuration
+const redactedValue = "REDACTED"
+
+// isSecretKey reports whether a setting or flag name holds a secret
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "token") || strings.Contains(key, "password") || strings.Contains(key, "secret")
+}
+
+// redactSettings replaces set secret values in settings, recursing into nested maps
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redacted[key] = redactSettings(v)
+		default:
+			if isSecretKey(key) && value != nil && value != "" {
+				value = redactedValue
+			}
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+// Effective returns the merged configuration used for the run (defaults, config
+// file and environment) with the explicitly set flags under "flags". Tokens,
+// passwords and other secrets are redacted.
+func Effective(flags map[string]string) map[string]interface{} {
+	settings := viper.AllSettings()
+	if len(flags) > 0 {
+		flagSettings := make(map[string]interface{}, len(flags))
+		for name, value := range flags {
+			flagSettings[name] = value
+		}
+		settings["flags"] = flagSettings
+	}
+	return redactSettings(settings)
+}
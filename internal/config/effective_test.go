@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestEffective(t *testing.T) {
+	const file = `
+github:
+  token: ghp_fromfile
+  base_url: https://github.example.com
+clone:
+  max_concurrent: 3
+  output_dir: /srv/src
+notify:
+  smtp:
+    host: smtp.example.com
+    password: hunter2
+`
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	path := filepath.Join(t.TempDir(), "zikrr.yaml")
+	if err := os.WriteFile(path, []byte(file), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := Effective(map[string]string{"token": "ghp_fromflag", "max-concurrent": "8", "no-tui": "true"})
+	lookup := func(keys ...string) interface{} {
+		var value interface{} = settings
+		for _, key := range keys {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = m[key]
+		}
+		return value
+	}
+
+	tests := []struct {
+		keys []string
+		want interface{}
+	}{
+		{[]string{"github", "token"}, redactedValue},
+		{[]string{"notify", "smtp", "password"}, redactedValue},
+		{[]string{"flags", "token"}, redactedValue},
+		{[]string{"github", "base_url"}, "https://github.example.com"},
+		{[]string{"notify", "smtp", "host"}, "smtp.example.com"},
+		{[]string{"clone", "max_concurrent"}, 3},
+		{[]string{"clone", "output_dir"}, "/srv/src"},
+		{[]string{"clone", "existing_repos"}, "skip"},
+		{[]string{"log", "level"}, "info"},
+		{[]string{"flags", "max-concurrent"}, "8"},
+		{[]string{"flags", "no-tui"}, "true"},
+	}
+	for _, tt := range tests {
+		if got := lookup(tt.keys...); got != tt.want {
+			t.Errorf("%v = %v, want %v", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestRedactSettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value interface{}
+		want  interface{}
+	}{
+		{"token", "token", "ghp_secret", redactedValue},
+		{"mixed case", "API_Token", "ghp_secret", redactedValue},
+		{"secret", "client_secret", "s3cret", redactedValue},
+		{"unset secret", "password", "", ""},
+		{"nil secret", "token", nil, nil},
+		{"plain setting", "layout", "flat", "flat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactSettings(map[string]interface{}{tt.key: tt.value})[tt.key]
+			if got != tt.want {
+				t.Errorf("redactSettings(%s=%v) = %v, want %v", tt.key, tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// adaptiveInterval is how often adaptive concurrency re-reads the rate limit
+const adaptiveInterval = 10 * time.Second
+
+// RateLimit is the API quota reported by a RateLimitProvider
+type RateLimit struct {
+	Remaining int
+	Limit     int
+	Reset     time.Time
+}
+
+// RateLimitProvider reports the current API rate limit, e.g. of the GitHub client
+type RateLimitProvider interface {
+	RateLimit(ctx context.Context) (RateLimit, error)
+}
+
+// SetRateLimitProvider makes CloneRepositories scale concurrency with the
+// share of the rate limit remaining and hold new clones while it is
+// exhausted (nil disables)
+func (c *ConcurrentCloner) SetRateLimitProvider(provider RateLimitProvider) {
+	c.rateLimits = provider
+}
+
+// adaptiveLimit scales max by the remaining share of the rate limit, keeping at least one slot
+func adaptiveLimit(max int, rate RateLimit) int {
+	if rate.Limit <= 0 {
+		return max
+	}
+	limit := (max*rate.Remaining + rate.Limit - 1) / rate.Limit
+	if limit < 1 {
+		return 1
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
+
+// adapt follows the rate limit until done closes. If ctx is cancelled first,
+// the full limit is restored so clones waiting for a slot can finish.
+func (c *ConcurrentCloner) adapt(ctx context.Context, done <-chan struct{}) {
+	defer c.limiter.setLimit(c.maxConcurrent)
+	ticker := time.NewTicker(adaptiveInterval)
+	defer ticker.Stop()
+
+	for {
+		c.applyRateLimit(ctx)
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyRateLimit reads the rate limit once and sets the concurrency for it;
+// an exhausted limit pauses new clones until a later read shows it reset
+func (c *ConcurrentCloner) applyRateLimit(ctx context.Context) {
+	rate, err := c.rateLimits.RateLimit(ctx)
+	if err != nil {
+		util.Debug(fmt.Sprintf("Keeping clone concurrency, rate limit unavailable: %v", err))
+		return
+	}
+	if rate.Remaining <= 0 && time.Until(rate.Reset) > 0 {
+		util.Info(fmt.Sprintf("Rate limit exhausted, holding new clones until %s", rate.Reset.Local().Format(time.RFC1123)))
+		c.limiter.pause()
+		return
+	}
+	limit := adaptiveLimit(c.maxConcurrent, rate)
+	c.limiter.setLimit(limit)
+	util.Debug(fmt.Sprintf("Set clone concurrency to %d/%d for %d/%d remaining API requests", limit, c.maxConcurrent, rate.Remaining, rate.Limit))
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRateLimits returns its readings in turn, repeating the last one
+type fakeRateLimits struct {
+	mu       sync.Mutex
+	readings []RateLimit
+	err      error
+	calls    int
+}
+
+func (f *fakeRateLimits) RateLimit(ctx context.Context) (RateLimit, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := f.calls
+	f.calls++
+	if f.err != nil {
+		return RateLimit{}, f.err
+	}
+	if i >= len(f.readings) {
+		i = len(f.readings) - 1
+	}
+	return f.readings[i], nil
+}
+
+// limitOf returns the current limit of l
+func limitOf(l *limiter) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+func TestAdaptiveLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int
+		rate RateLimit
+		want int
+	}{
+		{"full quota", 8, RateLimit{Remaining: 5000, Limit: 5000}, 8},
+		{"half quota", 8, RateLimit{Remaining: 2500, Limit: 5000}, 4},
+		{"rounds up", 8, RateLimit{Remaining: 1, Limit: 5000}, 1},
+		{"nothing left", 8, RateLimit{Remaining: 0, Limit: 5000}, 1},
+		{"unknown limit", 8, RateLimit{}, 8},
+		{"more than the limit", 8, RateLimit{Remaining: 6000, Limit: 5000}, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adaptiveLimit(tt.max, tt.rate); got != tt.want {
+				t.Errorf("adaptiveLimit(%d, %d/%d) = %d, want %d", tt.max, tt.rate.Remaining, tt.rate.Limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRateLimitShrinking(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	provider := &fakeRateLimits{readings: []RateLimit{
+		{Remaining: 5000, Limit: 5000, Reset: reset},
+		{Remaining: 3000, Limit: 5000, Reset: reset},
+		{Remaining: 1000, Limit: 5000, Reset: reset},
+		{Remaining: 0, Limit: 5000, Reset: reset},
+		{Remaining: 5000, Limit: 5000, Reset: reset.Add(time.Hour)},
+	}}
+	cloner := NewConcurrentCloner(10)
+	cloner.SetRateLimitProvider(provider)
+
+	// An exhausted quota pauses new clones until the next reading
+	for i, want := range []int{10, 6, 2, 0, 10} {
+		cloner.applyRateLimit(context.Background())
+		if got := limitOf(cloner.limiter); got != want {
+			t.Errorf("limit after reading %d = %d, want %d", i+1, got, want)
+		}
+	}
+}
+
+func TestApplyRateLimitUnavailable(t *testing.T) {
+	cloner := NewConcurrentCloner(4)
+	cloner.limiter.setLimit(2)
+	cloner.SetRateLimitProvider(&fakeRateLimits{err: errors.New("rate limit endpoint down")})
+	cloner.applyRateLimit(context.Background())
+	if got := limitOf(cloner.limiter); got != 2 {
+		t.Errorf("limit = %d after a failed reading, want it kept at 2", got)
+	}
+}
+
+func TestAdaptRestoresLimitWhenCancelled(t *testing.T) {
+	cloner := NewConcurrentCloner(4)
+	cloner.SetRateLimitProvider(&fakeRateLimits{readings: []RateLimit{{Remaining: 0, Limit: 5000, Reset: time.Now().Add(time.Hour)}}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Clones waiting out the exhausted quota must be able to finish cancelling
+	cloner.adapt(ctx, make(chan struct{}))
+	if got := limitOf(cloner.limiter); got != 4 {
+		t.Errorf("limit = %d after cancellation, want the full 4", got)
+	}
+}
+
+func TestCloneAllReadsRateLimit(t *testing.T) {
+	provider := &fakeRateLimits{readings: []RateLimit{{Remaining: 100, Limit: 5000, Reset: time.Now().Add(time.Hour)}}}
+	manager := NewRepositoryManager(t.TempDir(), 4)
+	manager.SetCloneDefaults(testCloneOptions("", ""))
+	manager.SetRateLimitProvider(provider)
+	var repos []*Repository
+	for _, name := range []string{"api", "web", "docs"} {
+		repos = append(repos, manager.AddRepository("acme", name, newFixtureRemote(t), "", SkipExisting))
+	}
+	for range manager.CloneAll(t.Context()) {
+	}
+
+	for _, repo := range repos {
+		if status, err, _ := repo.GetStatus(); status != StatusSuccess {
+			t.Errorf("%s = %v (%v), want success at a single slot", repo.Name, status, err)
+		}
+	}
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.calls == 0 {
+		t.Error("CloneAll never read the rate limit")
+	}
+}
@@ -0,0 +1,55 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// Supported values of CloneOptions.Backend
+const (
+	BackendExec  = "exec"
+	BackendGoGit = "go-git"
+)
+
+// Backend performs the git operations of a clone or update
+type Backend interface {
+	// Clone clones opts.URL into opts.TargetDir, reporting whether the repository is empty
+	Clone(ctx context.Context, opts CloneOptions) (empty bool, err error)
+	// Update fetches an existing clone in opts.TargetDir and resets it to the
+	// remote branch (the FetchOnly strategy)
+	Update(ctx context.Context, opts CloneOptions) error
+}
+
+// backendFor returns the backend selected by opts.Backend
+func backendFor(opts CloneOptions) Backend {
+	if opts.Backend == BackendGoGit {
+		return goGitBackend{}
+	}
+	return execBackend{}
+}
+
+// execBackend runs the system git binary
+type execBackend struct{}
+
+// Clone runs `git clone` with the arguments built from opts
+func (execBackend) Clone(ctx context.Context, opts CloneOptions) (bool, error) {
+	cmd := gitCommand(ctx, opts, buildCloneArgs(opts)...)
+	util.Debug(redactToken(fmt.Sprintf("Running git command: %v", cmd.Args), opts.Token))
+
+	// Stdout and stderr share one writer so git's output stays in order
+	writer := newProgressWriter(opts.PercentFunc)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	err := cmd.Run()
+	output := writer.Output()
+	if err != nil {
+		if authErr := authRequired(string(output), opts); authErr != nil {
+			return false, authErr
+		}
+		return false, fmt.Errorf("clone failed: %w\nOutput: %s", err, redactToken(string(output), opts.Token))
+	}
+	stripCredentials(ctx, opts)
+	return isEmptyCloneOutput(string(output)), nil
+}
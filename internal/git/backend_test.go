@@ -0,0 +1,95 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBackendsClone(t *testing.T) {
+	remote := newFixtureRemote(t, "develop")
+	empty := filepath.Join(t.TempDir(), "empty.git")
+	runGit(t, filepath.Dir(empty), "init", "--bare", empty)
+
+	tests := []struct {
+		name   string
+		remote string
+		branch string
+		// wantBranch is the branch checked out, "" for an empty repository
+		wantBranch string
+		wantEmpty  bool
+	}{
+		{"default branch", remote, "", "main", false},
+		{"named branch", remote, "develop", "develop", false},
+		{"empty repository", empty, "", "", true},
+	}
+	for _, backend := range []string{BackendExec, BackendGoGit} {
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				target := filepath.Join(t.TempDir(), "clone")
+				opts := testCloneOptions(tt.remote, target)
+				opts.Backend = backend
+				opts.Branch = tt.branch
+
+				result := cloneOne(t, opts)
+				if !result.Success {
+					t.Fatalf("clone failed: %v", result.Error)
+				}
+				if result.Empty != tt.wantEmpty {
+					t.Errorf("Empty = %v, want %v", result.Empty, tt.wantEmpty)
+				}
+				if tt.wantEmpty {
+					return
+				}
+				if got := runGit(t, target, "rev-parse", "--abbrev-ref", "HEAD"); got != tt.wantBranch {
+					t.Errorf("checked out %q, want %q", got, tt.wantBranch)
+				}
+				if got := readFile(t, filepath.Join(target, "README.md")); got != "fixture\n" {
+					t.Errorf("README.md = %q, want the fixture content", got)
+				}
+			})
+		}
+	}
+}
+
+func TestBackendsUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		// dirty is written to the clone before the update, which must discard it
+		dirty string
+	}{
+		{"clean worktree", ""},
+		{"local changes", "local edit\n"},
+	}
+	for _, backend := range []string{BackendExec, BackendGoGit} {
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				remote := newFixtureRemote(t)
+				target := filepath.Join(t.TempDir(), "clone")
+				opts := testCloneOptions(remote, target)
+				opts.Backend = backend
+				if result := cloneOne(t, opts); !result.Success {
+					t.Fatalf("clone failed: %v", result.Error)
+				}
+
+				pushCommit(t, remote, "CHANGELOG.md", "v2\n")
+				if tt.dirty != "" {
+					writeFile(t, filepath.Join(target, "README.md"), tt.dirty)
+				}
+				opts.ExistingRepo = FetchOnly
+				if result := cloneOne(t, opts); !result.Success {
+					t.Fatalf("update failed: %v", result.Error)
+				}
+
+				if got := readFile(t, filepath.Join(target, "CHANGELOG.md")); got != "v2\n" {
+					t.Errorf("CHANGELOG.md = %q, want the pushed content", got)
+				}
+				if got := readFile(t, filepath.Join(target, "README.md")); got != "fixture\n" {
+					t.Errorf("README.md = %q, want it reset to the remote", got)
+				}
+				if local, upstream := runGit(t, target, "rev-parse", "HEAD"), runGit(t, remote, "rev-parse", "main"); local != upstream {
+					t.Errorf("HEAD = %s, want the remote main %s", local, upstream)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Clone retry delays start at baseBackoff and double per attempt up to the cap
+const (
+	baseBackoff       = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// backoffRand returns a random fraction in [0, 1) for jitter; replace it with a
+// seeded source for deterministic delays
+var backoffRand = rand.Float64
+
+// retryBackoff returns the delay before retry attempt (1 = first retry):
+// min(baseBackoff<<(attempt-1), maxBackoff), of which the upper half is
+// randomized so concurrent clones don't retry in lockstep. The result never
+// exceeds maxBackoff.
+func retryBackoff(attempt int, maxBackoff time.Duration) time.Duration {
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+
+	backoff := maxBackoff
+	if shift := attempt - 1; shift >= 0 && shift < 32 {
+		if d := baseBackoff << uint(shift); d < maxBackoff {
+			backoff = d
+		}
+	}
+
+	half := backoff / 2
+	return half + time.Duration(backoffRand()*float64(backoff-half))
+}
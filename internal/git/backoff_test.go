@@ -0,0 +1,55 @@
+package git
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempt    int
+		maxBackoff time.Duration
+		// jitter is the random fraction drawn
+		jitter float64
+		want   time.Duration
+	}{
+		{"first retry, no jitter", 1, time.Minute, 0, 500 * time.Millisecond},
+		{"first retry, full jitter", 1, time.Minute, 1, time.Second},
+		{"third retry", 3, time.Minute, 0.5, 3 * time.Second},
+		{"capped", 10, 30 * time.Second, 1, 30 * time.Second},
+		{"capped, no jitter", 10, 30 * time.Second, 0, 15 * time.Second},
+		{"default cap", 20, 0, 1, DefaultMaxBackoff},
+		{"shift overflow", 100, 30 * time.Second, 1, 30 * time.Second},
+		{"cap below base", 1, 200 * time.Millisecond, 1, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := backoffRand
+			backoffRand = func() float64 { return tt.jitter }
+			t.Cleanup(func() { backoffRand = saved })
+
+			if got := retryBackoff(tt.attempt, tt.maxBackoff); got != tt.want {
+				t.Errorf("retryBackoff(%d, %v) = %v, want %v", tt.attempt, tt.maxBackoff, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryBackoffNeverExceedsCap(t *testing.T) {
+	saved := backoffRand
+	backoffRand = rand.New(rand.NewSource(1)).Float64
+	t.Cleanup(func() { backoffRand = saved })
+
+	for _, maxBackoff := range []time.Duration{time.Second, 5 * time.Second, 30 * time.Second} {
+		for attempt := 1; attempt <= 64; attempt++ {
+			for i := 0; i < 20; i++ {
+				got := retryBackoff(attempt, maxBackoff)
+				if got > maxBackoff || got < 0 {
+					t.Fatalf("retryBackoff(%d, %v) = %v, outside [0, %v]", attempt, maxBackoff, got, maxBackoff)
+				}
+			}
+		}
+	}
+}
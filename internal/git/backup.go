@@ -0,0 +1,78 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// backupTimeFormat is used in backup directory names so they sort chronologically
+const backupTimeFormat = "20060102-150405"
+
+// backupMarker separates a repository directory name from its backup timestamp
+const backupMarker = ".bak-"
+
+// backupPath returns a free backup location for a repository directory at the
+// given time; a counter is appended when that second already has a backup
+func backupPath(dir string, t time.Time) string {
+	base := filepath.Clean(dir) + backupMarker + t.Format(backupTimeFormat)
+	path := base
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// isBackupDir reports whether a directory name is a backup made on overwrite
+func isBackupDir(name string) bool {
+	return strings.Contains(name, backupMarker)
+}
+
+// backupExistingRepo moves an existing repository to a timestamped backup
+// directory instead of deleting it, then prunes old backups
+func backupExistingRepo(opts CloneOptions) error {
+	target := backupPath(opts.TargetDir, time.Now())
+	util.Info(fmt.Sprintf("Backing up existing repository %s to %s", opts.TargetDir, target))
+	opts.ProgressFunc(fmt.Sprintf("Backing up existing repository: %s", opts.TargetDir))
+
+	if err := os.Rename(opts.TargetDir, target); err != nil {
+		util.Error("Failed to back up existing repository", err)
+		return fmt.Errorf("failed to back up existing repository: %w", err)
+	}
+
+	if err := pruneBackups(opts.TargetDir, opts.BackupRetention); err != nil {
+		util.Warn(fmt.Sprintf("Failed to prune old backups of %s: %v", opts.TargetDir, err))
+	}
+	return nil
+}
+
+// pruneBackups removes the oldest backups of dir beyond the retention count
+func pruneBackups(dir string, retention int) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(filepath.Clean(dir) + backupMarker + "*")
+	if err != nil {
+		return err
+	}
+	if len(backups) <= retention {
+		return nil
+	}
+
+	sort.Strings(backups)
+	for _, old := range backups[:len(backups)-retention] {
+		util.Debug(fmt.Sprintf("Removing old backup: %s", old))
+		if err := os.RemoveAll(old); err != nil {
+			return fmt.Errorf("failed to remove backup %s: %w", old, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackupPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "acme", "api")
+	at := time.Date(2026, 3, 1, 12, 30, 45, 0, time.UTC)
+
+	want := dir + ".bak-20260301-123045"
+	if got := backupPath(dir+"/", at); got != want {
+		t.Fatalf("backupPath() = %q, want %q", got, want)
+	}
+
+	// Later backups within the same second get a counter
+	for i, suffix := range []string{"-1", "-2"} {
+		if err := os.MkdirAll(backupPath(dir, at), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if got := backupPath(dir, at); got != want+suffix {
+			t.Errorf("backup %d: backupPath() = %q, want %q", i+2, got, want+suffix)
+		}
+	}
+}
+
+func TestOverwriteBacksUpOrDeletes(t *testing.T) {
+	remote := newFixtureRemote(t)
+	tests := []struct {
+		name        string
+		backup      bool
+		overwrites  int
+		wantBackups int
+	}{
+		{"delete", false, 1, 0},
+		{"back up", true, 1, 1},
+		{"back up twice within a second", true, 2, 2},
+		{"retention", true, 5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(t.TempDir(), "acme", "api")
+			opts := testCloneOptions(remote, target)
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+
+			opts.ExistingRepo = OverwriteExisting
+			opts.BackupOnOverwrite = tt.backup
+			for i := 0; i < tt.overwrites; i++ {
+				writeFile(t, filepath.Join(target, "local.txt"), "local change\n")
+				if result := cloneOne(t, opts); !result.Success {
+					t.Fatalf("overwrite %d failed: %v", i+1, result.Error)
+				}
+			}
+
+			if _, err := os.Stat(filepath.Join(target, "local.txt")); !os.IsNotExist(err) {
+				t.Error("overwritten clone still has the local change")
+			}
+			backups, _ := filepath.Glob(target + ".bak-*")
+			if len(backups) != tt.wantBackups {
+				t.Fatalf("found %d backups %v, want %d", len(backups), backups, tt.wantBackups)
+			}
+			for _, backup := range backups {
+				if _, err := os.Stat(filepath.Join(backup, "local.txt")); err != nil {
+					t.Errorf("backup %s lacks the local change: %v", backup, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFindLocalRepositoriesSkipsBackups(t *testing.T) {
+	remote := newFixtureRemote(t)
+	base := t.TempDir()
+	target := filepath.Join(base, "acme", "api")
+	opts := testCloneOptions(remote, target)
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+	opts.ExistingRepo = OverwriteExisting
+	opts.BackupOnOverwrite = true
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("overwrite failed: %v", result.Error)
+	}
+
+	repos, err := FindLocalRepositories(context.Background(), base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Dir != target {
+		t.Errorf("FindLocalRepositories() = %+v, want only %s", repos, target)
+	}
+}
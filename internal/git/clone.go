@@ -2,10 +2,13 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,29 +25,125 @@ const (
 	OverwriteExisting
 	// FetchOnly updates the existing repository without re-cloning
 	FetchOnly
+	// Sync clones new repositories and updates existing ones so repeated runs
+	// converge on the same state; a directory holding a different repository is an error
+	Sync
 )
 
+// ParseExistingRepoStrategy parses a strategy name (skip, overwrite, fetch-only, sync)
+func ParseExistingRepoStrategy(name string) (ExistingRepoStrategy, error) {
+	switch strings.ToLower(name) {
+	case "", "skip":
+		return SkipExisting, nil
+	case "overwrite":
+		return OverwriteExisting, nil
+	case "fetch-only", "fetch":
+		return FetchOnly, nil
+	case "sync":
+		return Sync, nil
+	default:
+		return SkipExisting, fmt.Errorf("unknown existing repository strategy %q (use skip, overwrite, fetch-only or sync)", name)
+	}
+}
+
+// String returns the strategy name accepted by ParseExistingRepoStrategy
+func (s ExistingRepoStrategy) String() string {
+	switch s {
+	case OverwriteExisting:
+		return "overwrite"
+	case FetchOnly:
+		return "fetch-only"
+	case Sync:
+		return "sync"
+	default:
+		return "skip"
+	}
+}
+
 // CloneOptions represents options for cloning a repository
 type CloneOptions struct {
-	URL          string
-	TargetDir    string
-	Branch       string
-	Timeout      time.Duration
-	MaxRetries   int
+	URL       string
+	TargetDir string
+	Branch    string
+	// Tag checks out this tag, detached, instead of a branch; it excludes Branch
+	Tag string
+	// Mode makes bare or mirror clones instead of clones with a working tree
+	Mode CloneMode
+	// DefaultBranch is the repository's default branch; -b is omitted when Branch matches it
+	DefaultBranch string
+	Timeout       time.Duration
+	MaxRetries    int
+	// MaxBackoff caps the delay between clone retries (0 = DefaultMaxBackoff)
+	MaxBackoff   time.Duration
 	ProgressFunc func(status string)
+	// PercentFunc receives the phase and percentage (0-100) parsed from git's progress output
+	PercentFunc  func(phase string, percent float64)
 	ConnTimeout  time.Duration
 	CloneTimeout time.Duration
-	ExistingRepo ExistingRepoStrategy
+	// UpdateTimeout bounds the fetch of a FetchOnly update (0 = CloneTimeout)
+	UpdateTimeout time.Duration
+	ExistingRepo  ExistingRepoStrategy
+	Backend       string // git implementation: exec (default) or go-git
+	Jobs          int    // parallel jobs for submodules and fetches (0 = git default)
+	// Token authenticates HTTPS clones of private repositories; it is redacted from all output
+	Token string
+	// TokenHost limits sending Token to URLs on this host (empty = any host)
+	TokenHost string
+	// Proxy routes HTTP(S) remotes through this proxy (empty = git's own proxy settings)
+	Proxy string
+	// Depth creates a shallow clone with this many commits of history (0 = full clone)
+	Depth int
+	// Submodules clones and updates submodules recursively
+	Submodules bool
+	// KeepExtensions prunes working-tree files without one of these extensions after cloning
+	KeepExtensions []string
+
+	// LFSSkipSmudge clones LFS pointer files without downloading their content
+	LFSSkipSmudge bool
+
+	// SSHKeyPath is a private key used for SSH remotes instead of ~/.ssh/config
+	SSHKeyPath string
+	// SSHCommand replaces the ssh command for SSH remotes (GIT_SSH_COMMAND)
+	SSHCommand string
+
+	// Worktrees lists extra branches (path.Match patterns such as release/*)
+	// checked out as worktrees alongside the primary checkout
+	Worktrees []string
+
+	// SlowThreshold flags successful clones taking longer than this as slow (0 = disabled)
+	SlowThreshold time.Duration
+
+	// PostCloneHook is a shell command run in the repository after a successful clone or update
+	PostCloneHook string
+	// LanguageHooks maps a repository's primary language (case-insensitive) to the
+	// hook run instead of PostCloneHook for repositories in that language
+	LanguageHooks map[string]string
+	// PostCloneRetries is the number of times a failed hook is retried
+	PostCloneRetries int
+	// PostCloneRetryCodes limits retries to these exit codes (empty = retry any failure)
+	PostCloneRetryCodes []int
+	// PostCloneBackoff is the initial delay between hook retries, doubled each retry
+	PostCloneBackoff time.Duration
+	// PostCloneHookRequired fails the repository when its hook fails instead of only recording the error
+	PostCloneHookRequired bool
+
+	// BackupOnOverwrite moves an existing repository aside instead of deleting it
+	BackupOnOverwrite bool
+	// BackupRetention is the number of backups kept per repository (0 = keep all)
+	BackupRetention int
 }
 
 // DefaultCloneOptions returns default clone options
 func DefaultCloneOptions() CloneOptions {
 	return CloneOptions{
-		MaxRetries:   3,
-		ConnTimeout:  60 * time.Second,
-		CloneTimeout: 10 * time.Minute,
-		ProgressFunc: func(status string) {}, // No-op by default
-		ExistingRepo: SkipExisting,
+		MaxRetries:      3,
+		MaxBackoff:      DefaultMaxBackoff,
+		ConnTimeout:     60 * time.Second,
+		CloneTimeout:    10 * time.Minute,
+		ProgressFunc:    func(status string) {}, // No-op by default
+		PercentFunc:     func(phase string, percent float64) {},
+		ExistingRepo:    SkipExisting,
+		BackupRetention: 3,
 	}
 }
 
@@ -53,12 +152,52 @@ type CloneResult struct {
 	RepoURL string
 	Success bool
 	Error   error
+	Phases  []PhaseTiming
+	// ClonedBranch is the branch checked out after the clone or update
+	ClonedBranch string
+	// Duration is the wall-clock time of the clone or update, including the post-clone hook
+	Duration time.Duration
+	Empty    bool // cloned successfully but the repository has no commits
+	Slow     bool // succeeded but took longer than CloneOptions.SlowThreshold
+	// HookError is set when the post-clone hook failed; the clone itself still
+	// succeeded unless CloneOptions.PostCloneHookRequired is set
+	HookError error
+	// HookOutput is the combined output of the post-clone hook
+	HookOutput string
+}
+
+// isSlow reports whether a clone taking d exceeded the slow threshold
+func isSlow(d, threshold time.Duration) bool {
+	return threshold > 0 && d > threshold
+}
+
+// emptyRepoWarning is printed by git when cloning a repository with no commits
+const emptyRepoWarning = "You appear to have cloned an empty repository"
+
+// retryPrefix starts the progress message sent before each clone retry
+const retryPrefix = "Retrying in "
+
+// isEmptyCloneOutput reports whether git clone output indicates an empty repository
+func isEmptyCloneOutput(output string) bool {
+	return strings.Contains(output, emptyRepoWarning)
+}
+
+// cloneOutcome collects details about a clone operation beyond success or failure
+type cloneOutcome struct {
+	trace      cloneTrace
+	empty      bool
+	skipped    bool // an existing clone was left as it was
+	branch     string
+	hookError  error
+	hookOutput string
 }
 
 // ConcurrentCloner handles concurrent git clone operations
 type ConcurrentCloner struct {
 	maxConcurrent int
-	semaphore     chan struct{}
+	limiter       *limiter
+	rampUp        time.Duration
+	rateLimits    RateLimitProvider
 	wg            sync.WaitGroup
 }
 
@@ -66,10 +205,16 @@ type ConcurrentCloner struct {
 func NewConcurrentCloner(maxConcurrent int) *ConcurrentCloner {
 	return &ConcurrentCloner{
 		maxConcurrent: maxConcurrent,
-		semaphore:     make(chan struct{}, maxConcurrent),
+		limiter:       newLimiter(maxConcurrent),
 	}
 }
 
+// SetRampUp makes CloneRepositories start with one clone and add a slot every
+// interval up to the maximum, instead of starting all clones at once (0 = disabled)
+func (c *ConcurrentCloner) SetRampUp(interval time.Duration) {
+	c.rampUp = interval
+}
+
 // isGitRepo checks if a directory is a git repository
 func isGitRepo(dir string) bool {
 	gitDir := filepath.Join(dir, ".git")
@@ -81,140 +226,317 @@ func isGitRepo(dir string) bool {
 
 // handleExistingRepo handles an existing repository based on the strategy
 func (c *ConcurrentCloner) handleExistingRepo(ctx context.Context, opts CloneOptions) error {
-	if !isGitRepo(opts.TargetDir) {
+	if !hasClone(opts) {
 		util.Debug(fmt.Sprintf("Target directory %s is not a git repository, proceeding with clone", opts.TargetDir))
 		return nil // Not a git repo, proceed with clone
 	}
 
 	switch opts.ExistingRepo {
 	case SkipExisting:
-		util.Info(fmt.Sprintf("Skipping existing repository: %s", opts.URL))
+		cloneEvent{url: opts.URL, status: StatusSkipped}.info(fmt.Sprintf("Skipping existing repository: %s", opts.URL))
 		opts.ProgressFunc(fmt.Sprintf("Skipping existing repository: %s", opts.URL))
 		return fmt.Errorf("repository already exists: %s", opts.TargetDir)
 
 	case OverwriteExisting:
+		if opts.BackupOnOverwrite {
+			return backupExistingRepo(opts)
+		}
 		util.Info(fmt.Sprintf("Removing existing repository: %s", opts.TargetDir))
 		opts.ProgressFunc(fmt.Sprintf("Removing existing repository: %s", opts.TargetDir))
-		if err := os.RemoveAll(opts.TargetDir); err != nil {
+		if err := os.RemoveAll(fsPath(opts.TargetDir)); err != nil {
 			util.Error("Failed to remove existing repository", err)
 			return fmt.Errorf("failed to remove existing repository: %w", err)
 		}
 		return nil // Proceed with clone
 
 	case FetchOnly:
-		return c.fetchAndUpdate(ctx, opts)
+		return backendFor(opts).Update(ctx, opts)
+
+	case Sync:
+		return syncExisting(ctx, opts)
 	}
 
 	return nil
 }
 
-// fetchAndUpdate updates an existing repository
-func (c *ConcurrentCloner) fetchAndUpdate(ctx context.Context, opts CloneOptions) error {
-	util.Info(fmt.Sprintf("Updating existing repository: %s", opts.URL))
+// Update fetches and hard-resets an existing repository with the git binary
+func (execBackend) Update(ctx context.Context, opts CloneOptions) error {
+	if opts.Mode != CloneNormal {
+		return updateBare(ctx, opts)
+	}
+	cloneEvent{url: opts.URL, status: StatusUpdating}.info(fmt.Sprintf("Updating existing repository: %s", opts.URL))
 	opts.ProgressFunc(fmt.Sprintf("Updating existing repository: %s", opts.URL))
 
-	// Change to repository directory
-	currentDir, err := os.Getwd()
-	if err != nil {
-		util.Error("Failed to get current directory", err)
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
-	if err := os.Chdir(opts.TargetDir); err != nil {
-		util.Error("Failed to change to repository directory", err)
-		return fmt.Errorf("failed to change to repository directory: %w", err)
-	}
-	defer os.Chdir(currentDir)
+	// Every command runs in the repository directory; the process working
+	// directory is shared by concurrent updates and must not change
+	dir := opts.TargetDir
 
-	// Fetch updates
-	fetchCtx, cancel := context.WithTimeout(ctx, opts.ConnTimeout)
+	// Fetch updates, remembering where the remote was so unchanged repos skip the reset
+	before := revParse(ctx, dir, targetRef(opts))
+	fetchCtx, cancel := context.WithTimeout(ctx, updateTimeout(opts))
 	defer cancel()
-	fetchCmd := exec.CommandContext(fetchCtx, "git", "fetch", "--all", "--prune")
+	fetchCmd := gitCommand(fetchCtx, opts, buildFetchArgs(opts)...)
+	fetchCmd.Dir = dir
 	if output, err := fetchCmd.CombinedOutput(); err != nil {
-		util.Error("Failed to fetch updates", fmt.Errorf("%w: %s", err, output))
-		return fmt.Errorf("failed to fetch updates: %w\nOutput: %s", err, output)
+		if authErr := authRequired(string(output), opts); authErr != nil {
+			cloneEvent{url: opts.URL, status: StatusFailed}.error("Failed to fetch updates", authErr)
+			return authErr
+		}
+		redactedOutput := redactToken(string(output), opts.Token)
+		cloneEvent{url: opts.URL, status: StatusFailed}.error("Failed to fetch updates", fmt.Errorf("%w: %s", err, redactedOutput))
+		return fmt.Errorf("failed to fetch updates: %w\nOutput: %s", err, redactedOutput)
 	}
 	util.Debug("Successfully fetched updates")
 
-	// Reset to specified branch or default branch
-	resetCtx, cancel := context.WithTimeout(ctx, opts.ConnTimeout)
-	defer cancel()
-	resetArgs := []string{"reset", "--hard"}
-	if opts.Branch != "" {
-		resetArgs = append(resetArgs, fmt.Sprintf("origin/%s", opts.Branch))
-	} else {
-		resetArgs = append(resetArgs, "origin/HEAD")
+	// An empty remote has nothing to reset to; a tag clone has no remote-tracking branches
+	refsCmd := exec.CommandContext(ctx, "git", "for-each-ref", "--count=1", "refs/remotes/origin")
+	refsCmd.Dir = dir
+	if output, err := refsCmd.Output(); err == nil && strings.TrimSpace(string(output)) == "" && opts.Tag == "" {
+		util.Info(fmt.Sprintf("Repository %s is empty, nothing to update", opts.URL))
+		opts.ProgressFunc(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+		return nil
 	}
-	resetCmd := exec.CommandContext(resetCtx, "git", resetArgs...)
-	if output, err := resetCmd.CombinedOutput(); err != nil {
-		util.Error("Failed to reset branch", fmt.Errorf("%w: %s", err, output))
-		return fmt.Errorf("failed to reset branch: %w\nOutput: %s", err, output)
+
+	if !needsReset(ctx, dir, opts, before) {
+		util.Info(fmt.Sprintf("Repository %s is already up to date", opts.URL))
+		opts.ProgressFunc(fmt.Sprintf("Repository already up to date: %s", opts.URL))
+		return nil
+	}
+
+	// Reset to the tag, the specified branch or the default branch
+	if err := resetWithRecovery(ctx, dir, opts); err != nil {
+		return err
 	}
 	util.Debug("Successfully reset branch")
 
-	util.Info(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+	if opts.Submodules {
+		if err := updateSubmodules(ctx, dir, opts); err != nil {
+			return err
+		}
+	}
+
+	cloneEvent{url: opts.URL, status: StatusSuccess}.info(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
 	opts.ProgressFunc(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
 	return nil
 }
 
 // CloneRepository clones a single repository with retries and progress tracking
 func (c *ConcurrentCloner) CloneRepository(ctx context.Context, opts CloneOptions) error {
-	util.Info(fmt.Sprintf("Starting clone of repository: %s", opts.URL))
+	return c.cloneRepository(ctx, opts, &cloneOutcome{})
+}
+
+// cloneRepository clones a repository, recording phase timings and other details into out
+func (c *ConcurrentCloner) cloneRepository(ctx context.Context, opts CloneOptions, out *cloneOutcome) error {
+	cloneEvent{url: opts.URL, status: StatusCloning}.info(fmt.Sprintf("Starting clone of repository: %s", opts.URL))
+	if opts.Branch != "" && opts.Tag != "" {
+		return fmt.Errorf("branch %q and tag %q are mutually exclusive", opts.Branch, opts.Tag)
+	}
 
 	// Create target directory if it doesn't exist
-	if err := os.MkdirAll(opts.TargetDir, 0755); err != nil {
+	checkPathLength(opts.TargetDir)
+	if err := os.MkdirAll(fsPath(opts.TargetDir), 0755); err != nil {
 		util.Error("Failed to create target directory", err)
 		return fmt.Errorf("failed to create target directory: %w", err)
 	}
 
 	// Handle existing repository
-	if err := c.handleExistingRepo(ctx, opts); err != nil {
+	existing := hasClone(opts)
+	start := time.Now()
+	err := c.handleExistingRepo(ctx, opts)
+	out.trace.record(PhaseExistingRepo, start)
+	if err != nil {
 		if opts.ExistingRepo == SkipExisting {
+			out.skipped = true
 			return nil // Skip is not an error condition
 		}
 		return err
 	}
+	if existing && (opts.ExistingRepo == FetchOnly || opts.ExistingRepo == Sync) {
+		// Updated in place, nothing to clone; the reset restored pruned files
+		return pruneClone(ctx, opts, &out.trace)
+	}
 
 	var lastErr error
 	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
 		if attempt > 0 {
-			// Calculate backoff duration (exponential)
-			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
-			msg := fmt.Sprintf("Retrying in %v... (attempt %d/%d)", backoff, attempt+1, opts.MaxRetries)
-			util.Info(msg)
+			backoff := retryBackoff(attempt, opts.MaxBackoff)
+			msg := fmt.Sprintf(retryPrefix+"%v... (attempt %d/%d)", backoff, attempt+1, opts.MaxRetries)
+			cloneEvent{url: opts.URL, status: StatusRetrying, attempt: attempt + 1}.info(msg)
 			opts.ProgressFunc(msg)
-			time.Sleep(backoff)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
 		}
 
-		// Set up command with timeouts
+		// Set up command with timeouts; cancel as soon as the attempt ends
 		cloneCtx, cancel := context.WithTimeout(ctx, opts.CloneTimeout)
-		defer cancel()
-
-		cmd := exec.CommandContext(cloneCtx, "git", "clone")
-		if opts.Branch != "" {
-			cmd.Args = append(cmd.Args, "-b", opts.Branch)
-		}
-		cmd.Args = append(cmd.Args, "--progress", opts.URL, opts.TargetDir)
-
-		util.Debug(fmt.Sprintf("Running git command: %v", cmd.Args))
-
-		// Capture command output
-		output, err := cmd.CombinedOutput()
+		start := time.Now()
+		empty, err := backendFor(opts).Clone(cloneCtx, opts)
+		cancel()
+		out.trace.record(fmt.Sprintf(PhaseAttempt, attempt+1), start)
 		if err == nil {
+			if empty {
+				out.empty = true
+				util.Warn(fmt.Sprintf("Repository %s is empty", opts.URL))
+			}
 			msg := fmt.Sprintf("Successfully cloned %s", opts.URL)
-			util.Info(msg)
+			cloneEvent{url: opts.URL, status: StatusSuccess, attempt: attempt + 1}.info(msg)
 			opts.ProgressFunc(msg)
-			return nil
+
+			if !out.empty && len(opts.Worktrees) > 0 {
+				start := time.Now()
+				err := addWorktrees(ctx, opts)
+				out.trace.record(PhaseWorktrees, start)
+				if err != nil {
+					util.Error("Failed to add worktrees", err)
+					return err
+				}
+			}
+			return pruneClone(ctx, opts, &out.trace)
 		}
 
-		lastErr = fmt.Errorf("clone failed: %w\nOutput: %s", err, string(output))
+		lastErr = err
 		msg := fmt.Sprintf("Clone attempt %d failed: %v", attempt+1, lastErr)
-		util.Error(msg, lastErr)
+		cloneEvent{url: opts.URL, status: StatusFailed, attempt: attempt + 1}.error(msg, lastErr)
 		opts.ProgressFunc(msg)
+		if errors.Is(err, ErrAuthRequired) {
+			// Retrying cannot supply the missing credentials
+			return err
+		}
 	}
 
 	return fmt.Errorf("failed to clone after %d attempts: %w", opts.MaxRetries, lastErr)
 }
 
+// updateTimeout returns the timeout covering the fetch of an update
+func updateTimeout(opts CloneOptions) time.Duration {
+	if opts.UpdateTimeout > 0 {
+		return opts.UpdateTimeout
+	}
+	return opts.CloneTimeout
+}
+
+// gitConfigArgs returns the `-c key=value` arguments placed before the git subcommand
+func gitConfigArgs(opts CloneOptions) []string {
+	var args []string
+	if isWindows() {
+		args = append(args, "-c", "core.longpaths=true")
+	}
+	if opts.ConnTimeout > 0 {
+		// Abort transfers that stall below 1KB/s for the connect timeout
+		// instead of bounding the whole transfer by it
+		args = append(args,
+			"-c", "http.lowSpeedLimit=1000",
+			"-c", fmt.Sprintf("http.lowSpeedTime=%d", int(opts.ConnTimeout.Seconds())),
+		)
+	}
+	if opts.Jobs > 0 {
+		args = append(args, "-c", fmt.Sprintf("fetch.parallel=%d", opts.Jobs))
+	}
+	return args
+}
+
+// buildCloneArgs builds the git arguments for cloning a repository
+func buildCloneArgs(opts CloneOptions) []string {
+	args := append(gitConfigArgs(opts), "clone")
+	switch opts.Mode {
+	case CloneBare:
+		args = append(args, "--bare")
+	case CloneMirror:
+		// A mirror takes every ref, so there is no branch to pick
+		args = append(args, "--mirror")
+		opts.Branch, opts.Tag = "", ""
+	}
+	// git accepts a tag for -b and checks it out detached
+	if opts.Tag != "" {
+		args = append(args, "-b", opts.Tag)
+		if len(opts.Worktrees) == 0 {
+			args = append(args, "--single-branch")
+		}
+	} else if opts.Branch != "" && opts.Branch != opts.DefaultBranch {
+		args = append(args, "-b", opts.Branch)
+		// Only the requested branch is needed unless worktrees check out others
+		if len(opts.Worktrees) == 0 {
+			args = append(args, "--single-branch")
+		}
+	}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", fmt.Sprintf("%d", opts.Depth))
+		// Shallow clones fetch a single branch; worktrees need the others too
+		if len(opts.Worktrees) > 0 {
+			args = append(args, "--no-single-branch")
+		}
+	}
+	if opts.Submodules {
+		args = append(args, "--recurse-submodules")
+		if opts.Depth > 0 {
+			args = append(args, "--shallow-submodules")
+		}
+	}
+	if opts.Jobs > 0 {
+		args = append(args, "--jobs", fmt.Sprintf("%d", opts.Jobs))
+	}
+	return append(args, "--progress", authenticatedURL(opts.URL, tokenFor(opts)), opts.TargetDir)
+}
+
+// buildFetchArgs builds the git arguments for fetching updates of an existing repository
+func buildFetchArgs(opts CloneOptions) []string {
+	args := append(append(gitConfigArgs(opts), tokenRewriteArgs(opts)...), "fetch", "--all", "--prune")
+	if opts.Tag != "" {
+		// Tags are not updated by a plain fetch; force picks up a moved tag
+		args = append(args, "--tags", "--force")
+	}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", fmt.Sprintf("%d", opts.Depth))
+	}
+	if opts.Jobs > 0 {
+		args = append(args, "--jobs", fmt.Sprintf("%d", opts.Jobs))
+	}
+	return args
+}
+
+// buildSubmoduleUpdateArgs builds the git arguments for updating submodules after a reset
+func buildSubmoduleUpdateArgs(opts CloneOptions) []string {
+	args := append(append(gitConfigArgs(opts), tokenRewriteArgs(opts)...), "submodule", "update", "--init", "--recursive")
+	if opts.Depth > 0 {
+		args = append(args, "--depth", fmt.Sprintf("%d", opts.Depth))
+	}
+	if opts.Jobs > 0 {
+		args = append(args, "--jobs", fmt.Sprintf("%d", opts.Jobs))
+	}
+	return args
+}
+
+// updateSubmodules initializes and updates the submodules of the repository in dir
+func updateSubmodules(ctx context.Context, dir string, opts CloneOptions) error {
+	updateCtx, cancel := context.WithTimeout(ctx, updateTimeout(opts))
+	defer cancel()
+
+	cmd := gitCommand(updateCtx, opts, buildSubmoduleUpdateArgs(opts)...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		util.Error("Failed to update submodules", err)
+		return fmt.Errorf("failed to update submodules: %w\nOutput: %s", err, redactToken(string(output), opts.Token))
+	}
+	util.Debug("Successfully updated submodules")
+	return nil
+}
+
+// DefaultJobs returns the number of parallel git jobs per repository, splitting
+// the available CPUs across the concurrent clones
+func DefaultJobs(maxConcurrent int) int {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	jobs := runtime.NumCPU() / maxConcurrent
+	if jobs < 1 {
+		jobs = 1
+	}
+	return jobs
+}
+
 // CloneRepositories clones multiple repositories concurrently
 func (c *ConcurrentCloner) CloneRepositories(ctx context.Context, repos []CloneOptions) <-chan CloneResult {
 	results := make(chan CloneResult, len(repos))
@@ -224,26 +546,70 @@ func (c *ConcurrentCloner) CloneRepositories(ctx context.Context, repos []CloneO
 
 		util.Info(fmt.Sprintf("Starting concurrent clone of %d repositories", len(repos)))
 
+		if c.rateLimits != nil {
+			// Adaptive concurrency replaces ramp-up; both would fight over the limit
+			done := make(chan struct{})
+			defer close(done)
+			c.applyRateLimit(ctx)
+			go c.adapt(ctx, done)
+		} else if c.rampUp > 0 && c.maxConcurrent > 1 {
+			done := make(chan struct{})
+			defer close(done)
+			c.limiter.setLimit(1)
+			go c.limiter.rampUp(c.maxConcurrent, c.rampUp, done)
+		} else {
+			c.limiter.setLimit(c.maxConcurrent)
+		}
+
 		for _, opts := range repos {
 			c.wg.Add(1)
 			go func(opts CloneOptions) {
 				defer c.wg.Done()
 
-				// Acquire semaphore
-				c.semaphore <- struct{}{}
-				defer func() { <-c.semaphore }()
+				// Acquire a concurrency slot
+				c.limiter.acquire()
+				defer c.limiter.release()
 
-				err := c.CloneRepository(ctx, opts)
+				out := &cloneOutcome{}
+				start := time.Now()
+				err := c.cloneRepository(ctx, opts, out)
+				if err == nil && hasClone(opts) {
+					out.branch = headBranch(opts.TargetDir)
+				}
+				if err == nil && !out.skipped && opts.PostCloneHook != "" && isGitRepo(opts.TargetDir) {
+					hookStart := time.Now()
+					out.hookOutput, out.hookError = runPostCloneHook(ctx, opts)
+					out.trace.record(PhasePostCloneHook, hookStart)
+					if out.hookError != nil && opts.PostCloneHookRequired {
+						err = out.hookError
+					}
+				}
+				elapsed := time.Since(start)
+				out.trace.log(opts.URL)
 				result := CloneResult{
-					RepoURL: opts.URL,
-					Success: err == nil,
-					Error:   err,
+					RepoURL:  opts.URL,
+					Success:  err == nil,
+					Error:    err,
+					Phases:   out.trace.phases,
+					Duration: elapsed,
+
+					ClonedBranch: out.branch,
+					Empty:        out.empty,
+					Slow:         err == nil && isSlow(elapsed, opts.SlowThreshold),
+
+					HookError:  out.hookError,
+					HookOutput: out.hookOutput,
+				}
+				event := cloneEvent{url: opts.URL, status: StatusSuccess, duration: elapsed}
+				if result.Slow {
+					event.warn(fmt.Sprintf("Repository %s was slow to clone: %v (threshold %v)", opts.URL, elapsed.Round(time.Second), opts.SlowThreshold))
 				}
 
 				if result.Success {
-					util.Info(fmt.Sprintf("Successfully cloned repository: %s", opts.URL))
+					event.info(fmt.Sprintf("Successfully cloned repository: %s", opts.URL))
 				} else {
-					util.Error(fmt.Sprintf("Failed to clone repository: %s", opts.URL), err)
+					event.status = StatusFailed
+					event.error(fmt.Sprintf("Failed to clone repository: %s", opts.URL), err)
 				}
 
 				results <- result
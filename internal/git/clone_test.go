@@ -0,0 +1,563 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobsArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(CloneOptions) []string
+	}{
+		{"clone", buildCloneArgs},
+		{"fetch", buildFetchArgs},
+		{"submodule update", buildSubmoduleUpdateArgs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CloneOptions{URL: "https://github.com/acme/api.git", TargetDir: "api", Jobs: 4}
+			args := strings.Join(tt.build(opts), " ")
+			for _, want := range []string{"--jobs 4", "-c fetch.parallel=4"} {
+				if !strings.Contains(args, want) {
+					t.Errorf("args %q missing %q", args, want)
+				}
+			}
+
+			opts.Jobs = 0
+			args = strings.Join(tt.build(opts), " ")
+			if strings.Contains(args, "--jobs") || strings.Contains(args, "fetch.parallel") {
+				t.Errorf("args %q set jobs without Jobs", args)
+			}
+		})
+	}
+}
+
+func TestDefaultJobs(t *testing.T) {
+	cpus := runtime.NumCPU()
+	tests := []struct {
+		maxConcurrent, want int
+	}{
+		{0, cpus},
+		{1, cpus},
+		{cpus, 1},
+		{cpus * 2, 1},
+	}
+	for _, tt := range tests {
+		if got := DefaultJobs(tt.maxConcurrent); got != tt.want {
+			t.Errorf("DefaultJobs(%d) = %d, want %d", tt.maxConcurrent, got, tt.want)
+		}
+	}
+}
+
+func TestIsEmptyCloneOutput(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"Cloning into 'app'...\nwarning: You appear to have cloned an empty repository.\n", true},
+		{"Cloning into 'app'...\nReceiving objects: 100% (3/3), done.\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isEmptyCloneOutput(tt.output); got != tt.want {
+			t.Errorf("isEmptyCloneOutput(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyRepositoryInSummary(t *testing.T) {
+	remote := newFixtureRemote(t)
+	empty := filepath.Join(t.TempDir(), "empty.git")
+	runGit(t, filepath.Dir(empty), "init", "--bare", empty)
+
+	tests := []struct {
+		name     string
+		strategy ExistingRepoStrategy
+	}{
+		{"clone", SkipExisting},
+		{"update", FetchOnly},
+		{"sync", Sync},
+	}
+	base := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(base, 2)
+			manager.SetCloneDefaults(testCloneOptions("", ""))
+			manager.AddRepository("acme", "app", remote, "", tt.strategy)
+			manager.AddRepository("acme", "empty", empty, "", tt.strategy)
+			for range manager.CloneAll(context.Background()) {
+			}
+
+			summary := manager.Summary()
+			if summary.Failed != 0 {
+				t.Fatalf("failures = %+v, want none", summary.Failures)
+			}
+			// The first run clones the empty repository; later runs update it in place
+			wantEmpty := 0
+			if tt.strategy == SkipExisting {
+				wantEmpty = 1
+			}
+			if summary.Empty != wantEmpty {
+				t.Errorf("empty = %d, want %d", summary.Empty, wantEmpty)
+			}
+			if !isGitRepo(filepath.Join(base, "acme", "empty")) {
+				t.Error("empty repository was not cloned")
+			}
+		})
+	}
+}
+
+func TestUpdateTimeout(t *testing.T) {
+	tests := []struct {
+		clone, update, want time.Duration
+	}{
+		{10 * time.Minute, 0, 10 * time.Minute},
+		{10 * time.Minute, time.Minute, time.Minute},
+	}
+	for _, tt := range tests {
+		opts := CloneOptions{CloneTimeout: tt.clone, UpdateTimeout: tt.update}
+		if got := updateTimeout(opts); got != tt.want {
+			t.Errorf("updateTimeout(clone %v, update %v) = %v, want %v", tt.clone, tt.update, got, tt.want)
+		}
+	}
+}
+
+func TestFetchOnlyUpdateTimeout(t *testing.T) {
+	remote := newFixtureRemote(t)
+	target := filepath.Join(t.TempDir(), "repo")
+	opts := testCloneOptions(remote, target)
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+	pushCommit(t, remote, "CHANGES.md", "changed\n")
+
+	opts.ExistingRepo = FetchOnly
+	opts.UpdateTimeout = time.Nanosecond
+	result := cloneOne(t, opts)
+	if result.Success {
+		t.Fatal("update succeeded within a nanosecond")
+	}
+	if !strings.Contains(result.Error.Error(), "fetch") {
+		t.Errorf("update error = %v, want a fetch failure", result.Error)
+	}
+
+	// The clone timeout alone is long enough
+	opts.UpdateTimeout = 0
+	if result := cloneOne(t, opts); !result.Success {
+		t.Errorf("update failed: %v", result.Error)
+	}
+}
+
+func TestIsSlow(t *testing.T) {
+	tests := []struct {
+		name                string
+		duration, threshold time.Duration
+		want                bool
+	}{
+		{"no threshold", time.Hour, 0, false},
+		{"under threshold", time.Minute, 2 * time.Minute, false},
+		{"at threshold", 2 * time.Minute, 2 * time.Minute, false},
+		{"over threshold", 3 * time.Minute, 2 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSlow(tt.duration, tt.threshold); got != tt.want {
+				t.Errorf("isSlow(%v, %v) = %v, want %v", tt.duration, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlowCloneMarkedButSucceeds(t *testing.T) {
+	remote := newFixtureRemote(t)
+	tests := []struct {
+		name      string
+		url       string
+		threshold time.Duration
+		wantSlow  bool
+	}{
+		{"over threshold", remote, time.Nanosecond, true},
+		{"within threshold", remote, time.Hour, false},
+		{"failed clone is never slow", remote + ".missing", time.Nanosecond, false},
+	}
+	base := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(base, 1)
+			opts := testCloneOptions("", "")
+			opts.SlowThreshold = tt.threshold
+			manager.SetCloneDefaults(opts)
+			name := fmt.Sprintf("app%d", i)
+			repo := manager.AddRepository("acme", name, tt.url, "", SkipExisting)
+			for range manager.CloneAll(context.Background()) {
+			}
+
+			wantStatus := StatusSuccess
+			if tt.url != remote {
+				wantStatus = StatusFailed
+			}
+			if status, _, _ := repo.GetStatus(); status != wantStatus {
+				t.Fatalf("status = %v, want %v", status, wantStatus)
+			}
+			if got := repo.Snapshot().Slow; got != tt.wantSlow {
+				t.Errorf("Slow = %v, want %v", got, tt.wantSlow)
+			}
+			wantSummary := []string(nil)
+			if tt.wantSlow {
+				wantSummary = []string{"acme/" + name}
+			}
+			if got := manager.Summary().Slow; strings.Join(got, ",") != strings.Join(wantSummary, ",") {
+				t.Errorf("summary slow = %v, want %v", got, wantSummary)
+			}
+		})
+	}
+}
+
+func TestDepthArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*CloneOptions)
+		build  func(CloneOptions) []string
+		want   []string
+		absent []string
+	}{
+		{"full clone", func(o *CloneOptions) {}, buildCloneArgs, nil, []string{"--depth", "--shallow-submodules"}},
+		{"shallow clone", func(o *CloneOptions) { o.Depth = 1 }, buildCloneArgs, []string{"--depth 1"}, []string{"--no-single-branch"}},
+		{"shallow branch", func(o *CloneOptions) { o.Depth, o.Branch = 1, "develop" }, buildCloneArgs, []string{"-b develop --single-branch --depth 1"}, nil},
+		{"shallow default branch", func(o *CloneOptions) { o.Depth, o.Branch, o.DefaultBranch = 5, "main", "main" }, buildCloneArgs, []string{"--depth 5"}, []string{"-b main"}},
+		{"shallow with worktrees", func(o *CloneOptions) { o.Depth, o.Worktrees = 1, []string{"release/*"} }, buildCloneArgs, []string{"--depth 1 --no-single-branch"}, nil},
+		{"shallow submodules", func(o *CloneOptions) { o.Depth, o.Submodules = 1, true }, buildCloneArgs, []string{"--depth 1", "--recurse-submodules --shallow-submodules"}, nil},
+		{"full fetch", func(o *CloneOptions) {}, buildFetchArgs, nil, []string{"--depth"}},
+		{"shallow fetch", func(o *CloneOptions) { o.Depth = 1 }, buildFetchArgs, []string{"fetch --all --prune --depth 1"}, nil},
+		{"shallow submodule update", func(o *CloneOptions) { o.Depth = 1 }, buildSubmoduleUpdateArgs, []string{"--init --recursive --depth 1"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CloneOptions{URL: "https://github.com/acme/api.git", TargetDir: "api"}
+			tt.modify(&opts)
+			args := strings.Join(tt.build(opts), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(args, want) {
+					t.Errorf("args %q missing %q", args, want)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(args, absent) {
+					t.Errorf("args %q contain %q", args, absent)
+				}
+			}
+		})
+	}
+
+	if depth := DefaultCloneOptions().Depth; depth != 0 {
+		t.Errorf("DefaultCloneOptions().Depth = %d, want a full clone", depth)
+	}
+}
+
+func TestShallowClone(t *testing.T) {
+	remote := newFixtureRemote(t)
+	pushCommit(t, remote, "CHANGES.md", "changed\n")
+	// file:// makes git honor --depth for a local remote
+	url := "file://" + remote
+
+	tests := []struct {
+		name  string
+		depth int
+		want  string
+	}{
+		{"full", 0, "2"},
+		{"depth 1", 1, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCloneOptions(url, filepath.Join(t.TempDir(), "repo"))
+			opts.Depth = tt.depth
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			if got := runGit(t, opts.TargetDir, "rev-list", "--count", "HEAD"); got != tt.want {
+				t.Errorf("cloned %s commits, want %s", got, tt.want)
+			}
+
+			// Updating keeps the clone at the same depth
+			pushCommit(t, remote, "MORE.md", tt.name+"\n")
+			opts.ExistingRepo = FetchOnly
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("update failed: %v", result.Error)
+			}
+			if tt.depth > 0 {
+				if got := runGit(t, opts.TargetDir, "rev-list", "--count", "HEAD"); got != tt.want {
+					t.Errorf("updated clone has %s commits, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmoduleArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*CloneOptions)
+		build  func(CloneOptions) []string
+		want   []string
+		absent []string
+	}{
+		{"disabled", func(o *CloneOptions) {}, buildCloneArgs, nil, []string{"--recurse-submodules", "--shallow-submodules"}},
+		{"full clone", func(o *CloneOptions) { o.Submodules = true }, buildCloneArgs, []string{"--recurse-submodules"}, []string{"--shallow-submodules"}},
+		{"shallow clone", func(o *CloneOptions) { o.Submodules, o.Depth = true, 1 }, buildCloneArgs, []string{"--recurse-submodules --shallow-submodules"}, nil},
+		{"parallel jobs", func(o *CloneOptions) { o.Submodules, o.Jobs = true, 4 }, buildCloneArgs, []string{"--recurse-submodules --jobs 4"}, nil},
+		{"update", func(o *CloneOptions) { o.Submodules = true }, buildSubmoduleUpdateArgs, []string{"submodule update --init --recursive"}, []string{"--depth"}},
+		{"parallel update", func(o *CloneOptions) { o.Submodules, o.Jobs = true, 2 }, buildSubmoduleUpdateArgs, []string{"--init --recursive --jobs 2"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CloneOptions{URL: "https://github.com/acme/api.git", TargetDir: "api"}
+			tt.modify(&opts)
+			args := strings.Join(tt.build(opts), " ")
+			for _, want := range tt.want {
+				if !strings.Contains(args, want) {
+					t.Errorf("args %q missing %q", args, want)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(args, absent) {
+					t.Errorf("args %q contain %q", args, absent)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmodulesClonedAndUpdated(t *testing.T) {
+	// Local submodule URLs are refused unless the file protocol is allowed
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	sub := newFixtureRemote(t)
+	remote := newFixtureRemote(t)
+	work := filepath.Join(t.TempDir(), "work")
+	runGit(t, filepath.Dir(work), "clone", remote, work)
+	runGit(t, work, "submodule", "add", sub, "lib")
+	runGit(t, work, "commit", "-m", "add submodule")
+	runGit(t, work, "push", "origin", "main")
+
+	tests := []struct {
+		name       string
+		submodules bool
+		want       bool
+	}{
+		{"enabled", true, true},
+		{"disabled", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			opts.Submodules = tt.submodules
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			subReadme := filepath.Join(opts.TargetDir, "lib", "README.md")
+			if _, err := os.Stat(subReadme); (err == nil) != tt.want {
+				t.Fatalf("submodule checked out = %v, want %v", err == nil, tt.want)
+			}
+			if !tt.submodules {
+				return
+			}
+
+			// An update checks out the submodule commit the superproject now records
+			pushCommit(t, sub, "README.md", "submodule update\n")
+			runGit(t, filepath.Join(work, "lib"), "pull", "-q", "origin", "main")
+			runGit(t, work, "commit", "-am", "bump submodule")
+			runGit(t, work, "push", "origin", "main")
+
+			opts.ExistingRepo = FetchOnly
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("update failed: %v", result.Error)
+			}
+			if got := readFile(t, subReadme); got != "submodule update\n" {
+				t.Errorf("submodule README.md = %q after the update", got)
+			}
+		})
+	}
+}
+
+func TestDefaultBranchArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		branch        string
+		defaultBranch string
+		worktrees     []string
+		want          string
+		absent        []string
+	}{
+		{"no branch", "", "main", nil, "", []string{"-b", "--single-branch"}},
+		{"default branch", "main", "main", nil, "", []string{"-b", "--single-branch"}},
+		{"other branch", "develop", "main", nil, "-b develop --single-branch", nil},
+		{"default unknown", "main", "", nil, "-b main --single-branch", nil},
+		{"other branch with worktrees", "develop", "main", []string{"release/*"}, "-b develop", []string{"--single-branch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CloneOptions{URL: "https://github.com/acme/api.git", TargetDir: "api", Branch: tt.branch, DefaultBranch: tt.defaultBranch, Worktrees: tt.worktrees}
+			args := strings.Join(buildCloneArgs(opts), " ")
+			if !strings.Contains(args, tt.want) {
+				t.Errorf("args %q missing %q", args, tt.want)
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(args, absent) {
+					t.Errorf("args %q contain %q", args, absent)
+				}
+			}
+		})
+	}
+}
+
+func TestClonedBranch(t *testing.T) {
+	remote := newFixtureRemote(t, "develop")
+	runGit(t, remote, "tag", "v1.0.0", "main")
+
+	tests := []struct {
+		name          string
+		branch        string
+		defaultBranch string
+		tag           string
+		want          string
+	}{
+		{"remote default", "", "", "", "main"},
+		{"requested default", "main", "main", "", "main"},
+		{"requested other", "develop", "main", "", "develop"},
+		{"tag", "", "main", "v1.0.0", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			opts.Branch, opts.DefaultBranch, opts.Tag = tt.branch, tt.defaultBranch, tt.tag
+			result := cloneOne(t, opts)
+			if !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			if result.ClonedBranch != tt.want {
+				t.Errorf("ClonedBranch = %q, want %q", result.ClonedBranch, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		worktrees []string
+		clone     string
+		fetch     string
+		absent    []string
+	}{
+		{"no tag", "", nil, "clone", "fetch --all --prune", []string{"--tags", "-b"}},
+		{"tag", "v1.0.0", nil, "-b v1.0.0 --single-branch", "fetch --all --prune --tags --force", nil},
+		{"tag with worktrees", "v1.0.0", []string{"release/*"}, "-b v1.0.0", "--tags --force", []string{"--single-branch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CloneOptions{URL: "https://github.com/acme/api.git", TargetDir: "api", DefaultBranch: "main", Tag: tt.tag, Worktrees: tt.worktrees}
+			clone := strings.Join(buildCloneArgs(opts), " ")
+			if !strings.Contains(clone, tt.clone) {
+				t.Errorf("clone args %q missing %q", clone, tt.clone)
+			}
+			fetch := strings.Join(buildFetchArgs(opts), " ")
+			if !strings.Contains(fetch, tt.fetch) {
+				t.Errorf("fetch args %q missing %q", fetch, tt.fetch)
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(clone, absent) || strings.Contains(fetch, absent) {
+					t.Errorf("args %q / %q contain %q", clone, fetch, absent)
+				}
+			}
+		})
+	}
+}
+
+func TestTagCheckout(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+	}{
+		{"exec", BackendExec},
+		{"go-git", BackendGoGit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newFixtureRemote(t)
+			runGit(t, remote, "tag", "v1.0.0", "main")
+			pushCommit(t, remote, "CHANGES.md", "after v1.0.0\n")
+
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			opts.Backend, opts.Tag = tt.backend, "v1.0.0"
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			if _, err := os.Stat(filepath.Join(opts.TargetDir, "CHANGES.md")); err == nil {
+				t.Fatal("clone checked out main instead of the tag")
+			}
+
+			// Moving the tag makes the next update follow it
+			runGit(t, remote, "tag", "-f", "v1.0.0", "main")
+			opts.ExistingRepo = FetchOnly
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("update failed: %v", result.Error)
+			}
+			if got := readFile(t, filepath.Join(opts.TargetDir, "CHANGES.md")); got != "after v1.0.0\n" {
+				t.Errorf("CHANGES.md = %q after the tag moved", got)
+			}
+		})
+	}
+}
+
+func TestCloneRejectsBranchAndTag(t *testing.T) {
+	opts := testCloneOptions(newFixtureRemote(t), filepath.Join(t.TempDir(), "repo"))
+	opts.Branch, opts.Tag = "main", "v1.0.0"
+	result := cloneOne(t, opts)
+	if result.Success || !strings.Contains(result.Error.Error(), "mutually exclusive") {
+		t.Fatalf("clone with a branch and a tag = %v, want a mutually exclusive error", result.Error)
+	}
+	if _, err := os.Stat(opts.TargetDir); err == nil {
+		t.Error("rejected clone created its target directory")
+	}
+}
+
+func TestParseExistingRepoStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ExistingRepoStrategy
+		wantErr bool
+	}{
+		{"", SkipExisting, false},
+		{"skip", SkipExisting, false},
+		{"overwrite", OverwriteExisting, false},
+		{"Overwrite", OverwriteExisting, false},
+		{"fetch-only", FetchOnly, false},
+		{"fetch", FetchOnly, false},
+		{"sync", Sync, false},
+		{"merge", SkipExisting, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseExistingRepoStrategy(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseExistingRepoStrategy(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	// Every strategy's name parses back to it
+	for _, strategy := range []ExistingRepoStrategy{SkipExisting, OverwriteExisting, FetchOnly, Sync} {
+		if got, err := ParseExistingRepoStrategy(strategy.String()); err != nil || got != strategy {
+			t.Errorf("ParseExistingRepoStrategy(%q) = %v, %v; want %v", strategy.String(), got, err, strategy)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package git
+
+import "sync"
+
+// updateCoalescer delivers repository updates without ever blocking producers.
+// Updates for the same repository are coalesced: the consumer receives the
+// repository once per batch and reads its latest state via GetStatus.
+type updateCoalescer struct {
+	mu      sync.Mutex
+	pending []*Repository
+	queued  map[*Repository]bool
+	signal  chan struct{}
+	done    chan struct{}
+}
+
+// newUpdateCoalescer creates a coalescer and starts forwarding to the returned channel
+func newUpdateCoalescer() (*updateCoalescer, <-chan *Repository) {
+	c := &updateCoalescer{
+		queued: make(map[*Repository]bool),
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	out := make(chan *Repository)
+	go c.forward(out)
+	return c, out
+}
+
+// notify records that repo changed. It never blocks.
+func (c *updateCoalescer) notify(repo *Repository) {
+	c.mu.Lock()
+	if !c.queued[repo] {
+		c.queued[repo] = true
+		c.pending = append(c.pending, repo)
+	}
+	c.mu.Unlock()
+
+	select {
+	case c.signal <- struct{}{}:
+	default:
+	}
+}
+
+// close stops accepting updates; pending updates are still delivered before the output channel closes
+func (c *updateCoalescer) close() {
+	close(c.done)
+}
+
+// take removes and returns the pending batch
+func (c *updateCoalescer) take() []*Repository {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	batch := c.pending
+	c.pending = nil
+	c.queued = make(map[*Repository]bool)
+	return batch
+}
+
+// forward delivers pending batches to out until the coalescer is closed
+func (c *updateCoalescer) forward(out chan<- *Repository) {
+	defer close(out)
+
+	for {
+		select {
+		case <-c.signal:
+			for _, repo := range c.take() {
+				out <- repo
+			}
+		case <-c.done:
+			for _, repo := range c.take() {
+				out <- repo
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateCoalescerNeverBlocksProducers(t *testing.T) {
+	tests := []struct {
+		name      string
+		producers int
+		updates   int
+		repos     int
+	}{
+		{"one producer, one repository", 1, 1000, 1},
+		{"many producers sharing repositories", 8, 500, 4},
+		{"one repository per producer", 16, 200, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := make([]*Repository, tt.repos)
+			for i := range repos {
+				repos[i] = &Repository{Organization: "acme", Name: fmt.Sprintf("repo-%d", i)}
+			}
+			coalescer, out := newUpdateCoalescer()
+
+			// The consumer does not read until every producer has finished
+			var wg sync.WaitGroup
+			for p := 0; p < tt.producers; p++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < tt.updates; i++ {
+						coalescer.notify(repos[(p+i)%len(repos)])
+					}
+				}()
+			}
+			finished := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(finished)
+			}()
+			select {
+			case <-finished:
+			case <-time.After(10 * time.Second):
+				t.Fatal("producers blocked on a consumer that is not reading")
+			}
+			coalescer.close()
+
+			// A slow consumer gets each repository at most once per batch, and
+			// every repository at least once
+			seen := make(map[*Repository]int)
+			for repo := range out {
+				seen[repo]++
+				time.Sleep(time.Millisecond)
+			}
+			if len(seen) != len(repos) {
+				t.Errorf("received %d repositories, want %d", len(seen), len(repos))
+			}
+			for repo, count := range seen {
+				// At most one batch was taken before close and one after it
+				if count > 2 {
+					t.Errorf("%s delivered %d times, want its updates coalesced", repo.Name, count)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCoalescerDeliversAfterClose(t *testing.T) {
+	coalescer, out := newUpdateCoalescer()
+	repo := &Repository{Organization: "acme", Name: "app"}
+	coalescer.notify(repo)
+	coalescer.close()
+
+	var got []*Repository
+	for r := range out {
+		got = append(got, r)
+	}
+	if len(got) != 1 || got[0] != repo {
+		t.Errorf("received %v, want the pending update before the channel closed", got)
+	}
+}
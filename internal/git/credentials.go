@@ -0,0 +1,91 @@
+package git
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// tokenUser is the user name GitHub accepts alongside a token in HTTPS URLs
+const tokenUser = "x-access-token"
+
+// redacted replaces the token wherever it appears in logged or displayed text
+const redacted = "***"
+
+// authenticatedURL embeds token as credentials into an HTTPS clone URL.
+// Other URLs (SSH, local paths) and an empty token are returned unchanged.
+func authenticatedURL(rawURL, token string) string {
+	if token == "" {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return rawURL
+	}
+	u.User = url.UserPassword(tokenUser, token)
+	return u.String()
+}
+
+// tokenFor returns the token to send with opts.URL, or "" when the URL is not
+// on TokenHost so the token never leaves the configured GitHub host
+func tokenFor(opts CloneOptions) string {
+	if opts.Token == "" || opts.TokenHost == "" {
+		return opts.Token
+	}
+	u, err := url.Parse(opts.URL)
+	if err != nil || (!strings.EqualFold(u.Host, opts.TokenHost) && !strings.EqualFold(u.Hostname(), opts.TokenHost)) {
+		return ""
+	}
+	return opts.Token
+}
+
+// tokenRewriteArgs returns `-c url.<auth>.insteadOf=<base>` arguments so fetches of
+// existing HTTPS clones authenticate without storing the token in .git/config
+func tokenRewriteArgs(opts CloneOptions) []string {
+	token := tokenFor(opts)
+	if token == "" {
+		return nil
+	}
+	u, err := url.Parse(opts.URL)
+	if err != nil || u.Scheme != "https" {
+		return nil
+	}
+	base := u.Scheme + "://" + u.Host + "/"
+	return []string{"-c", "url." + configKeyEscape(authenticatedURL(base, token)) + ".insteadOf=" + base}
+}
+
+// configKeyEscape percent-encodes "=" in a URL used in a `-c` config key, which
+// ends at the first "="; git decodes the credentials when it uses the URL
+func configKeyEscape(s string) string {
+	return strings.ReplaceAll(s, "=", "%3D")
+}
+
+// redactToken removes the token from s, including its URL-escaped forms
+func redactToken(s, token string) string {
+	if token == "" {
+		return s
+	}
+	s = strings.ReplaceAll(s, token, redacted)
+	escaped := url.UserPassword(tokenUser, token).String()
+	for _, form := range []string{escaped, configKeyEscape(escaped)} {
+		if form != tokenUser+":"+token {
+			s = strings.ReplaceAll(s, form, tokenUser+":"+redacted)
+		}
+	}
+	return s
+}
+
+// stripCredentials resets the origin URL of a fresh clone to the bare URL so the
+// token is not persisted in .git/config
+func stripCredentials(ctx context.Context, opts CloneOptions) {
+	if authenticatedURL(opts.URL, tokenFor(opts)) == opts.URL {
+		return
+	}
+	cmd := gitCommand(ctx, opts, "remote", "set-url", "origin", opts.URL)
+	cmd.Dir = opts.TargetDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		util.Warn("Failed to remove credentials from origin URL of " + opts.TargetDir + ": " + redactToken(string(output), opts.Token))
+	}
+}
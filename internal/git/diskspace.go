@@ -0,0 +1,113 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// DiskCheck controls the free-space preflight run before cloning
+type DiskCheck int
+
+const (
+	// DiskCheckWarn logs a warning when the clones may not fit
+	DiskCheckWarn DiskCheck = iota
+	// DiskCheckAbort fails every pending repository instead of starting clones that may not fit
+	DiskCheckAbort
+	// DiskCheckOff skips the preflight
+	DiskCheckOff
+)
+
+// ParseDiskCheck parses a disk check mode name (warn, abort, off)
+func ParseDiskCheck(name string) (DiskCheck, error) {
+	switch strings.ToLower(name) {
+	case "", "warn":
+		return DiskCheckWarn, nil
+	case "abort":
+		return DiskCheckAbort, nil
+	case "off":
+		return DiskCheckOff, nil
+	default:
+		return DiskCheckWarn, fmt.Errorf("unknown disk check mode %q (use warn, abort or off)", name)
+	}
+}
+
+// SpaceProvider reports the bytes available to unprivileged users on the filesystem holding dir
+type SpaceProvider interface {
+	Available(dir string) (uint64, error)
+}
+
+// errSpaceUnsupported is returned where free space cannot be determined
+var errSpaceUnsupported = errors.New("free disk space is not available on this platform")
+
+// filesystemSpace is the SpaceProvider backed by the operating system
+type filesystemSpace struct{}
+
+// Available implements SpaceProvider, measuring the nearest existing ancestor of dir
+func (filesystemSpace) Available(dir string) (uint64, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return availableSpace(dir)
+}
+
+// checkDiskSpace compares the GitHub-reported size (KB) of the queued
+// repositories with the space available under dir. It returns an error only
+// when the clones may not fit and mode is DiskCheckAbort.
+func checkDiskSpace(dir string, queued []*Repository, mode DiskCheck, space SpaceProvider) error {
+	if mode == DiskCheckOff || len(queued) == 0 {
+		return nil
+	}
+
+	var required uint64
+	for _, repo := range queued {
+		if repo.Size > 0 {
+			required += uint64(repo.Size) * 1024
+		}
+	}
+	available, err := space.Available(dir)
+	if err != nil {
+		util.Debug(fmt.Sprintf("Skipping disk space check: %v", err))
+		return nil
+	}
+	if required <= available {
+		util.Debug(fmt.Sprintf("Disk space check passed: %s needed, %s available", formatBytes(required), formatBytes(available)))
+		return nil
+	}
+
+	msg := fmt.Sprintf("%d repositories need about %s but only %s is free in %s", len(queued), formatBytes(required), formatBytes(available), dir)
+	if mode == DiskCheckAbort {
+		return fmt.Errorf("insufficient disk space: %s", msg)
+	}
+	util.Warn("Low disk space: " + msg)
+	return nil
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. 1.5 GiB
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package git
+
+// availableSpace is not supported on this platform
+func availableSpace(dir string) (uint64, error) {
+	return 0, errSpaceUnsupported
+}
@@ -0,0 +1,147 @@
+package git
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// fakeSpace is a SpaceProvider reporting a fixed amount of free space
+type fakeSpace struct {
+	available uint64
+	err       error
+}
+
+func (f fakeSpace) Available(dir string) (uint64, error) {
+	return f.available, f.err
+}
+
+func TestParseDiskCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    DiskCheck
+		wantErr bool
+	}{
+		{"", DiskCheckWarn, false},
+		{"warn", DiskCheckWarn, false},
+		{"ABORT", DiskCheckAbort, false},
+		{"off", DiskCheckOff, false},
+		{"fail", DiskCheckWarn, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDiskCheck(tt.name)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseDiskCheck(%q) = %v, %v, want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	// 3 GiB reported by GitHub in KB, plus a repository of unknown size
+	queued := []*Repository{{Name: "app", Size: 2 * 1024 * 1024}, {Name: "lib", Size: 1024 * 1024}, {Name: "new"}}
+	const gib = 1 << 30
+
+	tests := []struct {
+		name    string
+		mode    DiskCheck
+		space   SpaceProvider
+		queued  []*Repository
+		wantErr string
+		warned  bool
+	}{
+		{"fits", DiskCheckAbort, fakeSpace{available: 4 * gib}, queued, "", false},
+		{"exactly fits", DiskCheckAbort, fakeSpace{available: 3 * gib}, queued, "", false},
+		{"warn when short", DiskCheckWarn, fakeSpace{available: gib}, queued, "", true},
+		{"abort when short", DiskCheckAbort, fakeSpace{available: gib}, queued, "3 repositories need about 3.0 GiB but only 1.0 GiB is free", false},
+		{"off", DiskCheckOff, fakeSpace{available: 0}, queued, "", false},
+		{"nothing queued", DiskCheckAbort, fakeSpace{available: 0}, nil, "", false},
+		{"space unknown", DiskCheckAbort, fakeSpace{err: errSpaceUnsupported}, queued, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs syncBuffer
+			saved := *util.Logger()
+			t.Cleanup(func() { *util.Logger() = saved })
+			if err := util.InitLoggerWithWriter("info", "text", &logs); err != nil {
+				t.Fatal(err)
+			}
+
+			err := checkDiskSpace("/srv/src", tt.queued, tt.mode, tt.space)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("checkDiskSpace() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("checkDiskSpace() error = %v, want %q", err, tt.wantErr)
+			}
+			if warned := strings.Contains(logs.String(), "Low disk space"); warned != tt.warned {
+				t.Errorf("warned = %v, want %v:\n%s", warned, tt.warned, logs.String())
+			}
+		})
+	}
+}
+
+func TestCloneAllAbortsWithoutSpace(t *testing.T) {
+	remote := newFixtureRemote(t)
+	tests := []struct {
+		name      string
+		mode      DiskCheck
+		available uint64
+		want      RepositoryStatus
+	}{
+		{"enough space", DiskCheckAbort, 1 << 30, StatusSuccess},
+		{"abort", DiskCheckAbort, 1024, StatusFailed},
+		{"warn only", DiskCheckWarn, 1024, StatusSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(t.TempDir(), 1)
+			manager.SetCloneDefaults(testCloneOptions("", ""))
+			manager.SetDiskCheck(tt.mode)
+			manager.SetSpaceProvider(fakeSpace{available: tt.available})
+			repo := manager.AddRepository("acme", "app", remote, "", SkipExisting)
+			repo.SetSize(10 * 1024)
+			for range manager.CloneAll(t.Context()) {
+			}
+
+			status, err, _ := repo.GetStatus()
+			if status != tt.want {
+				t.Fatalf("status = %v (%v), want %v", status, err, tt.want)
+			}
+			if status == StatusFailed && !strings.Contains(err.Error(), "insufficient disk space") {
+				t.Errorf("error = %v, want insufficient disk space", err)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{10 << 20, "10.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFilesystemSpace(t *testing.T) {
+	// A directory that does not exist yet is measured at its nearest existing ancestor
+	available, err := filesystemSpace{}.Available(filepath.Join(t.TempDir(), "not", "created", "yet"))
+	if errors.Is(err, errSpaceUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil || available == 0 {
+		t.Errorf("Available() = %d, %v, want free space", available, err)
+	}
+}
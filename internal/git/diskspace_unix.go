@@ -0,0 +1,14 @@
+//go:build unix
+
+package git
+
+import "golang.org/x/sys/unix"
+
+// availableSpace returns the bytes available to unprivileged users on dir's filesystem
+func availableSpace(dir string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package git
+
+import "golang.org/x/sys/windows"
+
+// availableSpace returns the bytes available to the current user on dir's volume
+func availableSpace(dir string) (uint64, error) {
+	path, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var free, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(path, &free, &total, &totalFree); err != nil {
+		return 0, err
+	}
+	return free, nil
+}
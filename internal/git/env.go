@@ -0,0 +1,78 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/internal/auth"
+)
+
+// ErrAuthRequired is returned when git needed credentials that were not supplied
+var ErrAuthRequired = errors.New("authentication required")
+
+// authPromptMarkers are printed by git when it cannot prompt for credentials
+var authPromptMarkers = []string{
+	"terminal prompts disabled",
+	"could not read Username",
+	"could not read Password",
+}
+
+// gitEnv returns the extra environment variables for git commands run with opts
+func gitEnv(opts CloneOptions) []string {
+	// No TTY is attached, so fail instead of waiting forever for a credential prompt
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	if opts.LFSSkipSmudge {
+		// Check out LFS pointer files only; content can be fetched later with `git lfs pull`
+		env = append(env, "GIT_LFS_SKIP_SMUDGE=1")
+	}
+	if opts.Proxy != "" {
+		// curl, which git uses for HTTP, only reads the lowercase http_proxy
+		env = append(env, "http_proxy="+opts.Proxy, "https_proxy="+opts.Proxy, "HTTPS_PROXY="+opts.Proxy)
+	}
+	if command := sshCommand(opts); command != "" {
+		env = append(env, "GIT_SSH_COMMAND="+command)
+	}
+	return env
+}
+
+// sshCommand returns the ssh command for SSH remotes: SSHCommand as given, or
+// one using only SSHKeyPath as identity. It is "" when neither is set.
+func sshCommand(opts CloneOptions) string {
+	if opts.SSHCommand != "" {
+		return opts.SSHCommand
+	}
+	if opts.SSHKeyPath != "" {
+		return "ssh -i " + shellQuote(opts.SSHKeyPath) + " -o IdentitiesOnly=yes"
+	}
+	return ""
+}
+
+// shellQuote quotes s for the POSIX shell git runs GIT_SSH_COMMAND with
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// gitCommand creates a git command that inherits the process environment plus gitEnv(opts)
+func gitCommand(ctx context.Context, opts CloneOptions, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), gitEnv(opts)...)
+	return cmd
+}
+
+// authRequired returns ErrAuthRequired when git output shows it failed for lack of
+// credentials, wrapped in an *auth.SSOError when the token needs SSO authorization
+func authRequired(output string, opts CloneOptions) error {
+	if url, ok := auth.ParseSSOOutput(output); ok {
+		return &auth.SSOError{URL: url, Err: ErrAuthRequired}
+	}
+	for _, marker := range authPromptMarkers {
+		if strings.Contains(output, marker) {
+			return fmt.Errorf("%w: %s needs credentials; set a token with access to it", ErrAuthRequired, redactToken(opts.URL, opts.Token))
+		}
+	}
+	return nil
+}
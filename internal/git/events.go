@@ -0,0 +1,66 @@
+package git
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// cloneEvent is a clone or update event logged with structured fields, so
+// JSON logs can be filtered by organization, repository and status
+type cloneEvent struct {
+	url      string
+	status   RepositoryStatus
+	attempt  int           // 1-based clone attempt, 0 when not tied to one
+	duration time.Duration // 0 until the clone has finished
+}
+
+// fields returns the structured log fields of the event
+func (e cloneEvent) fields() map[string]interface{} {
+	org, repo := repoFromURL(e.url)
+	fields := map[string]interface{}{
+		"org":    org,
+		"repo":   repo,
+		"url":    e.url,
+		"status": e.status.String(),
+	}
+	if e.attempt > 0 {
+		fields["attempt"] = e.attempt
+	}
+	if e.duration > 0 {
+		fields["duration"] = e.duration.Seconds()
+	}
+	return fields
+}
+
+// log logs msg at level with the event's fields and err, if set
+func (e cloneEvent) log(level zerolog.Level, msg string, err error) {
+	logger := util.WithFields(e.fields())
+	logger.WithLevel(level).Err(err).Msg(msg)
+}
+
+// info logs msg at info level with the event's fields
+func (e cloneEvent) info(msg string) {
+	e.log(zerolog.InfoLevel, msg, nil)
+}
+
+// warn logs msg at warning level with the event's fields
+func (e cloneEvent) warn(msg string) {
+	e.log(zerolog.WarnLevel, msg, nil)
+}
+
+// error logs msg and err at error level with the event's fields
+func (e cloneEvent) error(msg string, err error) {
+	e.log(zerolog.ErrorLevel, msg, err)
+}
+
+// repoFromURL returns the organization and repository named by a clone URL
+func repoFromURL(rawURL string) (string, string) {
+	parts := strings.Split(remotePath(rawURL), "/")
+	if len(parts) < 2 {
+		return "", remotePath(rawURL)
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+func TestRepoFromURL(t *testing.T) {
+	tests := []struct {
+		url, org, repo string
+	}{
+		{"https://github.com/acme/api.git", "acme", "api"},
+		{"git@github.com:acme/api.git", "acme", "api"},
+		{"https://ghe.example.com/acme/api/", "acme", "api"},
+		{"api", "", "api"},
+	}
+	for _, tt := range tests {
+		if org, repo := repoFromURL(tt.url); org != tt.org || repo != tt.repo {
+			t.Errorf("repoFromURL(%q) = %q, %q; want %q, %q", tt.url, org, repo, tt.org, tt.repo)
+		}
+	}
+}
+
+func TestCloneEventFields(t *testing.T) {
+	const url = "https://github.com/acme/api.git"
+	tests := []struct {
+		name   string
+		event  cloneEvent
+		want   map[string]interface{}
+		absent []string
+	}{
+		{"started", cloneEvent{url: url, status: StatusCloning},
+			map[string]interface{}{"org": "acme", "repo": "api", "url": url, "status": "Cloning"},
+			[]string{"attempt", "duration"}},
+		{"retrying", cloneEvent{url: url, status: StatusRetrying, attempt: 2},
+			map[string]interface{}{"status": "Retrying", "attempt": 2},
+			[]string{"duration"}},
+		{"finished", cloneEvent{url: url, status: StatusSuccess, duration: 1500 * time.Millisecond},
+			map[string]interface{}{"status": "Success", "duration": 1.5},
+			[]string{"attempt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := tt.event.fields()
+			for key, want := range tt.want {
+				if got := fields[key]; got != want {
+					t.Errorf("field %s = %v, want %v", key, got, want)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := fields[key]; ok {
+					t.Errorf("field %s is set: %v", key, fields[key])
+				}
+			}
+		})
+	}
+}
+
+func TestCloneEventsLoggedAsJSON(t *testing.T) {
+	var logs syncBuffer
+	saved := *util.Logger()
+	t.Cleanup(func() { *util.Logger() = saved })
+	if err := util.InitLoggerWithWriter("info", "json", &logs); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := newFixtureRemote(t)
+	if result := cloneOne(t, testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+
+	var finished map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		if strings.HasPrefix(entry["message"].(string), "Successfully cloned repository") {
+			finished = entry
+		}
+	}
+	if finished == nil {
+		t.Fatalf("no clone completion event in:\n%s", logs.String())
+	}
+	for _, key := range []string{"org", "repo", "url", "status", "duration"} {
+		if _, ok := finished[key]; !ok {
+			t.Errorf("completion event lacks %q: %v", key, finished)
+		}
+	}
+	if finished["repo"] != "remote" || finished["status"] != "Success" {
+		t.Errorf("completion event = %v, want repo remote with status Success", finished)
+	}
+}
@@ -0,0 +1,109 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runGit runs git in dir with a fixed identity and no user or system config
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "init.defaultBranch=main"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// newFixtureRemote creates a bare repository with one commit on main, plus the
+// given branches pointing at it, and returns its path
+func newFixtureRemote(t *testing.T, branches ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	runGit(t, root, "init", "work")
+	writeFile(t, filepath.Join(work, "README.md"), "fixture\n")
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "initial commit")
+	for _, branch := range branches {
+		runGit(t, work, "branch", branch)
+	}
+
+	remote := filepath.Join(root, "remote.git")
+	runGit(t, root, "clone", "--bare", "work", remote)
+	return remote
+}
+
+// pushCommit adds a commit to the main branch of a fixture remote
+func pushCommit(t *testing.T, remote, file, content string) {
+	t.Helper()
+
+	work := filepath.Join(t.TempDir(), "push")
+	runGit(t, filepath.Dir(work), "clone", remote, work)
+	writeFile(t, filepath.Join(work, file), content)
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "update "+file)
+	runGit(t, work, "push", "origin", "main")
+}
+
+// writeFile writes content to path, creating its directory
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testCloneOptions returns clone options for cloning remote into target
+// without retries, so failures surface immediately
+func testCloneOptions(remote, target string) CloneOptions {
+	opts := DefaultCloneOptions()
+	opts.URL = remote
+	opts.TargetDir = target
+	opts.MaxRetries = 0
+	opts.CloneTimeout = time.Minute
+	return opts
+}
+
+// cloneOne clones opts with a single-slot cloner and returns its result once
+// the cloner has finished
+func cloneOne(t *testing.T, opts CloneOptions) CloneResult {
+	t.Helper()
+
+	results := NewConcurrentCloner(1).CloneRepositories(context.Background(), []CloneOptions{opts})
+	result, ok := <-results
+	if !ok {
+		t.Fatal("no clone result")
+	}
+	for range results {
+	}
+	return result
+}
+
+// readFile returns the content of path, failing the test when it cannot be read
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lastSynced returns when the clone in dir was last cloned or fetched, from
+// the modification times of its git directory and FETCH_HEAD. It reports
+// false when dir holds no clone of the given mode.
+func lastSynced(dir string, mode CloneMode) (time.Time, bool) {
+	gitDir := dir
+	if mode == CloneNormal {
+		gitDir = filepath.Join(dir, ".git")
+	}
+	info, err := os.Stat(gitDir)
+	if err != nil || !info.IsDir() {
+		return time.Time{}, false
+	}
+	synced := info.ModTime()
+	// Every fetch rewrites FETCH_HEAD, even when nothing changed
+	if fetched, err := os.Stat(filepath.Join(gitDir, "FETCH_HEAD")); err == nil && fetched.ModTime().After(synced) {
+		synced = fetched.ModTime()
+	}
+	return synced, true
+}
+
+// recentlySynced reports whether the clone in dir was cloned or fetched within window
+func recentlySynced(dir string, mode CloneMode, window time.Duration, now time.Time) bool {
+	synced, ok := lastSynced(dir, mode)
+	return ok && now.Sub(synced) < window
+}
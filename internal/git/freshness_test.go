@@ -0,0 +1,105 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setMtime sets the modification time of path
+func setMtime(t *testing.T, path string, mtime time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecentlySynced(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	recent := now.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name string
+		mode CloneMode
+		// gitDir and fetchHead are the mtimes of the git directory and
+		// FETCH_HEAD; zero leaves the path out
+		gitDir, fetchHead time.Time
+		want              bool
+	}{
+		{"recent clone", CloneNormal, recent, time.Time{}, true},
+		{"old clone", CloneNormal, old, time.Time{}, false},
+		{"old clone fetched recently", CloneNormal, old, recent, true},
+		{"old clone fetched long ago", CloneNormal, old, old, false},
+		{"recent bare clone", CloneBare, recent, time.Time{}, true},
+		{"old mirror fetched recently", CloneMirror, old, recent, true},
+		{"no clone", CloneNormal, time.Time{}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			gitDir := dir
+			if tt.mode == CloneNormal {
+				gitDir = filepath.Join(dir, ".git")
+			}
+			if !tt.fetchHead.IsZero() {
+				writeFile(t, filepath.Join(gitDir, "FETCH_HEAD"), "")
+				setMtime(t, filepath.Join(gitDir, "FETCH_HEAD"), tt.fetchHead)
+			}
+			if !tt.gitDir.IsZero() {
+				if err := os.MkdirAll(gitDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				setMtime(t, gitDir, tt.gitDir)
+			}
+
+			if got := recentlySynced(dir, tt.mode, time.Hour, now); got != tt.want {
+				t.Errorf("recentlySynced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipNewerThan(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		// age is how long ago the existing clone was last synced
+		age  time.Duration
+		want RepositoryStatus
+	}{
+		{"disabled", 0, time.Minute, StatusSuccess},
+		{"synced within the window", time.Hour, time.Minute, StatusSkipped},
+		{"synced before the window", time.Hour, 2 * time.Hour, StatusSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newFixtureRemote(t)
+			base := t.TempDir()
+			target := filepath.Join(base, "acme", "app")
+			if result := cloneOne(t, testCloneOptions(remote, target)); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			setMtime(t, filepath.Join(target, ".git"), time.Now().Add(-tt.age))
+			pushCommit(t, remote, "CHANGES.md", "changed\n")
+
+			manager := NewRepositoryManager(base, 1)
+			manager.SetCloneDefaults(testCloneOptions("", ""))
+			manager.SetSkipNewerThan(tt.window)
+			repo := manager.AddRepository("acme", "app", remote, "", FetchOnly)
+			for range manager.CloneAll(t.Context()) {
+			}
+
+			status, err, _ := repo.GetStatus()
+			if status != tt.want {
+				t.Fatalf("status = %v (%v), want %v", status, err, tt.want)
+			}
+			// A skipped clone is not fetched
+			_, statErr := os.Stat(filepath.Join(target, "CHANGES.md"))
+			if updated, want := statErr == nil, tt.want == StatusSuccess; updated != want {
+				t.Errorf("clone updated = %v, want %v", updated, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// goGitBackend clones and updates repositories in-process with go-git, without a git binary
+type goGitBackend struct{}
+
+// goGitAuth returns token authentication for HTTPS remotes
+func goGitAuth(opts CloneOptions) transport.AuthMethod {
+	if tokenFor(opts) == "" {
+		return nil
+	}
+	return &githttp.BasicAuth{Username: tokenUser, Password: opts.Token}
+}
+
+// Clone clones opts.URL into opts.TargetDir
+func (goGitBackend) Clone(ctx context.Context, opts CloneOptions) (bool, error) {
+	cloneOpts := &gogit.CloneOptions{
+		URL:          opts.URL,
+		Auth:         goGitAuth(opts),
+		Depth:        opts.Depth,
+		ProxyOptions: transport.ProxyOptions{URL: opts.Proxy},
+	}
+	if opts.Submodules {
+		cloneOpts.RecurseSubmodules = gogit.DefaultSubmoduleRecursionDepth
+		cloneOpts.ShallowSubmodules = opts.Depth > 0
+	}
+	if opts.Tag != "" {
+		cloneOpts.ReferenceName = plumbing.NewTagReferenceName(opts.Tag)
+		cloneOpts.SingleBranch = true
+	} else if opts.Branch != "" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
+		cloneOpts.SingleBranch = true
+	}
+
+	util.Debug(fmt.Sprintf("Cloning %s with go-git", opts.URL))
+	_, err := gogit.PlainCloneContext(ctx, fsPath(opts.TargetDir), false, cloneOpts)
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return true, nil
+	}
+	if err != nil {
+		// go-git puts the response body, which carries GitHub's SSO notice, in the error
+		if authErr := authRequired(err.Error(), opts); authErr != nil {
+			return false, authErr
+		}
+		return false, fmt.Errorf("clone failed: %s", redactToken(err.Error(), opts.Token))
+	}
+	return false, nil
+}
+
+// Update fetches the origin remote and hard-resets the worktree to the remote branch
+func (goGitBackend) Update(ctx context.Context, opts CloneOptions) error {
+	cloneEvent{url: opts.URL, status: StatusUpdating}.info(fmt.Sprintf("Updating existing repository: %s", opts.URL))
+	opts.ProgressFunc(fmt.Sprintf("Updating existing repository: %s", opts.URL))
+
+	repo, err := gogit.PlainOpen(fsPath(opts.TargetDir))
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, updateTimeout(opts))
+	defer cancel()
+	fetchOpts := &gogit.FetchOptions{
+		RemoteName:   "origin",
+		Auth:         goGitAuth(opts),
+		ProxyOptions: transport.ProxyOptions{URL: opts.Proxy},
+		Depth:        opts.Depth,
+		Prune:        true,
+		Force:        true,
+	}
+	if opts.Tag != "" {
+		fetchOpts.Tags = gogit.AllTags
+	}
+	err = repo.FetchContext(fetchCtx, fetchOpts)
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			util.Info(fmt.Sprintf("Repository %s is empty, nothing to update", opts.URL))
+			opts.ProgressFunc(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+			return nil
+		}
+		if authErr := authRequired(err.Error(), opts); authErr != nil {
+			return authErr
+		}
+		return fmt.Errorf("failed to fetch updates: %s", redactToken(err.Error(), opts.Token))
+	}
+
+	target, err := goGitTarget(repo, opts)
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to open worktree: %w", err)
+	}
+	if err := worktree.Reset(&gogit.ResetOptions{Commit: target, Mode: gogit.HardReset}); err != nil {
+		return fmt.Errorf("failed to reset branch: %w", err)
+	}
+	if opts.Submodules {
+		submodules, err := worktree.Submodules()
+		if err != nil {
+			return fmt.Errorf("failed to list submodules: %w", err)
+		}
+		err = submodules.UpdateContext(ctx, &gogit.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: gogit.DefaultSubmoduleRecursionDepth,
+			Auth:              goGitAuth(opts),
+			Depth:             opts.Depth,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update submodules: %s", redactToken(err.Error(), opts.Token))
+		}
+	}
+
+	cloneEvent{url: opts.URL, status: StatusSuccess}.info(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+	opts.ProgressFunc(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+	return nil
+}
+
+// goGitTarget resolves the commit an update resets to: the tag when one is
+// set, otherwise the remote branch (the checked-out one by default)
+func goGitTarget(repo *gogit.Repository, opts CloneOptions) (plumbing.Hash, error) {
+	if opts.Tag != "" {
+		hash, err := repo.ResolveRevision(plumbing.Revision(targetRef(opts)))
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to resolve tag %s: %w", opts.Tag, err)
+		}
+		return *hash, nil
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to resolve HEAD: %w", err)
+		}
+		branch = head.Name().Short()
+	}
+	remote, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", branch), true)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to resolve %s: %w", remoteRef(branch), err)
+	}
+	return remote.Hash(), nil
+}
+
+// headBranch returns the branch checked out in dir, or "" for a detached or unborn HEAD
+func headBranch(dir string) string {
+	repo, err := gogit.PlainOpen(fsPath(dir))
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil || !head.Name().IsBranch() {
+		return ""
+	}
+	return head.Name().Short()
+}
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// PhasePostCloneHook is the trace phase covering the post-clone hook
+const PhasePostCloneHook = "post_clone_hook"
+
+// hookForLanguage returns the language-specific hook for a repository, falling
+// back to the generic hook for unknown or unmapped languages
+func hookForLanguage(hooks map[string]string, language, fallback string) string {
+	if language != "" {
+		for lang, hook := range hooks {
+			if strings.EqualFold(lang, language) {
+				return hook
+			}
+		}
+	}
+	return fallback
+}
+
+// parseHookTemplate parses a post-clone hook as a template over PathFields,
+// e.g. "make setup REPO={{quote .Repo}}"; quote shell-quotes a value
+func parseHookTemplate(hook string) (*template.Template, error) {
+	tmpl, err := template.New("hook").Option("missingkey=error").
+		Funcs(template.FuncMap{"quote": shellQuote}).Parse(hook)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post-clone hook template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, PathFields{}); err != nil {
+		return nil, fmt.Errorf("invalid post-clone hook template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderHook renders the post-clone hook command for a repository
+func renderHook(hook string, repo *Repository) (string, error) {
+	if !strings.Contains(hook, "{{") {
+		return hook, nil
+	}
+	tmpl, err := parseHookTemplate(hook)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, pathFields(repo)); err != nil {
+		return "", fmt.Errorf("failed to render post-clone hook for %s/%s: %w", repo.Organization, repo.Name, err)
+	}
+	return b.String(), nil
+}
+
+// hookWaitDelay bounds how long a cancelled hook's output is drained
+const hookWaitDelay = time.Second
+
+// hookCommand creates the shell command running a hook in dir
+func hookCommand(ctx context.Context, hook, dir string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", hook)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", hook)
+	}
+	cmd.Dir = dir
+	// Children of a killed shell can hold its output open; stop waiting for them
+	cmd.WaitDelay = hookWaitDelay
+	return cmd
+}
+
+// shouldRetryHook reports whether a failed hook should be retried. With no
+// retry codes configured every failure is retried; otherwise only the listed exit codes are.
+func shouldRetryHook(err error, codes []int) bool {
+	if len(codes) == 0 {
+		return true
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	for _, code := range codes {
+		if exitErr.ExitCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hookBackoff returns the delay before the given hook retry (1-based)
+func hookBackoff(base time.Duration, retry int) time.Duration {
+	if base <= 0 {
+		base = time.Second
+	}
+	return base * time.Duration(1<<uint(retry-1))
+}
+
+// runPostCloneHook runs opts.PostCloneHook in the repository directory,
+// retrying transient failures up to opts.PostCloneRetries times. It returns the
+// combined output of the last attempt.
+func runPostCloneHook(ctx context.Context, opts CloneOptions) (string, error) {
+	if opts.PostCloneHook == "" {
+		return "", nil
+	}
+
+	var output string
+	var lastErr error
+	for attempt := 0; attempt <= opts.PostCloneRetries; attempt++ {
+		if attempt > 0 {
+			backoff := hookBackoff(opts.PostCloneBackoff, attempt)
+			msg := fmt.Sprintf("Retrying post-clone hook in %v... (attempt %d/%d)", backoff, attempt+1, opts.PostCloneRetries+1)
+			util.Info(msg)
+			opts.ProgressFunc(msg)
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		cmd := hookCommand(ctx, opts.PostCloneHook, opts.TargetDir)
+		util.Debug(fmt.Sprintf("Running post-clone hook in %s: %s", opts.TargetDir, opts.PostCloneHook))
+		out, err := cmd.CombinedOutput()
+		output = string(out)
+		if err == nil {
+			util.Debug(fmt.Sprintf("Post-clone hook succeeded for %s", opts.URL))
+			return output, nil
+		}
+
+		lastErr = fmt.Errorf("post-clone hook failed: %w\nOutput: %s", err, output)
+		util.Error(fmt.Sprintf("Post-clone hook attempt %d failed for %s", attempt+1, opts.URL), err)
+		if !shouldRetryHook(err, opts.PostCloneRetryCodes) {
+			break
+		}
+	}
+	return output, lastErr
+}
@@ -0,0 +1,287 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHookForLanguage(t *testing.T) {
+	hooks := map[string]string{"Go": "go mod download", "javascript": "npm ci"}
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{"Go", "go mod download"},
+		{"go", "go mod download"},
+		{"JavaScript", "npm ci"},
+		{"Rust", "make setup"},
+		{"", "make setup"},
+	}
+	for _, tt := range tests {
+		if got := hookForLanguage(hooks, tt.language, "make setup"); got != tt.want {
+			t.Errorf("hookForLanguage(%q) = %q, want %q", tt.language, got, tt.want)
+		}
+	}
+}
+
+func TestShouldRetryHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	exitErr := func(code int) error {
+		return exec.Command("sh", "-c", "exit "+strconv.Itoa(code)).Run()
+	}
+
+	tests := []struct {
+		name  string
+		err   error
+		codes []int
+		want  bool
+	}{
+		{"any failure without codes", errors.New("boom"), nil, true},
+		{"listed exit code", exitErr(3), []int{2, 3}, true},
+		{"unlisted exit code", exitErr(1), []int{2, 3}, false},
+		{"not an exit error", errors.New("boom"), []int{1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetryHook(tt.err, tt.codes); got != tt.want {
+				t.Errorf("shouldRetryHook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookBackoff(t *testing.T) {
+	tests := []struct {
+		base  time.Duration
+		retry int
+		want  time.Duration
+	}{
+		{0, 1, time.Second},
+		{0, 3, 4 * time.Second},
+		{100 * time.Millisecond, 1, 100 * time.Millisecond},
+		{100 * time.Millisecond, 2, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := hookBackoff(tt.base, tt.retry); got != tt.want {
+			t.Errorf("hookBackoff(%v, %d) = %v, want %v", tt.base, tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestRunPostCloneHookRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+
+	// Fails until its third run
+	hook := `n=$(cat count 2>/dev/null || echo 0); n=$((n+1)); echo $n > count; [ $n -ge 3 ]`
+	tests := []struct {
+		name    string
+		retries int
+		codes   []int
+		wantErr bool
+		runs    string
+	}{
+		{"enough retries", 2, nil, false, "3"},
+		{"too few retries", 1, nil, true, "2"},
+		{"exit code not retried", 2, []int{7}, true, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultCloneOptions()
+			opts.TargetDir = t.TempDir()
+			opts.PostCloneHook = hook
+			opts.PostCloneRetries = tt.retries
+			opts.PostCloneRetryCodes = tt.codes
+			opts.PostCloneBackoff = time.Millisecond
+
+			_, err := runPostCloneHook(context.Background(), opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runPostCloneHook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			runs, _ := os.ReadFile(filepath.Join(opts.TargetDir, "count"))
+			if got := string(runs); got != tt.runs+"\n" {
+				t.Errorf("hook ran %q times, want %s", got, tt.runs)
+			}
+		})
+	}
+}
+
+func TestPostCloneHookSkipsExistingClone(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	remote := newFixtureRemote(t)
+	target := filepath.Join(t.TempDir(), "repo")
+	marker := filepath.Join(target, "hook-ran")
+
+	opts := testCloneOptions(remote, target)
+	opts.PostCloneHook = "touch hook-ran"
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("hook did not run after the clone: %v", err)
+	}
+
+	os.Remove(marker)
+	opts.ExistingRepo = SkipExisting
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("skip failed: %v", result.Error)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("hook ran for a skipped repository")
+	}
+
+	opts.ExistingRepo = FetchOnly
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("update failed: %v", result.Error)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("hook did not run after the update: %v", err)
+	}
+}
+
+func TestLanguageHookDispatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	remote := newFixtureRemote(t)
+	tests := []struct {
+		name     string
+		language string
+		fallback string
+		// want is the marker file the hook creates, "" when no hook runs
+		want string
+	}{
+		{"mapped language", "Go", "touch generic", "go-hook"},
+		{"case insensitive", "javascript", "touch generic", "js-hook"},
+		{"unmapped language", "Rust", "touch generic", "generic"},
+		{"unknown language", "", "touch generic", "generic"},
+		{"unmapped without fallback", "Rust", "", ""},
+	}
+	base := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(base, 1)
+			opts := testCloneOptions("", "")
+			opts.PostCloneHook = tt.fallback
+			opts.LanguageHooks = map[string]string{"Go": "touch go-hook", "JavaScript": "touch js-hook"}
+			manager.SetCloneDefaults(opts)
+			name := "app" + strconv.Itoa(i)
+			repo := manager.AddRepository("acme", name, remote, "", SkipExisting)
+			repo.SetLanguage(tt.language)
+			for range manager.CloneAll(context.Background()) {
+			}
+			if status, _, err := repo.GetStatus(); status != StatusSuccess {
+				t.Fatalf("status = %v (%v), want success", status, err)
+			}
+
+			dir := filepath.Join(base, "acme", name)
+			for _, marker := range []string{"go-hook", "js-hook", "generic"} {
+				_, err := os.Stat(filepath.Join(dir, marker))
+				if ran := err == nil; ran != (marker == tt.want) {
+					t.Errorf("hook creating %s ran = %v, want %v", marker, ran, marker == tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderHook(t *testing.T) {
+	repo := &Repository{Organization: "acme", Name: "it's", Language: "Go", DefaultBranch: "main"}
+	tests := []struct {
+		name    string
+		hook    string
+		want    string
+		wantErr bool
+	}{
+		{"plain command", "touch .cloned", "touch .cloned", false},
+		{"fields", "make setup ORG={{.Org}} BRANCH={{.Branch}}", "make setup ORG=acme BRANCH=main", false},
+		{"quoted field", "echo {{quote .Repo}}", `echo 'it'\''s'`, false},
+		{"unknown field", "echo {{.Owner}}", "", true},
+		{"malformed", "echo {{.Repo", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderHook(tt.hook, repo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderHook(%q) error = %v, wantErr %v", tt.hook, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderHook(%q) = %q, want %q", tt.hook, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostCloneHookFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	tests := []struct {
+		name     string
+		hook     string
+		required bool
+		want     RepositoryStatus
+		hookErr  bool
+	}{
+		{"hook succeeds", "touch .cloned; echo {{.Repo}} ready", false, StatusSuccess, false},
+		{"failure recorded", "touch .cloned; echo {{.Repo}} ready; exit 3", false, StatusSuccess, true},
+		{"failure required", "touch .cloned; echo {{.Repo}} ready; exit 3", true, StatusFailed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			manager := NewRepositoryManager(base, 1)
+			opts := testCloneOptions("", "")
+			opts.PostCloneHook, opts.PostCloneHookRequired = tt.hook, tt.required
+			manager.SetCloneDefaults(opts)
+			repo := manager.AddRepository("acme", "app", newFixtureRemote(t), "", SkipExisting)
+			for range manager.CloneAll(t.Context()) {
+			}
+
+			if status, err, _ := repo.GetStatus(); status != tt.want {
+				t.Fatalf("status = %v (%v), want %v", status, err, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(base, "acme", "app", ".cloned")); err != nil {
+				t.Errorf("hook did not run in the clone: %v", err)
+			}
+			snapshot := repo.Snapshot()
+			if (snapshot.HookError != "") != tt.hookErr {
+				t.Errorf("HookError = %q, want an error %v", snapshot.HookError, tt.hookErr)
+			}
+			if got := repo.HookOutput; got != "app ready\n" {
+				t.Errorf("HookOutput = %q, want the hook's output", got)
+			}
+		})
+	}
+}
+
+func TestPostCloneHookCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	opts := DefaultCloneOptions()
+	opts.TargetDir = t.TempDir()
+	opts.PostCloneHook = "sleep 30"
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := runPostCloneHook(ctx, opts); err == nil {
+		t.Fatal("runPostCloneHook() = nil after the context was cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("hook ran for %v after cancellation", elapsed)
+	}
+}
@@ -0,0 +1,131 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Layout arranges repositories under the base directory
+type Layout int
+
+const (
+	// LayoutNested clones into baseDir/org/repo
+	LayoutNested Layout = iota
+	// LayoutFlat clones into baseDir/repo
+	LayoutFlat
+	// LayoutFlatOrg clones into baseDir/org-repo
+	LayoutFlatOrg
+)
+
+// ParseLayout parses a layout name (nested, flat, flat-org)
+func ParseLayout(name string) (Layout, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "nested":
+		return LayoutNested, nil
+	case "flat":
+		return LayoutFlat, nil
+	case "flat-org":
+		return LayoutFlatOrg, nil
+	default:
+		return LayoutNested, fmt.Errorf("unknown layout %q (use nested, flat or flat-org)", name)
+	}
+}
+
+// path returns the clone target of a repository relative to the base directory
+func (l Layout) path(org, name string) string {
+	switch l {
+	case LayoutFlat:
+		return name
+	case LayoutFlatOrg:
+		return org + "-" + name
+	default:
+		return filepath.Join(org, name)
+	}
+}
+
+// PathFields are the repository details available to a path template
+type PathFields struct {
+	Org      string
+	Repo     string
+	Language string
+	// Branch is the requested branch, or the default branch when none was requested
+	Branch        string
+	DefaultBranch string
+}
+
+// pathFields returns the template fields of a repository
+func pathFields(repo *Repository) PathFields {
+	branch := repo.Branch
+	if branch == "" {
+		branch = repo.DefaultBranch
+	}
+	return PathFields{
+		Org:           repo.Organization,
+		Repo:          repo.Name,
+		Language:      repo.Language,
+		Branch:        branch,
+		DefaultBranch: repo.DefaultBranch,
+	}
+}
+
+// ParsePathTemplate parses a clone path template such as
+// "{{.Org}}/{{.Language}}/{{.Repo}}" or "{{.Org}}/{{.Repo}}@{{.Branch}}"
+func ParsePathTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path template %q: %w", text, err)
+	}
+	// Catch unknown fields before any repository is cloned
+	if err := tmpl.Execute(io.Discard, PathFields{}); err != nil {
+		return nil, fmt.Errorf("invalid path template %q: %w", text, err)
+	}
+	return tmpl, nil
+}
+
+// renderPath renders a path template for a repository. The result must stay
+// inside the base directory, so absolute paths and ".." escapes are rejected.
+func renderPath(tmpl *template.Template, repo *Repository) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, pathFields(repo)); err != nil {
+		return "", fmt.Errorf("failed to render path template for %s/%s: %w", repo.Organization, repo.Name, err)
+	}
+	rendered := b.String()
+	path := filepath.Clean(filepath.FromSlash(rendered))
+	if rendered == "" || path == "." || filepath.IsAbs(path) || filepath.VolumeName(path) != "" ||
+		path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path template renders %q for %s/%s, which is outside the output directory", rendered, repo.Organization, repo.Name)
+	}
+	return path, nil
+}
+
+// targetCollisions finds repositories sharing a clone target, ignoring case
+// for case-insensitive filesystems. Each colliding repository maps to an error
+// naming the others.
+func targetCollisions(targets map[*Repository]string) map[*Repository]error {
+	byTarget := make(map[string][]*Repository)
+	for repo, target := range targets {
+		key := strings.ToLower(filepath.Clean(target))
+		byTarget[key] = append(byTarget[key], repo)
+	}
+
+	collisions := make(map[*Repository]error)
+	for _, group := range byTarget {
+		if len(group) < 2 {
+			continue
+		}
+		names := make([]string, len(group))
+		for i, repo := range group {
+			names[i] = repo.Organization + "/" + repo.Name
+		}
+		sort.Strings(names)
+		for _, repo := range group {
+			collisions[repo] = fmt.Errorf("clone target %s is shared by %s; use a layout that keeps them apart",
+				targets[repo], strings.Join(names, ", "))
+		}
+	}
+	return collisions
+}
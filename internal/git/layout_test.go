@@ -0,0 +1,250 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOrgDirOverrides(t *testing.T) {
+	base := t.TempDir()
+	orgDirs := map[string]string{"org-a": "/data/a", "org-b": ""}
+	tests := []struct {
+		name     string
+		orgDirs  map[string]string
+		template string
+		mode     CloneMode
+		org      string
+		want     string
+	}{
+		{"no overrides", nil, "", CloneNormal, "org-a", filepath.Join(base, "org-a", "app")},
+		{"mapped", orgDirs, "", CloneNormal, "org-a", filepath.Join("/data/a", "app")},
+		{"unmapped", orgDirs, "", CloneNormal, "org-c", filepath.Join(base, "org-c", "app")},
+		{"empty mapping falls back", orgDirs, "", CloneNormal, "org-b", filepath.Join(base, "org-b", "app")},
+		{"mapped mirror", orgDirs, "", CloneMirror, "org-a", filepath.Join("/data/a", "app.git")},
+		{"mapping beats template", orgDirs, "all/{{.Repo}}", CloneNormal, "org-a", filepath.Join("/data/a", "app")},
+		{"template when unmapped", orgDirs, "all/{{.Repo}}", CloneNormal, "org-c", filepath.Join(base, "all", "app")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(base, 1)
+			manager.SetOrgDirs(tt.orgDirs)
+			if tt.template != "" {
+				tmpl, err := ParsePathTemplate(tt.template)
+				if err != nil {
+					t.Fatal(err)
+				}
+				manager.SetPathTemplate(tmpl)
+			}
+			opts := DefaultCloneOptions()
+			opts.Mode = tt.mode
+			manager.SetCloneDefaults(opts)
+
+			repo := manager.AddRepository(tt.org, "app", "unused", "", SkipExisting)
+			got, err := manager.targetDir(repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("targetDir(%s/app) = %q, want %q", tt.org, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Layout
+		wantErr bool
+	}{
+		{"", LayoutNested, false},
+		{"nested", LayoutNested, false},
+		{"flat", LayoutFlat, false},
+		{" Flat-Org ", LayoutFlatOrg, false},
+		{"deep", LayoutNested, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLayout(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLayout(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLayoutTargets(t *testing.T) {
+	base := t.TempDir()
+	tests := []struct {
+		name   string
+		layout Layout
+		// repos are org/name pairs added in order
+		repos []string
+		want  map[string]string
+		// collide are the repositories refused for sharing a target
+		collide []string
+	}{
+		{"nested", LayoutNested, []string{"acme/app", "other/app"}, map[string]string{
+			"acme/app":  filepath.Join(base, "acme", "app"),
+			"other/app": filepath.Join(base, "other", "app"),
+		}, nil},
+		{"flat", LayoutFlat, []string{"acme/app", "acme/api"}, map[string]string{
+			"acme/app": filepath.Join(base, "app"),
+			"acme/api": filepath.Join(base, "api"),
+		}, nil},
+		{"flat-org", LayoutFlatOrg, []string{"acme/app", "other/app"}, map[string]string{
+			"acme/app":  filepath.Join(base, "acme-app"),
+			"other/app": filepath.Join(base, "other-app"),
+		}, nil},
+		{"flat collision", LayoutFlat, []string{"acme/app", "other/app", "acme/api"}, map[string]string{
+			"acme/api": filepath.Join(base, "api"),
+		}, []string{"acme/app", "other/app"}},
+		{"flat collision ignoring case", LayoutFlat, []string{"acme/App", "other/app"}, nil, []string{"acme/App", "other/app"}},
+		{"flat-org collision", LayoutFlatOrg, []string{"acme/web-app", "acme-web/app"}, nil, []string{"acme/web-app", "acme-web/app"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(base, 1)
+			manager.SetLayout(tt.layout)
+			repos := make(map[string]*Repository)
+			var pending []*Repository
+			for _, fullName := range tt.repos {
+				org, name, _ := strings.Cut(fullName, "/")
+				repo := manager.AddRepository(org, name, "unused", "", SkipExisting)
+				repos[fullName] = repo
+				pending = append(pending, repo)
+			}
+
+			targets, problems := manager.targetDirs(pending)
+			for fullName, want := range tt.want {
+				if problems[repos[fullName]] != nil {
+					t.Errorf("%s problem = %v, want none", fullName, problems[repos[fullName]])
+				}
+				if got := targets[repos[fullName]]; got != want {
+					t.Errorf("target of %s = %q, want %q", fullName, got, want)
+				}
+			}
+			for _, fullName := range tt.collide {
+				err := problems[repos[fullName]]
+				if err == nil || !strings.Contains(err.Error(), "is shared by") {
+					t.Errorf("%s problem = %v, want a collision", fullName, err)
+				}
+			}
+			if len(problems) != len(tt.collide) {
+				t.Errorf("%d repositories have problems, want %d: %v", len(problems), len(tt.collide), problems)
+			}
+		})
+	}
+}
+
+func TestLayoutCollisionFailsWithoutCloning(t *testing.T) {
+	base := t.TempDir()
+	manager := NewRepositoryManager(base, 2)
+	manager.SetCloneDefaults(testCloneOptions("", ""))
+	manager.SetLayout(LayoutFlat)
+	first := manager.AddRepository("acme", "app", newFixtureRemote(t), "", SkipExisting)
+	second := manager.AddRepository("other", "app", newFixtureRemote(t), "", SkipExisting)
+	for range manager.CloneAll(t.Context()) {
+	}
+
+	for _, repo := range []*Repository{first, second} {
+		status, err, _ := repo.GetStatus()
+		if status != StatusFailed || err == nil || !strings.Contains(err.Error(), "acme/app, other/app") {
+			t.Errorf("%s/%s = %s (%v), want a collision naming both", repo.Organization, repo.Name, status, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(base, "app")); err == nil {
+		t.Error("a colliding repository was cloned")
+	}
+}
+
+func TestRenderPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		branch   string
+		want     string
+		// wantErr is a substring of the expected error, "" for success
+		wantErr string
+	}{
+		{"org and repo", "{{.Org}}/{{.Repo}}", "", "acme/app", ""},
+		{"by language", "{{.Org}}/{{.Language}}/{{.Repo}}", "", "acme/Go/app", ""},
+		{"default branch", "{{.Org}}/{{.Repo}}@{{.Branch}}", "", "acme/app@main", ""},
+		{"requested branch", "{{.Org}}/{{.Repo}}@{{.Branch}}", "develop", "acme/app@develop", ""},
+		{"functions", `{{.Org | printf "%s-org"}}/{{.Repo}}`, "", "acme-org/app", ""},
+		{"inner dot dot stays inside", "{{.Org}}/x/../{{.Repo}}", "", "acme/app", ""},
+		{"parent escape", "../{{.Repo}}", "", "", "outside the output directory"},
+		{"nested escape", "{{.Org}}/../../{{.Repo}}", "", "", "outside the output directory"},
+		{"parent only", "{{.Org}}/../..", "", "", "outside the output directory"},
+		{"absolute", "/tmp/{{.Repo}}", "", "", "outside the output directory"},
+		{"empty", `{{if false}}x{{end}}`, "", "", "outside the output directory"},
+		{"current directory", "{{.Org}}/..", "", "", "outside the output directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParsePathTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParsePathTemplate() error = %v", err)
+			}
+			repo := &Repository{Organization: "acme", Name: "app", Language: "Go", Branch: tt.branch, DefaultBranch: "main"}
+			got, err := renderPath(tmpl, repo)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("renderPath() = %q, %v, want error containing %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderPath() error = %v", err)
+			}
+			if want := filepath.FromSlash(tt.want); got != want {
+				t.Errorf("renderPath() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParsePathTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{"known fields", "{{.Org}}/{{.Repo}}@{{.DefaultBranch}}", false},
+		{"unknown field", "{{.Owner}}/{{.Repo}}", true},
+		{"syntax error", "{{.Org}/{{.Repo}}", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePathTemplate(tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("ParsePathTemplate(%q) error = %v, want error %v", tt.template, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPathTemplateTraversalFailsOnlyThatRepository(t *testing.T) {
+	base := t.TempDir()
+	manager := NewRepositoryManager(base, 1)
+	tmpl, err := ParsePathTemplate("{{if eq .Repo \"evil\"}}../../{{end}}{{.Org}}/{{.Repo}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager.SetPathTemplate(tmpl)
+	good := manager.AddRepository("acme", "app", "unused", "", SkipExisting)
+	evil := manager.AddRepository("acme", "evil", "unused", "", SkipExisting)
+
+	targets, problems := manager.targetDirs([]*Repository{good, evil})
+	if got, want := targets[good], filepath.Join(base, "acme", "app"); got != want {
+		t.Errorf("target of acme/app = %q, want %q", got, want)
+	}
+	if _, ok := targets[evil]; ok {
+		t.Errorf("acme/evil got target %q outside %s", targets[evil], base)
+	}
+	if err := problems[evil]; err == nil || !strings.Contains(err.Error(), "outside the output directory") {
+		t.Errorf("acme/evil problem = %v, want the traversal rejected", err)
+	}
+	if err := problems[good]; err != nil {
+		t.Errorf("acme/app problem = %v, want none", err)
+	}
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// limiter is a counting semaphore whose limit can change while it is in use
+type limiter struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+}
+
+// newLimiter creates a limiter allowing up to limit concurrent holders
+func newLimiter(limit int) *limiter {
+	l := &limiter{limit: limit}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until a slot is free and takes it
+func (l *limiter) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+}
+
+// release frees a slot taken by acquire
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	l.cond.Broadcast()
+}
+
+// setLimit changes the number of slots; holders beyond a lowered limit keep
+// their slot until they release it
+func (l *limiter) setLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	l.cond.Broadcast()
+}
+
+// pause stops handing out slots until the next setLimit
+func (l *limiter) pause() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = 0
+}
+
+// rampUp raises the limit from 1 to max by one slot per interval, stopping early when done closes
+func (l *limiter) rampUp(max int, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for limit := 1; limit < max; {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			limit++
+			l.setLimit(limit)
+			util.Debug(fmt.Sprintf("Raised clone concurrency to %d/%d", limit, max))
+		}
+	}
+}
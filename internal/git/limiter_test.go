@@ -0,0 +1,146 @@
+package git
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// activeCount returns the number of slots currently held
+func activeCount(l *limiter) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.active
+}
+
+// holdAll starts n goroutines that each take a slot and keep it until release closes
+func holdAll(l *limiter, n int, release <-chan struct{}) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.acquire()
+			<-release
+			l.release()
+		}()
+	}
+	return &wg
+}
+
+// waitForActive polls until l has want active holders, failing the test after a second
+func waitForActive(t *testing.T, l *limiter, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for activeCount(l) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("active = %d, want %d", activeCount(l), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiterSetLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		initial, limit int
+		want           int
+	}{
+		{"raise", 1, 3, 3},
+		{"lower keeps current holders", 3, 1, 3},
+		{"below one", 1, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter(tt.initial)
+			release := make(chan struct{})
+			wg := holdAll(l, 4, release)
+			waitForActive(t, l, tt.initial)
+
+			l.setLimit(tt.limit)
+			waitForActive(t, l, tt.want)
+			time.Sleep(10 * time.Millisecond)
+			if got := activeCount(l); got != tt.want {
+				t.Errorf("active = %d after setLimit(%d), want %d", got, tt.limit, tt.want)
+			}
+			close(release)
+			wg.Wait()
+		})
+	}
+}
+
+func TestLimiterPause(t *testing.T) {
+	l := newLimiter(2)
+	l.pause()
+	release := make(chan struct{})
+	wg := holdAll(l, 2, release)
+	time.Sleep(10 * time.Millisecond)
+	if got := activeCount(l); got != 0 {
+		t.Fatalf("active = %d while paused, want 0", got)
+	}
+	l.setLimit(2)
+	waitForActive(t, l, 2)
+	close(release)
+	wg.Wait()
+}
+
+func TestLimiterRampUp(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int
+	}{
+		{"to three", 3},
+		{"to five", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter(1)
+			release := make(chan struct{})
+			wg := holdAll(l, tt.max+2, release)
+			waitForActive(t, l, 1)
+			done := make(chan struct{})
+			finished := make(chan struct{})
+			go func() {
+				l.rampUp(tt.max, 20*time.Millisecond, done)
+				close(finished)
+			}()
+
+			// Concurrency climbs one slot at a time instead of jumping to max
+			seen := []int{activeCount(l)}
+			deadline := time.Now().Add(time.Second)
+			for seen[len(seen)-1] < tt.max && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+				if active := activeCount(l); active != seen[len(seen)-1] {
+					seen = append(seen, active)
+				}
+			}
+			for i, active := range seen {
+				if active != i+1 {
+					t.Fatalf("active concurrency went %v, want 1 up to %d one step at a time", seen, tt.max)
+				}
+			}
+			if len(seen) != tt.max {
+				t.Fatalf("active concurrency went %v, want it to reach %d", seen, tt.max)
+			}
+
+			<-finished
+			close(done)
+			time.Sleep(30 * time.Millisecond)
+			if got := activeCount(l); got != tt.max {
+				t.Errorf("active = %d after the ramp-up, want it capped at %d", got, tt.max)
+			}
+			close(release)
+			wg.Wait()
+		})
+	}
+}
+
+func TestLimiterRampUpStopsWhenDone(t *testing.T) {
+	l := newLimiter(1)
+	done := make(chan struct{})
+	close(done)
+	l.rampUp(5, time.Millisecond, done)
+	if l.limit != 1 {
+		t.Errorf("limit = %d after a stopped ramp-up, want 1", l.limit)
+	}
+}
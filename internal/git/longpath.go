@@ -0,0 +1,67 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// windowsMaxPath is the classic MAX_PATH limit on Windows, including the terminating NUL
+const windowsMaxPath = 260
+
+// longPathMargin leaves room for files nested inside the clone target
+const longPathMargin = 60
+
+// isWindows reports whether long-path handling applies on this platform
+func isWindows() bool {
+	return runtime.GOOS == "windows"
+}
+
+// longPath converts an absolute Windows path to its extended-length form (\\?\C:\... or \\?\UNC\server\share\...)
+func longPath(path string) string {
+	switch {
+	case strings.HasPrefix(path, `\\?\`):
+		return path
+	case strings.HasPrefix(path, `\\`):
+		return `\\?\UNC\` + strings.TrimPrefix(path, `\\`)
+	default:
+		return `\\?\` + path
+	}
+}
+
+// exceedsMaxPath reports whether files inside dir are likely to exceed MAX_PATH
+func exceedsMaxPath(dir string) bool {
+	return len(dir)+longPathMargin >= windowsMaxPath
+}
+
+// checkPathLength warns when a clone target on Windows is deep enough that
+// files inside it may exceed MAX_PATH. git is run with core.longpaths enabled,
+// which handles such paths when long paths are enabled in the OS.
+func checkPathLength(targetDir string) {
+	if !isWindows() {
+		return
+	}
+	abs, err := filepath.Abs(targetDir)
+	if err != nil {
+		return
+	}
+	if exceedsMaxPath(abs) {
+		util.Warn(fmt.Sprintf("Clone target %s is close to the Windows MAX_PATH limit; using extended-length path %s", abs, longPath(abs)))
+	}
+}
+
+// fsPath returns the path used for filesystem operations on the clone target,
+// switching to the extended-length form on Windows when the path is too long
+func fsPath(path string) string {
+	if !isWindows() {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil || !exceedsMaxPath(abs) {
+		return path
+	}
+	return longPath(abs)
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLongPath(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{`C:\src\acme\app`, `\\?\C:\src\acme\app`},
+		{`\\server\share\acme\app`, `\\?\UNC\server\share\acme\app`},
+		{`\\?\C:\src\acme\app`, `\\?\C:\src\acme\app`},
+	}
+	for _, tt := range tests {
+		if got := longPath(tt.path); got != tt.want {
+			t.Errorf("longPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExceedsMaxPath(t *testing.T) {
+	tests := []struct {
+		length int
+		want   bool
+	}{
+		{10, false},
+		{windowsMaxPath - longPathMargin - 1, false},
+		{windowsMaxPath - longPathMargin, true},
+		{windowsMaxPath, true},
+	}
+	for _, tt := range tests {
+		if got := exceedsMaxPath(strings.Repeat("a", tt.length)); got != tt.want {
+			t.Errorf("exceedsMaxPath(%d chars) = %v, want %v", tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestFSPath(t *testing.T) {
+	base := t.TempDir()
+	short := filepath.Join(base, "acme", "app")
+	long := filepath.Join(base, strings.Repeat("deep", 60), "app")
+
+	tests := []struct {
+		name, path string
+		// wantLong is whether the extended-length form is used
+		wantLong bool
+	}{
+		{"short", short, false},
+		{"long", long, runtime.GOOS == "windows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.path
+			if tt.wantLong {
+				want = longPath(tt.path)
+			}
+			if got := fsPath(tt.path); got != want {
+				t.Errorf("fsPath() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLongPathsConfig(t *testing.T) {
+	args := gitConfigArgs(CloneOptions{})
+	enabled := slices.Contains(args, "core.longpaths=true")
+	if want := runtime.GOOS == "windows"; enabled != want {
+		t.Errorf("gitConfigArgs() = %v, core.longpaths enabled %v, want %v", args, enabled, want)
+	}
+}
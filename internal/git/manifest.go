@@ -0,0 +1,143 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// SessionManifest records the final state of every repository in a clone run
+type SessionManifest struct {
+	GeneratedAt  time.Time            `json:"generated_at"`
+	Repositories []RepositorySnapshot `json:"repositories"`
+}
+
+// commitResolver returns the commit recorded for a successful clone in dir, or ""
+type commitResolver func(dir string, repo RepositorySnapshot) string
+
+// Manifest captures the current state of all managed repositories, with the
+// path and commit of each successful clone
+func (rm *RepositoryManager) Manifest() *SessionManifest {
+	rm.mu.RLock()
+	mode := rm.defaults.Mode
+	rm.mu.RUnlock()
+
+	return rm.manifest(func(dir string, repo RepositorySnapshot) string {
+		return cloneCommit(context.Background(), dir, mode, repo.DefaultBranch)
+	})
+}
+
+// manifest builds the session manifest, resolving commits with resolve
+func (rm *RepositoryManager) manifest(resolve commitResolver) *SessionManifest {
+	repos := rm.GetRepositories()
+
+	rm.mu.RLock()
+	targets := make(map[*Repository]string, len(repos))
+	for _, repo := range repos {
+		if dir, err := rm.targetDir(repo); err == nil {
+			targets[repo] = dir
+		}
+	}
+	rm.mu.RUnlock()
+
+	manifest := &SessionManifest{GeneratedAt: time.Now().UTC()}
+	for _, repo := range repos {
+		snapshot := repo.Snapshot()
+		snapshot.Progress = ""
+		if dir, ok := targets[repo]; ok && snapshot.Status == StatusSuccess.String() {
+			snapshot.Path = dir
+			snapshot.Commit = resolve(dir, snapshot)
+		}
+		manifest.Repositories = append(manifest.Repositories, snapshot)
+	}
+	return manifest
+}
+
+// cloneCommit returns the commit checked out in dir; bare and mirror clones
+// have no checkout and record their default branch instead
+func cloneCommit(ctx context.Context, dir string, mode CloneMode, defaultBranch string) string {
+	if mode != CloneNormal && defaultBranch != "" {
+		if sha := revParse(ctx, dir, "refs/heads/"+defaultBranch); sha != "" {
+			return sha
+		}
+	}
+	return revParse(ctx, dir, "HEAD")
+}
+
+// WriteManifest writes a session manifest to path as JSON
+func WriteManifest(path string, manifest *SessionManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadManifest reads a session manifest written by WriteManifest
+func LoadManifest(path string) (*SessionManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+	var manifest SessionManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+// RunDiff lists the repositories whose presence or outcome changed between two runs
+type RunDiff struct {
+	Added           []string `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed         []string `json:"removed,omitempty" yaml:"removed,omitempty"`
+	NewlyFailing    []string `json:"newly_failing,omitempty" yaml:"newly_failing,omitempty"`
+	NewlySucceeding []string `json:"newly_succeeding,omitempty" yaml:"newly_succeeding,omitempty"`
+}
+
+// IsEmpty reports whether the two runs had the same repositories and outcomes
+func (d *RunDiff) IsEmpty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.NewlyFailing)+len(d.NewlySucceeding) == 0
+}
+
+// DiffManifests compares an earlier manifest with a later one
+func DiffManifests(before, after *SessionManifest) *RunDiff {
+	index := func(m *SessionManifest) map[string]string {
+		statuses := make(map[string]string, len(m.Repositories))
+		for _, repo := range m.Repositories {
+			statuses[repo.Organization+"/"+repo.Name] = repo.Status
+		}
+		return statuses
+	}
+	old, current := index(before), index(after)
+	failed := StatusFailed.String()
+
+	diff := &RunDiff{}
+	for name, status := range current {
+		previous, ok := old[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case status == failed && previous != failed:
+			diff.NewlyFailing = append(diff.NewlyFailing, name)
+		case status != failed && previous == failed:
+			diff.NewlySucceeding = append(diff.NewlySucceeding, name)
+		}
+	}
+	for name := range old {
+		if _, ok := current[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.NewlyFailing)
+	sort.Strings(diff.NewlySucceeding)
+	return diff
+}
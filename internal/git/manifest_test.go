@@ -0,0 +1,183 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testManifest builds a manifest from "org/name=status" entries
+func testManifest(entries ...string) *SessionManifest {
+	manifest := &SessionManifest{}
+	for _, entry := range entries {
+		name, status, _ := strings.Cut(entry, "=")
+		org, repo, _ := strings.Cut(name, "/")
+		manifest.Repositories = append(manifest.Repositories, RepositorySnapshot{Organization: org, Name: repo, Status: status})
+	}
+	return manifest
+}
+
+func TestDiffManifests(t *testing.T) {
+	tests := []struct {
+		name            string
+		before, after   *SessionManifest
+		added, removed  []string
+		failing, passes []string
+	}{
+		{
+			name:   "identical",
+			before: testManifest("acme/app=Success", "acme/web=Failed"),
+			after:  testManifest("acme/web=Failed", "acme/app=Success"),
+		},
+		{
+			name:    "added and removed",
+			before:  testManifest("acme/app=Success", "acme/old=Success"),
+			after:   testManifest("acme/app=Success", "acme/new=Failed", "acme/api=Success"),
+			added:   []string{"acme/api", "acme/new"},
+			removed: []string{"acme/old"},
+		},
+		{
+			name:    "outcome changes",
+			before:  testManifest("acme/app=Success", "acme/web=Failed", "acme/lib=Skipped", "acme/cli=Failed"),
+			after:   testManifest("acme/app=Failed", "acme/web=Success", "acme/lib=Failed", "acme/cli=Skipped"),
+			failing: []string{"acme/app", "acme/lib"},
+			passes:  []string{"acme/cli", "acme/web"},
+		},
+		{
+			name:   "success to skipped is not a change",
+			before: testManifest("acme/app=Success"),
+			after:  testManifest("acme/app=Skipped"),
+		},
+		{
+			name:   "empty first run",
+			before: testManifest(),
+			after:  testManifest("acme/b=Success", "acme/a=Failed"),
+			added:  []string{"acme/a", "acme/b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffManifests(tt.before, tt.after)
+			check := func(label string, got, want []string) {
+				if strings.Join(got, ",") != strings.Join(want, ",") {
+					t.Errorf("%s = %v, want %v", label, got, want)
+				}
+			}
+			check("added", diff.Added, tt.added)
+			check("removed", diff.Removed, tt.removed)
+			check("newly failing", diff.NewlyFailing, tt.failing)
+			check("newly succeeding", diff.NewlySucceeding, tt.passes)
+			if want := len(tt.added)+len(tt.removed)+len(tt.failing)+len(tt.passes) == 0; diff.IsEmpty() != want {
+				t.Errorf("IsEmpty() = %v, want %v", diff.IsEmpty(), want)
+			}
+		})
+	}
+}
+
+func TestManifestFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.json")
+	if err := WriteManifest(before, testManifest("acme/app=Success", "acme/web=Success")); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteManifest(after, testManifest("acme/app=Failed")); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := LoadManifest(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadManifest(after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := DiffManifests(a, b)
+	if strings.Join(diff.NewlyFailing, ",") != "acme/app" || strings.Join(diff.Removed, ",") != "acme/web" {
+		t.Errorf("diff = %+v, want acme/app newly failing and acme/web removed", diff)
+	}
+
+	writeFile(t, filepath.Join(dir, "bad.json"), "not json")
+	for _, path := range []string{filepath.Join(dir, "missing.json"), filepath.Join(dir, "bad.json")} {
+		if _, err := LoadManifest(path); err == nil {
+			t.Errorf("LoadManifest(%s) succeeded", filepath.Base(path))
+		}
+	}
+}
+
+func TestManifestResolvesCommits(t *testing.T) {
+	base := t.TempDir()
+	manager := NewRepositoryManager(base, 1)
+	statuses := []struct {
+		name   string
+		status RepositoryStatus
+		// listed reports whether the manifest records a path and commit
+		listed bool
+	}{
+		{"cloned", StatusSuccess, true},
+		{"broken", StatusFailed, false},
+		{"skipped", StatusSkipped, false},
+	}
+	for _, s := range statuses {
+		repo := manager.AddRepository("acme", s.name, "https://github.com/acme/"+s.name+".git", "", SkipExisting)
+		repo.SetDefaultBranch("main")
+		repo.UpdateStatus(s.status, nil)
+	}
+
+	var resolved []string
+	manifest := manager.manifest(func(dir string, repo RepositorySnapshot) string {
+		resolved = append(resolved, repo.Name+"@"+repo.DefaultBranch)
+		return "sha-" + repo.Name
+	})
+	if strings.Join(resolved, ",") != "cloned@main" {
+		t.Errorf("resolved commits of %v, want only cloned@main", resolved)
+	}
+
+	for i, s := range statuses {
+		t.Run(s.name, func(t *testing.T) {
+			got := manifest.Repositories[i]
+			wantPath, wantCommit := "", ""
+			if s.listed {
+				wantPath, wantCommit = filepath.Join(base, "acme", s.name), "sha-"+s.name
+			}
+			if got.Path != wantPath || got.Commit != wantCommit {
+				t.Errorf("path, commit = %q, %q; want %q, %q", got.Path, got.Commit, wantPath, wantCommit)
+			}
+		})
+	}
+}
+
+func TestCloneCommit(t *testing.T) {
+	// HEAD of the remote points at develop, which is behind main
+	remote := newFixtureRemote(t, "develop")
+	pushCommit(t, remote, "CHANGES.md", "changed\n")
+	runGit(t, remote, "symbolic-ref", "HEAD", "refs/heads/develop")
+	mainSHA := runGit(t, remote, "rev-parse", "main")
+	developSHA := runGit(t, remote, "rev-parse", "develop")
+
+	tests := []struct {
+		name          string
+		mode          CloneMode
+		defaultBranch string
+		want          string
+	}{
+		{"checkout records HEAD", CloneNormal, "main", developSHA},
+		{"mirror records the default branch", CloneMirror, "main", mainSHA},
+		{"bare records the default branch", CloneBare, "main", mainSHA},
+		{"mirror without a default branch", CloneMirror, "", developSHA},
+		{"mirror with an unknown default branch", CloneMirror, "trunk", developSHA},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			opts.Mode = tt.mode
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			if got := cloneCommit(t.Context(), opts.TargetDir, tt.mode, tt.defaultBranch); got != tt.want {
+				t.Errorf("cloneCommit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
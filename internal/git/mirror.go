@@ -0,0 +1,87 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CloneMode selects the kind of clone made of each repository
+type CloneMode int
+
+const (
+	// CloneNormal makes a regular clone with a working tree
+	CloneNormal CloneMode = iota
+	// CloneBare makes a bare clone of the branches and tags, without a working tree
+	CloneBare
+	// CloneMirror makes a bare clone of every ref, kept identical to the remote on update
+	CloneMirror
+)
+
+// ParseCloneMode parses a clone mode name (normal, bare, mirror)
+func ParseCloneMode(name string) (CloneMode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "normal":
+		return CloneNormal, nil
+	case "bare":
+		return CloneBare, nil
+	case "mirror":
+		return CloneMirror, nil
+	default:
+		return CloneNormal, fmt.Errorf("unknown clone mode %q (use normal, bare or mirror)", name)
+	}
+}
+
+// isBareRepo checks if a directory is a bare git repository
+func isBareRepo(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil || info.IsDir() {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(dir, "objects"))
+	return err == nil && info.IsDir()
+}
+
+// hasClone reports whether opts.TargetDir already holds a clone of the configured mode
+func hasClone(opts CloneOptions) bool {
+	if opts.Mode != CloneNormal {
+		return isBareRepo(opts.TargetDir)
+	}
+	return isGitRepo(opts.TargetDir)
+}
+
+// buildBareUpdateArgs builds the git arguments updating a bare or mirror clone.
+// A mirror's refspec already covers every ref; a bare clone has no refspec, so
+// branches and tags are fetched onto themselves.
+func buildBareUpdateArgs(opts CloneOptions) []string {
+	args := append(gitConfigArgs(opts), tokenRewriteArgs(opts)...)
+	if opts.Mode == CloneMirror {
+		return append(args, "remote", "update", "--prune")
+	}
+	return append(args, "fetch", "--prune", "origin", "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*")
+}
+
+// updateBare fetches into a bare or mirror clone; with no working tree there is nothing to reset
+func updateBare(ctx context.Context, opts CloneOptions) error {
+	cloneEvent{url: opts.URL, status: StatusUpdating}.info(fmt.Sprintf("Updating existing repository: %s", opts.URL))
+	opts.ProgressFunc(fmt.Sprintf("Updating existing repository: %s", opts.URL))
+
+	fetchCtx, cancel := context.WithTimeout(ctx, updateTimeout(opts))
+	defer cancel()
+	cmd := gitCommand(fetchCtx, opts, buildBareUpdateArgs(opts)...)
+	cmd.Dir = opts.TargetDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if authErr := authRequired(string(output), opts); authErr != nil {
+			cloneEvent{url: opts.URL, status: StatusFailed}.error("Failed to fetch updates", authErr)
+			return authErr
+		}
+		redactedOutput := redactToken(string(output), opts.Token)
+		cloneEvent{url: opts.URL, status: StatusFailed}.error("Failed to fetch updates", fmt.Errorf("%w: %s", err, redactedOutput))
+		return fmt.Errorf("failed to fetch updates: %w\nOutput: %s", err, redactedOutput)
+	}
+
+	cloneEvent{url: opts.URL, status: StatusSuccess}.info(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+	opts.ProgressFunc(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+	return nil
+}
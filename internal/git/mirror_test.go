@@ -0,0 +1,105 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCloneMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    CloneMode
+		wantErr bool
+	}{
+		{"", CloneNormal, false},
+		{"normal", CloneNormal, false},
+		{"bare", CloneBare, false},
+		{" Mirror ", CloneMirror, false},
+		{"shallow", CloneNormal, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCloneMode(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCloneMode(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCloneModeArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   CloneMode
+		clone  string
+		update string
+		absent []string
+	}{
+		{"bare", CloneBare, "clone --bare -b develop", "fetch --prune origin +refs/heads/*:refs/heads/* +refs/tags/*:refs/tags/*", []string{"--mirror"}},
+		{"mirror", CloneMirror, "clone --mirror", "remote update --prune", []string{"--bare", "-b", "--single-branch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := CloneOptions{URL: "https://github.com/acme/api.git", TargetDir: "api.git", Branch: "develop", DefaultBranch: "main", Mode: tt.mode}
+			clone := strings.Join(buildCloneArgs(opts), " ")
+			if !strings.Contains(clone, tt.clone) {
+				t.Errorf("clone args %q missing %q", clone, tt.clone)
+			}
+			update := strings.Join(buildBareUpdateArgs(opts), " ")
+			if !strings.Contains(update, tt.update) {
+				t.Errorf("update args %q missing %q", update, tt.update)
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(clone, absent) {
+					t.Errorf("clone args %q contain %q", clone, absent)
+				}
+			}
+		})
+	}
+}
+
+func TestBareAndMirrorUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		mode CloneMode
+		// allRefs reports whether refs outside branches and tags are kept in sync
+		allRefs bool
+	}{
+		{"bare", CloneBare, false},
+		{"mirror", CloneMirror, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newFixtureRemote(t, "old")
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo.git"))
+			opts.Mode = tt.mode
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			if !isBareRepo(opts.TargetDir) || isGitRepo(opts.TargetDir) {
+				t.Fatal("clone is not a bare repository")
+			}
+
+			pushCommit(t, remote, "CHANGES.md", "changed\n")
+			runGit(t, remote, "branch", "-D", "old")
+			runGit(t, remote, "tag", "v1.0.0", "main")
+			runGit(t, remote, "update-ref", "refs/backup/main", "main")
+
+			opts.ExistingRepo = FetchOnly
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("update failed: %v", result.Error)
+			}
+			for _, ref := range []string{"main", "v1.0.0"} {
+				if got, want := runGit(t, opts.TargetDir, "rev-parse", ref), runGit(t, remote, "rev-parse", ref); got != want {
+					t.Errorf("%s = %s after the update, want %s", ref, got, want)
+				}
+			}
+			if branches := runGit(t, opts.TargetDir, "branch", "--list", "old"); branches != "" {
+				t.Errorf("branch deleted on the remote survived the update: %s", branches)
+			}
+			backup := runGit(t, opts.TargetDir, "for-each-ref", "refs/backup")
+			if (backup != "") != tt.allRefs {
+				t.Errorf("refs/backup/main fetched = %v, want %v", backup != "", tt.allRefs)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadDependencyOrder reads a YAML file mapping each repository ("org/name") to
+// the repositories it depends on, e.g.
+//
+//	acme/app: [acme/lib, acme/proto]
+//	acme/lib: [acme/proto]
+func LoadDependencyOrder(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dependency order %s: %w", path, err)
+	}
+
+	var raw map[string][]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse dependency order %s: %w", path, err)
+	}
+
+	deps := make(map[string][]string, len(raw))
+	for repo, requires := range raw {
+		key := strings.ToLower(repo)
+		for _, dep := range requires {
+			deps[key] = append(deps[key], strings.ToLower(dep))
+		}
+	}
+	return deps, nil
+}
+
+// cloneWaves groups names into waves in topological order: every repository
+// comes in a later wave than the repositories it depends on. Dependencies on
+// repositories outside names are ignored. Within a wave, names keep their input
+// order. A dependency cycle is an error.
+func cloneWaves(names []string, deps map[string][]string) ([][]string, error) {
+	included := make(map[string]bool, len(names))
+	for _, name := range names {
+		included[name] = true
+	}
+
+	pending := make(map[string]int, len(names))
+	for _, name := range names {
+		for _, dep := range deps[name] {
+			if included[dep] && dep != name {
+				pending[name]++
+			} else if dep == name {
+				return nil, fmt.Errorf("dependency cycle: %s depends on itself", name)
+			}
+		}
+	}
+
+	done := make(map[string]bool, len(names))
+	var waves [][]string
+	for len(done) < len(names) {
+		var wave []string
+		for _, name := range names {
+			if !done[name] && pending[name] == 0 {
+				wave = append(wave, name)
+			}
+		}
+		if len(wave) == 0 {
+			var cycle []string
+			for _, name := range names {
+				if !done[name] {
+					cycle = append(cycle, name)
+				}
+			}
+			sort.Strings(cycle)
+			return nil, fmt.Errorf("dependency cycle among: %s", strings.Join(cycle, ", "))
+		}
+
+		for _, name := range wave {
+			done[name] = true
+		}
+		for _, name := range names {
+			for _, dep := range deps[name] {
+				if included[dep] && contains(wave, dep) {
+					pending[name]--
+				}
+			}
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+// contains reports whether s is in list
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
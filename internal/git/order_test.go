@@ -0,0 +1,176 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCloneWaves(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		deps    map[string][]string
+		want    string
+		wantErr string
+	}{
+		{"no dependencies", []string{"a/app", "a/lib"}, nil, "[a/app a/lib]", ""},
+		{
+			name:  "chain",
+			names: []string{"a/app", "a/lib", "a/proto"},
+			deps:  map[string][]string{"a/app": {"a/lib"}, "a/lib": {"a/proto"}},
+			want:  "[a/proto] [a/lib] [a/app]",
+		},
+		{
+			name:  "diamond keeps input order within a wave",
+			names: []string{"a/app", "a/web", "a/api", "a/proto"},
+			deps:  map[string][]string{"a/app": {"a/web", "a/api"}, "a/web": {"a/proto"}, "a/api": {"a/proto"}},
+			want:  "[a/proto] [a/web a/api] [a/app]",
+		},
+		{
+			name:  "dependency outside the run is ignored",
+			names: []string{"a/app", "a/lib"},
+			deps:  map[string][]string{"a/app": {"a/lib", "other/sdk"}, "a/lib": {"other/sdk"}},
+			want:  "[a/lib] [a/app]",
+		},
+		{
+			name:    "cycle",
+			names:   []string{"a/app", "a/lib", "a/proto"},
+			deps:    map[string][]string{"a/lib": {"a/proto"}, "a/proto": {"a/lib"}},
+			wantErr: "dependency cycle among: a/lib, a/proto",
+		},
+		{
+			name:    "self dependency",
+			names:   []string{"a/app"},
+			deps:    map[string][]string{"a/app": {"a/app"}},
+			wantErr: "a/app depends on itself",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waves, err := cloneWaves(tt.names, tt.deps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("cloneWaves() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cloneWaves() error = %v", err)
+			}
+			var got []string
+			for _, wave := range waves {
+				got = append(got, fmt.Sprint(wave))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("cloneWaves() = %s, want %s", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDependencyOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "lowercased",
+			content: "Acme/App: [acme/Lib, acme/proto]\nacme/lib: [acme/proto]\n",
+			want:    map[string]string{"acme/app": "acme/lib acme/proto", "acme/lib": "acme/proto"},
+		},
+		{"invalid", "acme/app: {", nil, "failed to parse dependency order"},
+		{"missing", "", nil, "failed to read dependency order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "deps.yaml")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			deps, err := LoadDependencyOrder(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadDependencyOrder() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadDependencyOrder() error = %v", err)
+			}
+			if len(deps) != len(tt.want) {
+				t.Errorf("LoadDependencyOrder() = %v, want %v", deps, tt.want)
+			}
+			for repo, want := range tt.want {
+				if got := strings.Join(deps[repo], " "); got != want {
+					t.Errorf("dependencies of %s = %q, want %q", repo, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCloneAllInDependencyOrder(t *testing.T) {
+	// Results are matched to repositories by URL, so each needs its own remote
+	app, lib := newFixtureRemote(t), newFixtureRemote(t)
+	missing := filepath.Join(t.TempDir(), "missing.git")
+
+	tests := []struct {
+		name string
+		// urls maps each repository to its remote
+		urls map[string]string
+		deps map[string][]string
+		want map[string]string
+	}{
+		{
+			name: "dependencies cloned",
+			urls: map[string]string{"app": app, "lib": lib},
+			deps: map[string][]string{"acme/app": {"acme/lib"}},
+			want: map[string]string{"app": "", "lib": ""},
+		},
+		{
+			name: "failed dependency",
+			urls: map[string]string{"app": app, "lib": missing},
+			deps: map[string][]string{"acme/app": {"acme/lib"}},
+			want: map[string]string{"app": "not cloned because dependency acme/lib failed", "lib": "failed to clone"},
+		},
+		{
+			name: "cycle",
+			urls: map[string]string{"app": app, "lib": lib},
+			deps: map[string][]string{"acme/app": {"acme/lib"}, "acme/lib": {"acme/app"}},
+			want: map[string]string{"app": "dependency cycle", "lib": "dependency cycle"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(t.TempDir(), 2)
+			manager.SetCloneDefaults(testCloneOptions("", ""))
+			manager.SetDependencies(tt.deps)
+			for _, name := range []string{"app", "lib"} {
+				manager.AddRepository("acme", name, tt.urls[name], "", SkipExisting)
+			}
+			for range manager.CloneAll(t.Context()) {
+			}
+
+			for _, repo := range manager.GetRepositories() {
+				want := tt.want[repo.Name]
+				status, err, _ := repo.GetStatus()
+				if want == "" {
+					if status != StatusSuccess {
+						t.Errorf("%s: status %v (%v), want success", repo.Name, status, err)
+					}
+					continue
+				}
+				if status != StatusFailed || err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("%s: status %v, error %v, want a failure with %q", repo.Name, status, err, want)
+				}
+			}
+		})
+	}
+}
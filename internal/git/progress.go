@@ -0,0 +1,67 @@
+package git
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+)
+
+// progressPattern matches git progress lines such as
+// "Receiving objects:  42% (123/456), 1.20 MiB | 2.00 MiB/s"
+var progressPattern = regexp.MustCompile(`^(?:remote: )?([A-Za-z ]+):\s+(\d{1,3})% \(`)
+
+// parseProgressLine extracts the phase and percentage from a git progress line
+func parseProgressLine(line string) (phase string, percent float64, ok bool) {
+	match := progressPattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", 0, false
+	}
+	value, err := strconv.Atoi(match[2])
+	if err != nil || value > 100 {
+		return "", 0, false
+	}
+	return match[1], float64(value), true
+}
+
+// progressWriter collects git output while reporting progress lines as they
+// arrive. Git rewrites progress lines in place with carriage returns, so both
+// '\r' and '\n' end a line.
+type progressWriter struct {
+	output  bytes.Buffer
+	partial []byte
+	report  func(phase string, percent float64)
+}
+
+// newProgressWriter creates a writer reporting parsed progress to report
+func newProgressWriter(report func(phase string, percent float64)) *progressWriter {
+	return &progressWriter{report: report}
+}
+
+// Write implements io.Writer
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.output.Write(p)
+	for _, b := range p {
+		if b != '\r' && b != '\n' {
+			w.partial = append(w.partial, b)
+			continue
+		}
+		w.flushLine()
+	}
+	return len(p), nil
+}
+
+// flushLine reports the buffered line if it is a progress line
+func (w *progressWriter) flushLine() {
+	if len(w.partial) == 0 {
+		return
+	}
+	if phase, percent, ok := parseProgressLine(string(w.partial)); ok && w.report != nil {
+		w.report(phase, percent)
+	}
+	w.partial = w.partial[:0]
+}
+
+// Output returns everything written so far
+func (w *progressWriter) Output() []byte {
+	return w.output.Bytes()
+}
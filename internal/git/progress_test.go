@@ -0,0 +1,89 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseProgressLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		phase   string
+		percent float64
+		ok      bool
+	}{
+		{"Receiving objects:  42% (123/456), 1.20 MiB | 2.00 MiB/s", "Receiving objects", 42, true},
+		{"Resolving deltas: 100% (80/80), done.", "Resolving deltas", 100, true},
+		{"remote: Counting objects:   7% (1/14)", "Counting objects", 7, true},
+		{"remote: Compressing objects: 100% (10/10), done.", "Compressing objects", 100, true},
+		{"Updating files:   0% (0/3)", "Updating files", 0, true},
+		{"Cloning into 'app'...", "", 0, false},
+		{"remote: Enumerating objects: 14, done.", "", 0, false},
+		{"Receiving objects: 420% (1/2)", "", 0, false},
+		{"", "", 0, false},
+	}
+	for _, tt := range tests {
+		phase, percent, ok := parseProgressLine(tt.line)
+		if phase != tt.phase || percent != tt.percent || ok != tt.ok {
+			t.Errorf("parseProgressLine(%q) = %q, %v, %v, want %q, %v, %v", tt.line, phase, percent, ok, tt.phase, tt.percent, tt.ok)
+		}
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	output := "Cloning into 'app'...\n" +
+		"remote: Counting objects:  50% (1/2)\rremote: Counting objects: 100% (2/2), done.\n" +
+		"Receiving objects:  33% (1/3)\rReceiving objects:  66% (2/3)\rReceiving objects: 100% (3/3), done.\n" +
+		"Resolving deltas: 100% (1/1)"
+
+	tests := []struct {
+		name string
+		// chunk is the size of each Write, splitting lines at arbitrary points
+		chunk int
+	}{
+		{"single write", len(output)},
+		{"byte at a time", 1},
+		{"odd chunks", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reports []string
+			w := newProgressWriter(func(phase string, percent float64) {
+				reports = append(reports, fmt.Sprintf("%s %.0f", phase, percent))
+			})
+			for start := 0; start < len(output); start += tt.chunk {
+				end := min(start+tt.chunk, len(output))
+				w.Write([]byte(output[start:end]))
+			}
+
+			// The last line has no terminator yet, so it is not reported
+			want := "Counting objects 50,Counting objects 100,Receiving objects 33,Receiving objects 66,Receiving objects 100"
+			if got := strings.Join(reports, ","); got != want {
+				t.Errorf("reports = %s, want %s", got, want)
+			}
+			if string(w.Output()) != output {
+				t.Errorf("Output() = %q, want everything written", w.Output())
+			}
+		})
+	}
+}
+
+func TestClonerReportsPercent(t *testing.T) {
+	remote := newFixtureRemote(t)
+	opts := testCloneOptions("file://"+remote, filepath.Join(t.TempDir(), "repo"))
+	var phases []string
+	opts.PercentFunc = func(phase string, percent float64) {
+		if percent < 0 || percent > 100 {
+			t.Errorf("%s reported %v%%", phase, percent)
+		}
+		phases = append(phases, phase)
+	}
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+	if len(phases) == 0 {
+		t.Error("no progress was reported during the clone")
+	}
+}
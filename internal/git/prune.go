@@ -0,0 +1,97 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// PhasePrune is the trace phase covering the pruning of unwanted files
+const PhasePrune = "prune"
+
+// normalizeExtensions lowercases extensions and ensures each has a leading dot
+func normalizeExtensions(exts []string) map[string]bool {
+	normalized := make(map[string]bool, len(exts))
+	for _, ext := range exts {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		normalized[ext] = true
+	}
+	return normalized
+}
+
+// pruneWorkingTree removes every file under dir whose extension is not in
+// exts, then any directories left empty. The .git directory (or .git file of
+// a submodule or worktree) is never touched. It returns the number of files removed.
+func pruneWorkingTree(dir string, exts []string) (int, error) {
+	keep := normalizeExtensions(exts)
+	if len(keep) == 0 {
+		return 0, nil
+	}
+
+	removed := 0
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if keep[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to prune %s: %w", dir, err)
+	}
+
+	// Remove emptied directories deepest first; non-empty ones fail and are kept
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, d := range dirs {
+		os.Remove(d)
+	}
+	return removed, nil
+}
+
+// pruneClone prunes a clone to opts.KeepExtensions, recording the phase timing
+func pruneClone(ctx context.Context, opts CloneOptions, trace *cloneTrace) error {
+	if len(opts.KeepExtensions) == 0 {
+		return nil
+	}
+	start := time.Now()
+	removed, err := pruneWorkingTree(fsPath(opts.TargetDir), opts.KeepExtensions)
+	trace.record(PhasePrune, start)
+	if err != nil {
+		util.Error("Failed to prune working tree", err)
+		return err
+	}
+	util.Debug(fmt.Sprintf("Pruned %d files from %s", removed, opts.TargetDir))
+	return nil
+}
@@ -0,0 +1,117 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeExtensions(t *testing.T) {
+	got := normalizeExtensions([]string{"go", ".MD", " yaml ", ""})
+	var keys []string
+	for ext := range got {
+		keys = append(keys, ext)
+	}
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != ".go,.md,.yaml" {
+		t.Errorf("normalizeExtensions() = %v, want .go, .md and .yaml", keys)
+	}
+}
+
+// treeFiles lists the files under dir relative to it, sorted
+func treeFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if info.IsDir() {
+			if rel != "." {
+				files = append(files, rel+"/")
+			}
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestPruneWorkingTree(t *testing.T) {
+	files := []string{
+		".git/HEAD",
+		".git/objects/pack/pack.idx",
+		"README.md",
+		"main.go",
+		"logo.PNG",
+		"bin/tool.exe",
+		"docs/guide.MD",
+		"docs/images/logo.svg",
+		"vendor/lib/.git",
+		"vendor/lib/lib.go",
+	}
+
+	tests := []struct {
+		name    string
+		exts    []string
+		removed int
+		want    []string
+	}{
+		{
+			name:    "keep go and md",
+			exts:    []string{"go", "md"},
+			removed: 3,
+			want: []string{
+				".git/", ".git/HEAD", ".git/objects/", ".git/objects/pack/", ".git/objects/pack/pack.idx",
+				"README.md", "docs/", "docs/guide.MD", "main.go",
+				"vendor/", "vendor/lib/", "vendor/lib/.git", "vendor/lib/lib.go",
+			},
+		},
+		{
+			name:    "keep nothing matching",
+			exts:    []string{"rs"},
+			removed: 7,
+			want: []string{
+				".git/", ".git/HEAD", ".git/objects/", ".git/objects/pack/", ".git/objects/pack/pack.idx",
+				"vendor/", "vendor/lib/", "vendor/lib/.git",
+			},
+		},
+		{
+			name:    "no extensions",
+			exts:    nil,
+			removed: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range files {
+				writeFile(t, filepath.Join(dir, file), file)
+			}
+			want := tt.want
+			if want == nil {
+				want = treeFiles(t, dir)
+			}
+
+			removed, err := pruneWorkingTree(dir, tt.exts)
+			if err != nil {
+				t.Fatalf("pruneWorkingTree() error = %v", err)
+			}
+			if removed != tt.removed {
+				t.Errorf("pruneWorkingTree() removed %d files, want %d", removed, tt.removed)
+			}
+			if got := treeFiles(t, dir); strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("tree after pruning = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"text/template"
+	"time"
 
 	"github.com/sachin-duhan/zikrr/pkg/util"
 )
@@ -50,9 +52,24 @@ type Repository struct {
 	Organization string
 	URL          string
 	Branch       string
+	Tag          string // checked out instead of Branch when set
+	Language     string
+	Size         int // size reported by GitHub, in KB
+	// DefaultBranch is the repository's default branch on GitHub
+	DefaultBranch string
+	// ClonedBranch is the branch actually checked out by the last clone or update
+	ClonedBranch string
 	Status       RepositoryStatus
 	Error        error
 	Progress     string
+	Phase        string  // git progress phase, e.g. "Receiving objects"
+	Percent      float64 // completion of Phase, 0-100
+	Empty        bool
+	Slow         bool
+	Retries      int // clone attempts retried after a failure
+	HookError    error
+	HookOutput   string
+	Duration     time.Duration
 	ExistingRepo ExistingRepoStrategy
 	mu           sync.RWMutex
 }
@@ -62,23 +79,207 @@ type RepositoryManager struct {
 	repositories []*Repository
 	baseDir      string
 	cloner       *ConcurrentCloner
-	mu           sync.RWMutex
+	defaults     CloneOptions
+	orgDirs      map[string]string
+	observers    []func(*Repository)
+	// dependencies maps a lowercased "org/name" to the repositories cloned before it
+	dependencies map[string][]string
+	diskCheck    DiskCheck
+	space        SpaceProvider
+	layout       Layout
+	pathTemplate *template.Template
+	// strategyOverrides replace the strategy given to AddRepository for matching repositories
+	strategyOverrides []StrategyOverride
+	// skipNewerThan skips existing clones synced within this window (0 = never)
+	skipNewerThan time.Duration
+	// startedAt and finishedAt bound the latest CloneAll batch
+	startedAt  time.Time
+	finishedAt time.Time
+	mu         sync.RWMutex
 }
 
 // NewRepositoryManager creates a new repository manager
 func NewRepositoryManager(baseDir string, maxConcurrent int) *RepositoryManager {
 	util.Info(fmt.Sprintf("Initializing repository manager with base directory: %s", baseDir))
 	return &RepositoryManager{
-		baseDir: baseDir,
-		cloner:  NewConcurrentCloner(maxConcurrent),
+		baseDir:  baseDir,
+		cloner:   NewConcurrentCloner(maxConcurrent),
+		defaults: DefaultCloneOptions(),
+		space:    filesystemSpace{},
 	}
 }
 
-// AddRepository adds a new repository to be managed
+// BaseDir returns the directory repositories are cloned into
+func (rm *RepositoryManager) BaseDir() string {
+	return rm.baseDir
+}
+
+// SetDiskCheck sets what CloneAll does when the pending repositories may not fit on disk
+func (rm *RepositoryManager) SetDiskCheck(mode DiskCheck) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.diskCheck = mode
+}
+
+// SetSpaceProvider replaces how free disk space is measured for the preflight
+func (rm *RepositoryManager) SetSpaceProvider(space SpaceProvider) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.space = space
+}
+
+// SetCloneDefaults sets the options used as the starting point for every clone.
+// Per-repository fields (URL, target directory, branch, strategy) are filled in by CloneAll.
+func (rm *RepositoryManager) SetCloneDefaults(opts CloneOptions) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if opts.ProgressFunc == nil {
+		opts.ProgressFunc = func(status string) {}
+	}
+	if opts.PercentFunc == nil {
+		opts.PercentFunc = func(phase string, percent float64) {}
+	}
+	rm.defaults = opts
+}
+
+// CloneDefaults returns the options set with SetCloneDefaults
+func (rm *RepositoryManager) CloneDefaults() CloneOptions {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	return rm.defaults
+}
+
+// SetRampUp makes CloneAll add one concurrent clone per interval up to the maximum (0 = disabled)
+func (rm *RepositoryManager) SetRampUp(interval time.Duration) {
+	rm.cloner.SetRampUp(interval)
+}
+
+// SetRateLimitProvider makes CloneAll scale concurrency with the remaining API rate limit (nil disables)
+func (rm *RepositoryManager) SetRateLimitProvider(provider RateLimitProvider) {
+	rm.cloner.SetRateLimitProvider(provider)
+}
+
+// SetOrgDirs sets per-organization output directories that override baseDir/org
+func (rm *RepositoryManager) SetOrgDirs(dirs map[string]string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.orgDirs = dirs
+}
+
+// SetDependencies makes CloneAll clone repositories in waves of dependency
+// order (see LoadDependencyOrder) instead of all at once
+func (rm *RepositoryManager) SetDependencies(deps map[string][]string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.dependencies = deps
+}
+
+// AddObserver registers fn to be called on every repository state change during
+// CloneAll. Observers run on the clone goroutines and must not block.
+func (rm *RepositoryManager) AddObserver(fn func(*Repository)) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.observers = append(rm.observers, fn)
+}
+
+// targetDir returns the clone target for a repository, honoring per-organization
+// overrides, then the path template, then the layout
+func (rm *RepositoryManager) targetDir(repo *Repository) (string, error) {
+	// Bare and mirror clones follow git's convention of a .git suffix
+	suffix := ""
+	if rm.defaults.Mode != CloneNormal {
+		suffix = ".git"
+	}
+	if dir, ok := rm.orgDirs[repo.Organization]; ok && dir != "" {
+		return filepath.Join(dir, repo.Name+suffix), nil
+	}
+	if rm.pathTemplate != nil {
+		path, err := renderPath(rm.pathTemplate, repo)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(rm.baseDir, path+suffix), nil
+	}
+	return filepath.Join(rm.baseDir, rm.layout.path(repo.Organization, repo.Name+suffix)), nil
+}
+
+// targetDirs computes the clone targets of repos, with an error for each
+// repository whose target cannot be rendered or is shared with another
+func (rm *RepositoryManager) targetDirs(repos []*Repository) (map[*Repository]string, map[*Repository]error) {
+	targets := make(map[*Repository]string, len(repos))
+	problems := make(map[*Repository]error)
+	for _, repo := range repos {
+		target, err := rm.targetDir(repo)
+		if err != nil {
+			problems[repo] = err
+			continue
+		}
+		targets[repo] = target
+	}
+	for repo, err := range targetCollisions(targets) {
+		problems[repo] = err
+	}
+	return targets, problems
+}
+
+// SetPathTemplate sets a template computing each clone target relative to the
+// base directory, replacing the layout (nil restores it)
+func (rm *RepositoryManager) SetPathTemplate(tmpl *template.Template) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.pathTemplate = tmpl
+}
+
+// SetSkipNewerThan makes CloneAll skip existing clones that were cloned or
+// fetched within window, without fetching them (0 disables the check)
+func (rm *RepositoryManager) SetSkipNewerThan(window time.Duration) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.skipNewerThan = window
+}
+
+// SetLayout sets how repositories are arranged under the base directory
+func (rm *RepositoryManager) SetLayout(layout Layout) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.layout = layout
+}
+
+// SetStrategyOverrides sets per-repository existing-repository strategies that
+// AddRepository applies instead of the strategy it is given; the first match wins
+func (rm *RepositoryManager) SetStrategyOverrides(overrides []StrategyOverride) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.strategyOverrides = overrides
+}
+
+// StrategyFor returns the existing-repository strategy AddRepository would use
+// for org/name when given strategy
+func (rm *RepositoryManager) StrategyFor(org, name string, strategy ExistingRepoStrategy) ExistingRepoStrategy {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	return strategyFor(rm.strategyOverrides, org, name, strategy)
+}
+
+// AddRepository adds a new repository to be managed. A matching strategy
+// override replaces strategy.
 func (rm *RepositoryManager) AddRepository(org, name, url, branch string, strategy ExistingRepoStrategy) *Repository {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
+	strategy = strategyFor(rm.strategyOverrides, org, name, strategy)
 	util.Debug(fmt.Sprintf("Adding repository to manager: %s/%s (branch: %s)", org, name, branch))
 	repo := &Repository{
 		Name:         name,
@@ -102,30 +303,85 @@ func (rm *RepositoryManager) GetRepositories() []*Repository {
 	return repos
 }
 
-// CloneAll starts cloning all pending repositories
+// CloneAll starts cloning all pending repositories. Updates are coalesced per
+// repository so a slow consumer never stalls the clone goroutines; receivers
+// should read the latest state with GetStatus.
 func (rm *RepositoryManager) CloneAll(ctx context.Context) <-chan *Repository {
-	updates := make(chan *Repository, len(rm.repositories))
+	coalescer, updates := newUpdateCoalescer()
 	util.Info(fmt.Sprintf("Starting clone of %d repositories", len(rm.repositories)))
+	rm.mu.Lock()
+	rm.startedAt, rm.finishedAt = time.Now(), time.Time{}
+	rm.mu.Unlock()
+
+	publish := func(repo *Repository) {
+		coalescer.notify(repo)
+		for _, observe := range rm.observers {
+			observe(repo)
+		}
+	}
 
 	go func() {
-		defer close(updates)
+		defer coalescer.close()
+		defer func() {
+			rm.mu.Lock()
+			rm.finishedAt = time.Now()
+			rm.mu.Unlock()
+		}()
+
+		// Repositories sharing a clone target would overwrite each other
+		var pending []*Repository
+		for _, repo := range rm.repositories {
+			if repo.Status == StatusPending {
+				pending = append(pending, repo)
+			}
+		}
+		targets, problems := rm.targetDirs(pending)
 
 		// Prepare clone options for each repository
 		cloneOpts := make([]CloneOptions, 0, len(rm.repositories))
+		queued := make([]*Repository, 0, len(rm.repositories))
 		for _, repo := range rm.repositories {
 			if repo.Status != StatusPending {
 				util.Debug(fmt.Sprintf("Skipping non-pending repository: %s/%s (status: %s)", repo.Organization, repo.Name, repo.Status))
 				continue
 			}
+			if err, ok := problems[repo]; ok {
+				util.Error(fmt.Sprintf("Not cloning %s/%s", repo.Organization, repo.Name), err)
+				repo.UpdateStatus(StatusFailed, err)
+				publish(repo)
+				continue
+			}
 
-			targetDir := filepath.Join(rm.baseDir, repo.Organization, repo.Name)
+			targetDir := targets[repo]
+			if rm.skipNewerThan > 0 && recentlySynced(targetDir, rm.defaults.Mode, rm.skipNewerThan, time.Now()) {
+				util.Info(fmt.Sprintf("Skipping %s/%s: synced within the last %v", repo.Organization, repo.Name, rm.skipNewerThan))
+				repo.mu.Lock()
+				repo.Progress = fmt.Sprintf("Skipping recently synced repository: %s", repo.URL)
+				repo.mu.Unlock()
+				repo.UpdateStatus(StatusSkipped, nil)
+				publish(repo)
+				continue
+			}
 			util.Debug(fmt.Sprintf("Preparing to clone %s/%s to %s", repo.Organization, repo.Name, targetDir))
 
-			opts := DefaultCloneOptions()
+			opts := rm.defaults
 			opts.URL = repo.URL
 			opts.TargetDir = targetDir
 			opts.Branch = repo.Branch
+			opts.Tag = repo.Tag
+			opts.DefaultBranch = repo.DefaultBranch
 			opts.ExistingRepo = repo.ExistingRepo
+			hook, err := renderHook(hookForLanguage(opts.LanguageHooks, repo.Language, opts.PostCloneHook), repo)
+			if err != nil {
+				util.Error(fmt.Sprintf("Not cloning %s/%s", repo.Organization, repo.Name), err)
+				repo.UpdateStatus(StatusFailed, err)
+				publish(repo)
+				continue
+			}
+			opts.PostCloneHook = hook
+			if opts.Jobs == 0 {
+				opts.Jobs = DefaultJobs(rm.cloner.maxConcurrent)
+			}
 			opts.ProgressFunc = func(status string) {
 				repo.mu.Lock()
 				repo.Progress = status
@@ -135,46 +391,71 @@ func (rm *RepositoryManager) CloneAll(ctx context.Context) <-chan *Repository {
 				} else if strings.Contains(status, "Skipping") {
 					repo.Status = StatusSkipped
 					util.Debug(fmt.Sprintf("Repository %s/%s is skipped", repo.Organization, repo.Name))
+				} else if strings.HasPrefix(status, retryPrefix) {
+					repo.Status = StatusRetrying
+					repo.Retries++
+					util.Debug(fmt.Sprintf("Repository %s/%s is retrying (retry %d)", repo.Organization, repo.Name, repo.Retries))
 				} else {
 					repo.Status = StatusCloning
 					util.Debug(fmt.Sprintf("Repository %s/%s is cloning", repo.Organization, repo.Name))
 				}
 				repo.mu.Unlock()
-				updates <- repo
+				publish(repo)
+			}
+			opts.PercentFunc = func(phase string, percent float64) {
+				repo.mu.Lock()
+				repo.Phase = phase
+				repo.Percent = percent
+				repo.mu.Unlock()
+				publish(repo)
 			}
 			cloneOpts = append(cloneOpts, opts)
+			queued = append(queued, repo)
 		}
 
-		// Start cloning repositories
-		results := rm.cloner.CloneRepositories(ctx, cloneOpts)
-		for result := range results {
-			// Find corresponding repository
-			var repo *Repository
-			for _, r := range rm.repositories {
-				if r.URL == result.RepoURL {
-					repo = r
-					break
+		if err := checkDiskSpace(rm.baseDir, queued, rm.diskCheck, rm.space); err != nil {
+			util.Error("Not starting clones", err)
+			for _, repo := range queued {
+				repo.UpdateStatus(StatusFailed, err)
+				publish(repo)
+			}
+			return
+		}
+
+		waves, err := rm.cloneWaves(queued)
+		if err != nil {
+			util.Error("Failed to order repositories by dependency", err)
+			for _, repo := range queued {
+				repo.UpdateStatus(StatusFailed, err)
+				publish(repo)
+			}
+			return
+		}
+
+		failed := make(map[string]bool)
+		for _, wave := range waves {
+			var waveOpts []CloneOptions
+			for _, i := range wave {
+				if dep := rm.failedDependency(queued[i], failed); dep != "" {
+					queued[i].UpdateStatus(StatusFailed, fmt.Errorf("not cloned because dependency %s failed", dep))
+					failed[fullName(queued[i])] = true
+					publish(queued[i])
+					continue
 				}
+				waveOpts = append(waveOpts, cloneOpts[i])
 			}
-			if repo == nil {
-				util.Error("Failed to find repository for result", fmt.Errorf("repository not found: %s", result.RepoURL))
+			if len(waveOpts) == 0 {
 				continue
 			}
 
-			// Update repository status
-			repo.mu.Lock()
-			if result.Success {
-				if repo.Status != StatusSkipped {
-					repo.Status = StatusSuccess
-					util.Info(fmt.Sprintf("Repository %s/%s cloned successfully", repo.Organization, repo.Name))
+			for result := range rm.cloner.CloneRepositories(ctx, waveOpts) {
+				if repo := rm.recordResult(result); repo != nil {
+					if !result.Success {
+						failed[fullName(repo)] = true
+					}
+					publish(repo)
 				}
-			} else {
-				repo.Status = StatusFailed
-				repo.Error = result.Error
-				util.Error(fmt.Sprintf("Failed to clone repository %s/%s", repo.Organization, repo.Name), result.Error)
 			}
-			repo.mu.Unlock()
-			updates <- repo
 		}
 
 		util.Info("Completed processing all repositories")
@@ -183,6 +464,104 @@ func (rm *RepositoryManager) CloneAll(ctx context.Context) <-chan *Repository {
 	return updates
 }
 
+// Elapsed returns the wall-clock time of the latest CloneAll batch, so far if
+// it is still running, or zero before the first batch
+func (rm *RepositoryManager) Elapsed() time.Duration {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	if rm.startedAt.IsZero() {
+		return 0
+	}
+	if rm.finishedAt.IsZero() {
+		return time.Since(rm.startedAt)
+	}
+	return rm.finishedAt.Sub(rm.startedAt)
+}
+
+// fullName returns the lowercased "org/name" of a repository used for dependency lookups
+func fullName(repo *Repository) string {
+	return strings.ToLower(repo.Organization + "/" + repo.Name)
+}
+
+// cloneWaves splits the queued repositories (by index) into waves following
+// the configured dependencies; without dependencies everything is one wave
+func (rm *RepositoryManager) cloneWaves(queued []*Repository) ([][]int, error) {
+	index := make(map[string]int, len(queued))
+	names := make([]string, len(queued))
+	for i, repo := range queued {
+		names[i] = fullName(repo)
+		index[names[i]] = i
+	}
+	if len(rm.dependencies) == 0 {
+		all := make([]int, len(queued))
+		for i := range all {
+			all[i] = i
+		}
+		return [][]int{all}, nil
+	}
+
+	named, err := cloneWaves(names, rm.dependencies)
+	if err != nil {
+		return nil, err
+	}
+	waves := make([][]int, len(named))
+	for w, wave := range named {
+		for _, name := range wave {
+			waves[w] = append(waves[w], index[name])
+		}
+	}
+	return waves, nil
+}
+
+// failedDependency returns the first dependency of repo that failed in this run, or ""
+func (rm *RepositoryManager) failedDependency(repo *Repository, failed map[string]bool) string {
+	for _, dep := range rm.dependencies[fullName(repo)] {
+		if failed[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// recordResult applies a clone result to its repository, returning nil when
+// no repository matches the result
+func (rm *RepositoryManager) recordResult(result CloneResult) *Repository {
+	// Find corresponding repository
+	var repo *Repository
+	for _, r := range rm.repositories {
+		if r.URL == result.RepoURL {
+			repo = r
+			break
+		}
+	}
+	if repo == nil {
+		util.Error("Failed to find repository for result", fmt.Errorf("repository not found: %s", result.RepoURL))
+		return nil
+	}
+
+	// Update repository status
+	repo.mu.Lock()
+	repo.Duration = result.Duration
+	repo.ClonedBranch = result.ClonedBranch
+	repo.HookError = result.HookError
+	repo.HookOutput = result.HookOutput
+	if result.Success {
+		repo.Empty = result.Empty
+		repo.Slow = result.Slow
+		if repo.Status != StatusSkipped {
+			repo.Status = StatusSuccess
+			util.Info(fmt.Sprintf("Repository %s/%s cloned successfully", repo.Organization, repo.Name))
+		}
+	} else {
+		repo.Status = StatusFailed
+		repo.Error = result.Error
+		util.Error(fmt.Sprintf("Failed to clone repository %s/%s", repo.Organization, repo.Name), result.Error)
+	}
+	repo.mu.Unlock()
+	return repo
+}
+
 // GetRepository returns a repository by its name and organization
 func (rm *RepositoryManager) GetRepository(org, name string) *Repository {
 	rm.mu.RLock()
@@ -197,6 +576,30 @@ func (rm *RepositoryManager) GetRepository(org, name string) *Repository {
 	return nil
 }
 
+// ResetFailed moves all failed repositories back to pending so the next
+// CloneAll call retries them. It returns the number of repositories reset.
+func (rm *RepositoryManager) ResetFailed() int {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	count := 0
+	for _, repo := range rm.repositories {
+		repo.mu.Lock()
+		if repo.Status == StatusFailed {
+			repo.Status = StatusPending
+			repo.Error = nil
+			repo.Progress = ""
+			repo.Phase = ""
+			repo.Percent = 0
+			repo.Retries = 0
+			count++
+		}
+		repo.mu.Unlock()
+	}
+	util.Info(fmt.Sprintf("Reset %d failed repositories for retry", count))
+	return count
+}
+
 // UpdateStatus updates the status of a repository
 func (r *Repository) UpdateStatus(status RepositoryStatus, err error) {
 	r.mu.Lock()
@@ -220,6 +623,54 @@ func (r *Repository) GetStatus() (RepositoryStatus, error, string) {
 	return r.Status, r.Error, r.Progress
 }
 
+// GetPercent returns the current git progress phase and its completion (0-100)
+func (r *Repository) GetPercent() (string, float64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.Phase, r.Percent
+}
+
+// IsEmpty reports whether the repository was cloned but has no commits
+func (r *Repository) IsEmpty() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.Empty
+}
+
+// SetLanguage sets the repository's primary language, used to pick its post-clone hook
+func (r *Repository) SetLanguage(language string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Language = language
+}
+
+// SetDefaultBranch records the repository's default branch
+func (r *Repository) SetDefaultBranch(branch string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.DefaultBranch = branch
+}
+
+// SetTag sets the tag checked out instead of a branch
+func (r *Repository) SetTag(tag string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Tag = tag
+}
+
+// SetSize sets the repository size reported by GitHub, in KB
+func (r *Repository) SetSize(kb int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Size = kb
+}
+
 // SetExistingRepoStrategy sets the strategy for handling existing repositories
 func (r *Repository) SetExistingRepoStrategy(strategy ExistingRepoStrategy) {
 	r.mu.Lock()
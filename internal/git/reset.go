@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// staleLockAge is how long index.lock must have been left untouched before it
+// is considered abandoned; a younger lock may belong to a running git process
+const staleLockAge = 10 * time.Minute
+
+// resetWithRecovery hard-resets the repository in dir to its remote branch. A
+// missing remote ref is fetched explicitly first, and a failed reset caused by
+// an index.lock older than staleLockAge is retried once after removing the lock.
+func resetWithRecovery(ctx context.Context, dir string, opts CloneOptions) error {
+	if err := ensureRemoteRef(ctx, dir, opts); err != nil {
+		return err
+	}
+
+	output, err := resetHard(ctx, dir, opts)
+	if err == nil {
+		return nil
+	}
+
+	lock := filepath.Join(dir, ".git", "index.lock")
+	info, statErr := os.Stat(lock)
+	if statErr != nil || time.Since(info.ModTime()) < staleLockAge {
+		util.Error("Failed to reset branch", fmt.Errorf("%w: %s", err, output))
+		return fmt.Errorf("failed to reset branch: %w\nOutput: %s", err, output)
+	}
+
+	util.Warn(fmt.Sprintf("Removing stale index.lock in %s and retrying reset", opts.TargetDir))
+	if rmErr := os.Remove(lock); rmErr != nil {
+		return fmt.Errorf("failed to remove stale index.lock: %w", rmErr)
+	}
+	if output, err := resetHard(ctx, dir, opts); err != nil {
+		util.Error("Failed to reset branch after removing index.lock", fmt.Errorf("%w: %s", err, output))
+		return fmt.Errorf("failed to reset branch: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// resetHard runs `git reset --hard` to the configured tag or the remote ref of the branch
+func resetHard(ctx context.Context, dir string, opts CloneOptions) ([]byte, error) {
+	resetCtx, cancel := context.WithTimeout(ctx, opts.ConnTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(resetCtx, "git", "reset", "--hard", targetRef(opts))
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// ensureRemoteRef fetches the remote-tracking ref explicitly when it is missing,
+// e.g. for a branch outside the configured refspec or an unset origin/HEAD
+func ensureRemoteRef(ctx context.Context, dir string, opts CloneOptions) error {
+	ref := targetRef(opts)
+	verify := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", ref)
+	verify.Dir = dir
+	if verify.Run() == nil {
+		return nil
+	}
+
+	util.Info(fmt.Sprintf("Remote ref %s missing in %s, fetching it explicitly", ref, opts.TargetDir))
+	fetchCtx, cancel := context.WithTimeout(ctx, updateTimeout(opts))
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if opts.Tag != "" {
+		refspec := fmt.Sprintf("+refs/tags/%s:refs/tags/%s", opts.Tag, opts.Tag)
+		cmd = gitCommand(fetchCtx, opts, append(tokenRewriteArgs(opts), "fetch", "origin", refspec)...)
+	} else if opts.Branch == "" {
+		cmd = gitCommand(fetchCtx, opts, append(tokenRewriteArgs(opts), "remote", "set-head", "origin", "--auto")...)
+	} else {
+		refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", opts.Branch, opts.Branch)
+		cmd = gitCommand(fetchCtx, opts, append(tokenRewriteArgs(opts), "fetch", "origin", refspec)...)
+	}
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w\nOutput: %s", ref, err, redactToken(string(output), opts.Token))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResetWithRecovery(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch string
+		// breakRepo damages the clone in dir before the reset
+		breakRepo func(t *testing.T, dir string)
+		wantErr   string
+	}{
+		{"clean", "main", func(t *testing.T, dir string) {}, ""},
+		{"stale index lock", "main", func(t *testing.T, dir string) {
+			lock := filepath.Join(dir, ".git", "index.lock")
+			writeFile(t, lock, "")
+			old := time.Now().Add(-2 * staleLockAge)
+			if err := os.Chtimes(lock, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}, ""},
+		{"missing branch ref", "develop", func(t *testing.T, dir string) {
+			runGit(t, dir, "update-ref", "-d", "refs/remotes/origin/develop")
+		}, ""},
+		{"missing origin HEAD", "", func(t *testing.T, dir string) {
+			runGit(t, dir, "remote", "set-head", "origin", "-d")
+		}, ""},
+		{"branch missing on the remote", "gone", func(t *testing.T, dir string) {}, "failed to fetch origin/gone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newFixtureRemote(t, "develop")
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			// The working tree is behind and dirty, so only a real reset leaves it clean
+			pushCommit(t, remote, "CHANGES.md", "changed\n")
+			runGit(t, opts.TargetDir, "fetch", "origin")
+			writeFile(t, filepath.Join(opts.TargetDir, "README.md"), "local edit\n")
+			tt.breakRepo(t, opts.TargetDir)
+
+			opts.Branch = tt.branch
+			err := resetWithRecovery(context.Background(), opts.TargetDir, opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resetWithRecovery() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resetWithRecovery() = %v", err)
+			}
+
+			if got := readFile(t, filepath.Join(opts.TargetDir, "README.md")); got != "fixture\n" {
+				t.Errorf("README.md = %q, want the remote content", got)
+			}
+			if head, want := runGit(t, opts.TargetDir, "rev-parse", "HEAD"), runGit(t, opts.TargetDir, "rev-parse", targetRef(opts)); head != want {
+				t.Errorf("HEAD = %s, want %s at %s", head, want, targetRef(opts))
+			}
+			if _, err := os.Stat(filepath.Join(opts.TargetDir, ".git", "index.lock")); !os.IsNotExist(err) {
+				t.Errorf("index.lock left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestResetLeavesFreshIndexLock(t *testing.T) {
+	remote := newFixtureRemote(t, "develop")
+	opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+	// A lock this young may belong to a git process still running in the clone
+	lock := filepath.Join(opts.TargetDir, ".git", "index.lock")
+	writeFile(t, lock, "")
+
+	opts.Branch = "main"
+	err := resetWithRecovery(context.Background(), opts.TargetDir, opts)
+	if err == nil || !strings.Contains(err.Error(), "failed to reset branch") {
+		t.Fatalf("resetWithRecovery() = %v, want a reset failure", err)
+	}
+	if _, err := os.Stat(lock); err != nil {
+		t.Errorf("fresh index.lock was removed: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCancelDuringBackoffReturnsPromptly(t *testing.T) {
+	// Full jitter makes every backoff its maximum, at least a second
+	saved := backoffRand
+	backoffRand = func() float64 { return 1 }
+	t.Cleanup(func() { backoffRand = saved })
+
+	tests := []struct {
+		name string
+		// cancelAt is the retry whose backoff is interrupted
+		cancelAt   int
+		maxRetries int
+	}{
+		{"first retry", 1, 1},
+		{"second retry", 2, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			opts := testCloneOptions("file://"+filepath.Join(t.TempDir(), "missing.git"), filepath.Join(t.TempDir(), "clone"))
+			opts.MaxRetries = tt.maxRetries
+			opts.MaxBackoff = time.Minute
+			retries, attempts := 0, 0
+			var cancelled time.Time
+			opts.ProgressFunc = func(status string) {
+				if strings.HasPrefix(status, "Clone attempt") {
+					attempts++
+				}
+				if strings.HasPrefix(status, retryPrefix) {
+					if retries++; retries == tt.cancelAt {
+						cancelled = time.Now()
+						cancel()
+					}
+				}
+			}
+
+			err := NewConcurrentCloner(1).CloneRepository(ctx, opts)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("CloneRepository() error = %v, want context.Canceled", err)
+			}
+			if elapsed := time.Since(cancelled); elapsed > 500*time.Millisecond {
+				t.Errorf("returned %v after cancellation, want it to stop waiting for the backoff", elapsed)
+			}
+			if attempts != tt.cancelAt {
+				t.Errorf("made %d attempts, want %d before the cancelled retry", attempts, tt.cancelAt)
+			}
+		})
+	}
+}
+
+func TestRetryingStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+	}{
+		{"no retries", 0},
+		{"two retries", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewRepositoryManager(t.TempDir(), 1)
+			opts := testCloneOptions("", "")
+			opts.MaxRetries, opts.MaxBackoff = tt.retries, 10*time.Millisecond
+			manager.SetCloneDefaults(opts)
+			repo := manager.AddRepository("acme", "app", "file://"+filepath.Join(t.TempDir(), "missing.git"), "", SkipExisting)
+
+			// Observers run with the repository unlocked, so its fields can be read
+			var transitions []RepositoryStatus
+			manager.AddObserver(func(r *Repository) {
+				status, _, _ := r.GetStatus()
+				if len(transitions) == 0 || transitions[len(transitions)-1] != status {
+					transitions = append(transitions, status)
+				}
+			})
+			for range manager.CloneAll(t.Context()) {
+			}
+
+			retrying := 0
+			for _, status := range transitions {
+				if status == StatusRetrying {
+					retrying++
+				}
+			}
+			if retrying != tt.retries {
+				t.Errorf("transitions = %v, want %d into retrying", transitions, tt.retries)
+			}
+			if status, _, _ := repo.GetStatus(); status != StatusFailed {
+				t.Errorf("final status = %v, want failed", status)
+			}
+			if got := repo.Snapshot().Retries; got != tt.retries {
+				t.Errorf("Retries = %d, want %d", got, tt.retries)
+			}
+
+			// A manual retry starts counting again
+			manager.ResetFailed()
+			if got := repo.Snapshot().Retries; got != 0 {
+				t.Errorf("Retries after ResetFailed = %d, want 0", got)
+			}
+		})
+	}
+}
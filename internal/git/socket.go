@@ -0,0 +1,204 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// RepositorySnapshot is a point-in-time, serializable view of a repository's state
+type RepositorySnapshot struct {
+	Organization string  `json:"organization"`
+	Name         string  `json:"name"`
+	URL          string  `json:"url"`
+	Branch       string  `json:"branch,omitempty"`
+	Tag          string  `json:"tag,omitempty"`
+	ClonedBranch string  `json:"cloned_branch,omitempty"`
+	Status       string  `json:"status"`
+	Progress     string  `json:"progress,omitempty"`
+	Phase        string  `json:"phase,omitempty"`
+	Percent      float64 `json:"percent,omitempty"`
+	Error        string  `json:"error,omitempty"`
+	Empty        bool    `json:"empty,omitempty"`
+	Slow         bool    `json:"slow,omitempty"`
+	Retries      int     `json:"retries,omitempty"`
+	HookError    string  `json:"hook_error,omitempty"`
+	// DurationSeconds is how long the clone or update took, once finished
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	// DefaultBranch is the repository's default branch on GitHub
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// Path and Commit locate a successful clone; they are only set in session manifests
+	Path   string `json:"path,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// Snapshot returns the current state of the repository
+func (r *Repository) Snapshot() RepositorySnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := RepositorySnapshot{
+		Organization: r.Organization,
+		Name:         r.Name,
+		URL:          r.URL,
+		Branch:       r.Branch,
+		Tag:          r.Tag,
+		ClonedBranch: r.ClonedBranch,
+		Status:       r.Status.String(),
+		Progress:     r.Progress,
+		Phase:        r.Phase,
+		Percent:      r.Percent,
+		Empty:        r.Empty,
+		Slow:         r.Slow,
+		Retries:      r.Retries,
+
+		DurationSeconds: r.Duration.Seconds(),
+		DefaultBranch:   r.DefaultBranch,
+	}
+	if r.Error != nil {
+		snapshot.Error = r.Error.Error()
+	}
+	if r.HookError != nil {
+		snapshot.HookError = r.HookError.Error()
+	}
+	return snapshot
+}
+
+// progressClientBuffer is the number of events queued per client before events are dropped
+const progressClientBuffer = 256
+
+// progressDrainTimeout bounds how long Close waits for a client to receive its queued events
+const progressDrainTimeout = 2 * time.Second
+
+// ProgressServer streams repository snapshots as newline-delimited JSON to
+// every client connected to a Unix domain socket
+type ProgressServer struct {
+	listener net.Listener
+	path     string
+	mu       sync.Mutex
+	clients  map[net.Conn]chan RepositorySnapshot
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewProgressServer listens on the Unix socket at path, replacing a stale
+// socket file. Any other file at path is left alone and reported as an error.
+func NewProgressServer(path string) (*ProgressServer, error) {
+	info, err := os.Lstat(path)
+	switch {
+	case err == nil:
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("progress socket path %s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove existing socket: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to inspect progress socket path: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on progress socket: %w", err)
+	}
+
+	s := &ProgressServer{
+		listener: listener,
+		path:     path,
+		clients:  make(map[net.Conn]chan RepositorySnapshot),
+	}
+	go s.accept()
+	util.Info(fmt.Sprintf("Streaming progress events on %s", path))
+	return s, nil
+}
+
+// accept registers connecting clients until the listener is closed
+func (s *ProgressServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		events := make(chan RepositorySnapshot, progressClientBuffer)
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.clients[conn] = events
+		s.wg.Add(1)
+		s.mu.Unlock()
+		util.Debug("Progress socket client connected")
+
+		go s.serve(conn, events)
+	}
+}
+
+// serve writes events to a client until it disconnects or the server closes
+// and the queued events have been written
+func (s *ProgressServer) serve(conn net.Conn, events <-chan RepositorySnapshot) {
+	defer s.wg.Done()
+	defer s.drop(conn)
+
+	enc := json.NewEncoder(conn)
+	for event := range events {
+		if err := enc.Encode(event); err != nil {
+			util.Debug(fmt.Sprintf("Progress socket client disconnected: %v", err))
+			return
+		}
+	}
+}
+
+// drop unregisters and closes a client connection
+func (s *ProgressServer) drop(conn net.Conn) {
+	s.mu.Lock()
+	if events, ok := s.clients[conn]; ok {
+		delete(s.clients, conn)
+		close(events)
+	}
+	s.mu.Unlock()
+	conn.Close()
+}
+
+// Publish sends the repository's current state to all clients. It never
+// blocks; events are dropped for clients that are not keeping up.
+func (s *ProgressServer) Publish(repo *Repository) {
+	snapshot := repo.Snapshot()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, events := range s.clients {
+		select {
+		case events <- snapshot:
+		default:
+		}
+	}
+}
+
+// Close stops accepting clients, waits for each client to receive the events
+// already queued for it, disconnects all clients and removes the socket file
+func (s *ProgressServer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	s.closed = true
+	deadline := time.Now().Add(progressDrainTimeout)
+	for conn, events := range s.clients {
+		delete(s.clients, conn)
+		close(events)
+		// A client that stops reading cannot hold up shutdown
+		conn.SetWriteDeadline(deadline)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	os.Remove(s.path)
+	return err
+}
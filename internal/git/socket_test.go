@@ -0,0 +1,203 @@
+package git
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForClients waits until the server has registered n clients
+func waitForClients(t *testing.T, s *ProgressServer, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		connected := len(s.clients)
+		s.mu.Unlock()
+		if connected == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("%d clients did not connect", n)
+}
+
+// writeStaleSocket leaves a socket file at path with nothing listening on it
+func writeStaleSocket(t *testing.T, path string) {
+	t.Helper()
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.SetUnlinkOnClose(false)
+	listener.Close()
+}
+
+func TestProgressServerStreamsSnapshots(t *testing.T) {
+	tests := []struct {
+		name    string
+		clients int
+	}{
+		{"one client", 1},
+		{"several clients", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "progress.sock")
+			// A stale socket file from an earlier run is replaced
+			writeStaleSocket(t, path)
+			server, err := NewProgressServer(path)
+			if err != nil {
+				t.Fatalf("NewProgressServer() error = %v", err)
+			}
+
+			readers := make([]*bufio.Reader, tt.clients)
+			for i := range readers {
+				conn, err := net.Dial("unix", path)
+				if err != nil {
+					t.Fatalf("failed to connect: %v", err)
+				}
+				t.Cleanup(func() { conn.Close() })
+				conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+				readers[i] = bufio.NewReader(conn)
+			}
+			waitForClients(t, server, tt.clients)
+
+			app := &Repository{Organization: "acme", Name: "app", URL: "https://github.com/acme/app.git", Status: StatusCloning, Phase: "Receiving objects", Percent: 42}
+			lib := &Repository{Organization: "acme", Name: "lib", URL: "https://github.com/acme/lib.git", Status: StatusFailed, Error: errors.New("authentication required")}
+			server.Publish(app)
+			server.Publish(lib)
+
+			want := []RepositorySnapshot{app.Snapshot(), lib.Snapshot()}
+			for i, reader := range readers {
+				for _, expected := range want {
+					line, err := reader.ReadBytes('\n')
+					if err != nil {
+						t.Fatalf("client %d: failed to read event: %v", i, err)
+					}
+					var got RepositorySnapshot
+					if err := json.Unmarshal(line, &got); err != nil {
+						t.Fatalf("client %d: event is not JSON: %v\n%s", i, err, line)
+					}
+					if got != expected {
+						t.Errorf("client %d: event = %+v, want %+v", i, got, expected)
+					}
+				}
+			}
+
+			if err := server.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			for i, reader := range readers {
+				if _, err := reader.ReadBytes('\n'); !errors.Is(err, io.EOF) {
+					t.Errorf("client %d: read after Close = %v, want EOF", i, err)
+				}
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("socket file still exists after Close: %v", err)
+			}
+		})
+	}
+}
+
+func TestProgressServerPublishNeverBlocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.sock")
+	server, err := NewProgressServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	// The client never reads, so its buffer and the socket fill up
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	waitForClients(t, server, 1)
+
+	repo := &Repository{Organization: "acme", Name: "app", Status: StatusCloning, Progress: strings.Repeat("x", 4096)}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10*progressClientBuffer; i++ {
+			server.Publish(repo)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Publish blocked on a client that is not reading")
+	}
+}
+
+func TestProgressServerRejectsNonSocketPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.sock")
+	if err := os.WriteFile(path, []byte("keep me"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := NewProgressServer(path)
+	if err == nil {
+		server.Close()
+		t.Fatal("NewProgressServer() succeeded on a regular file")
+	}
+	if !strings.Contains(err.Error(), "is not a socket") {
+		t.Errorf("NewProgressServer() error = %v, want it to say the path is not a socket", err)
+	}
+	if got := readFile(t, path); got != "keep me" {
+		t.Errorf("file content = %q, want it left intact", got)
+	}
+}
+
+func TestProgressServerCloseDrainsQueuedSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.sock")
+	server, err := NewProgressServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	waitForClients(t, server, 1)
+
+	// Queue more events than the socket buffers before the client reads any
+	const published = 200
+	repo := &Repository{Organization: "acme", Name: "app", Status: StatusCloning, Progress: strings.Repeat("x", 4096)}
+	for i := 0; i < published; i++ {
+		server.Publish(repo)
+	}
+	closed := make(chan error, 1)
+	go func() { closed <- server.Close() }()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(conn)
+	received := 0
+	for {
+		if _, err := reader.ReadBytes('\n'); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("read after %d events: %v", received, err)
+			}
+			break
+		}
+		received++
+	}
+	if received != published {
+		t.Errorf("received %d events, want %d", received, published)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// StrategyOverride applies Strategy to repositories whose "org/name" matches
+// Pattern, a case-insensitive path.Match glob such as "acme/legacy-*"
+type StrategyOverride struct {
+	Pattern  string
+	Strategy ExistingRepoStrategy
+}
+
+// ParseStrategyOverride parses a pattern and strategy name, rejecting malformed patterns
+func ParseStrategyOverride(pattern, strategy string) (StrategyOverride, error) {
+	parsed, err := ParseExistingRepoStrategy(strategy)
+	if err != nil {
+		return StrategyOverride{}, fmt.Errorf("invalid strategy for %q: %w", pattern, err)
+	}
+	if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+		return StrategyOverride{}, fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+	}
+	return StrategyOverride{Pattern: pattern, Strategy: parsed}, nil
+}
+
+// strategyFor returns the strategy of the first override matching org/name, or fallback
+func strategyFor(overrides []StrategyOverride, org, name string, fallback ExistingRepoStrategy) ExistingRepoStrategy {
+	fullName := strings.ToLower(org + "/" + name)
+	for _, override := range overrides {
+		if ok, _ := path.Match(strings.ToLower(override.Pattern), fullName); ok {
+			return override.Strategy
+		}
+	}
+	return fallback
+}
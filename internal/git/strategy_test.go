@@ -0,0 +1,77 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStrategyOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		strategy string
+		want     ExistingRepoStrategy
+		wantErr  string
+	}{
+		{"exact repository", "acme/api", "overwrite", OverwriteExisting, ""},
+		{"glob", "acme/legacy-*", "fetch-only", FetchOnly, ""},
+		{"empty strategy skips", "acme/*", "", SkipExisting, ""},
+		{"unknown strategy", "acme/api", "merge", SkipExisting, "invalid strategy"},
+		{"malformed pattern", "acme/[api", "skip", SkipExisting, "invalid repository pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStrategyOverride(tt.pattern, tt.strategy)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseStrategyOverride() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStrategyOverride() error = %v", err)
+			}
+			if got.Pattern != tt.pattern || got.Strategy != tt.want {
+				t.Errorf("ParseStrategyOverride() = %+v, want %s for %s", got, tt.want, tt.pattern)
+			}
+		})
+	}
+}
+
+func TestStrategyPrecedence(t *testing.T) {
+	overrides := []StrategyOverride{
+		{Pattern: "acme/api", Strategy: FetchOnly},
+		{Pattern: "acme/legacy-*", Strategy: OverwriteExisting},
+		{Pattern: "*/legacy-billing", Strategy: Sync},
+	}
+	tests := []struct {
+		org, name string
+		want      ExistingRepoStrategy
+	}{
+		{"acme", "api", FetchOnly},
+		{"ACME", "Api", FetchOnly},
+		{"acme", "legacy-billing", OverwriteExisting}, // the first match wins
+		{"other", "legacy-billing", Sync},
+		{"acme", "web", SkipExisting},
+		{"other", "api", SkipExisting},
+	}
+	manager := NewRepositoryManager(t.TempDir(), 1)
+	manager.SetStrategyOverrides(overrides)
+	for _, tt := range tests {
+		t.Run(tt.org+"/"+tt.name, func(t *testing.T) {
+			if got := strategyFor(overrides, tt.org, tt.name, SkipExisting); got != tt.want {
+				t.Errorf("strategyFor() = %s, want %s", got, tt.want)
+			}
+			repo := manager.AddRepository(tt.org, tt.name, "unused", "", SkipExisting)
+			if repo.ExistingRepo != tt.want {
+				t.Errorf("AddRepository() strategy = %s, want %s", repo.ExistingRepo, tt.want)
+			}
+		})
+	}
+
+	// Without overrides the strategy given to AddRepository is kept
+	manager.SetStrategyOverrides(nil)
+	if repo := manager.AddRepository("acme", "api", "unused", "", OverwriteExisting); repo.ExistingRepo != OverwriteExisting {
+		t.Errorf("AddRepository() strategy = %s without overrides, want overwrite", repo.ExistingRepo)
+	}
+}
@@ -0,0 +1,102 @@
+package git
+
+import "fmt"
+
+// CloneFailure describes a repository that could not be cloned or updated
+type CloneFailure struct {
+	Repository string `json:"repository" yaml:"repository"`
+	Error      string `json:"error" yaml:"error"`
+}
+
+// RepositoryResult is the final outcome of a single repository in a clone run
+type RepositoryResult struct {
+	Organization    string  `json:"organization" yaml:"organization"`
+	Name            string  `json:"name" yaml:"name"`
+	Status          string  `json:"status" yaml:"status"`
+	Branch          string  `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Error           string  `json:"error,omitempty" yaml:"error,omitempty"`
+	HookError       string  `json:"hook_error,omitempty" yaml:"hook_error,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds" yaml:"duration_seconds"`
+}
+
+// CloneSummary aggregates the outcome of a clone run
+type CloneSummary struct {
+	Total     int            `json:"total" yaml:"total"`
+	Succeeded int            `json:"succeeded" yaml:"succeeded"`
+	Skipped   int            `json:"skipped" yaml:"skipped"`
+	Failed    int            `json:"failed" yaml:"failed"`
+	Empty     int            `json:"empty" yaml:"empty"`
+	Slow      []string       `json:"slow,omitempty" yaml:"slow,omitempty"`
+	Failures  []CloneFailure `json:"failures,omitempty" yaml:"failures,omitempty"`
+	// ElapsedSeconds is the wall-clock time of the whole batch
+	ElapsedSeconds float64 `json:"elapsed_seconds" yaml:"elapsed_seconds"`
+
+	Repositories []RepositoryResult `json:"repositories" yaml:"repositories"`
+}
+
+// Summary aggregates the current state of all managed repositories
+func (rm *RepositoryManager) Summary() *CloneSummary {
+	summary := &CloneSummary{ElapsedSeconds: rm.Elapsed().Seconds()}
+	for _, repo := range rm.GetRepositories() {
+		status, _, _ := repo.GetStatus()
+		snapshot := repo.Snapshot()
+		summary.Total++
+		summary.Repositories = append(summary.Repositories, RepositoryResult{
+			Organization:    snapshot.Organization,
+			Name:            snapshot.Name,
+			Status:          snapshot.Status,
+			Branch:          snapshot.ClonedBranch,
+			Error:           snapshot.Error,
+			HookError:       snapshot.HookError,
+			DurationSeconds: snapshot.DurationSeconds,
+		})
+		switch status {
+		case StatusSuccess:
+			summary.Succeeded++
+			if snapshot.Empty {
+				summary.Empty++
+			}
+			if snapshot.Slow {
+				summary.Slow = append(summary.Slow, snapshot.Organization+"/"+snapshot.Name)
+			}
+		case StatusSkipped:
+			summary.Skipped++
+		case StatusFailed:
+			summary.Failed++
+			summary.Failures = append(summary.Failures, CloneFailure{
+				Repository: snapshot.Organization + "/" + snapshot.Name,
+				Error:      snapshot.Error,
+			})
+		}
+	}
+	return summary
+}
+
+// Reconciliation compares the outcome of a run against the number of repositories expected
+type Reconciliation struct {
+	Expected  int `json:"expected" yaml:"expected"`
+	Succeeded int `json:"succeeded" yaml:"succeeded"`
+	Skipped   int `json:"skipped" yaml:"skipped"`
+	Failed    int `json:"failed" yaml:"failed"`
+	Missing   int `json:"missing" yaml:"missing"` // expected but never reached a final status
+}
+
+// Reconcile checks that every expected repository was cloned, updated or
+// deliberately skipped. It returns an error describing any discrepancy.
+func Reconcile(expected int, summary *CloneSummary) (*Reconciliation, error) {
+	r := &Reconciliation{
+		Expected:  expected,
+		Succeeded: summary.Succeeded,
+		Skipped:   summary.Skipped,
+		Failed:    summary.Failed,
+	}
+	if accounted := r.Succeeded + r.Skipped + r.Failed; accounted < expected {
+		r.Missing = expected - accounted
+	}
+
+	if r.Succeeded+r.Skipped == expected {
+		return r, nil
+	}
+	return r, fmt.Errorf("repository count mismatch: expected %d, got %d succeeded + %d skipped (%d failed, %d missing)",
+		r.Expected, r.Succeeded, r.Skipped, r.Failed, r.Missing)
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name                       string
+		expected                   int
+		succeeded, skipped, failed int
+		wantMissing                int
+		wantErr                    bool
+	}{
+		{"all succeeded", 10, 10, 0, 0, 0, false},
+		{"succeeded and skipped", 10, 7, 3, 0, 0, false},
+		{"nothing expected", 0, 0, 0, 0, 0, false},
+		{"failures", 10, 8, 0, 2, 0, true},
+		{"silently dropped", 10, 6, 2, 0, 2, true},
+		{"failed and dropped", 10, 5, 1, 1, 3, true},
+		{"more than expected", 5, 6, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &CloneSummary{
+				Total:     tt.succeeded + tt.skipped + tt.failed,
+				Succeeded: tt.succeeded,
+				Skipped:   tt.skipped,
+				Failed:    tt.failed,
+			}
+			r, err := Reconcile(tt.expected, summary)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if r.Expected != tt.expected || r.Succeeded != tt.succeeded || r.Skipped != tt.skipped || r.Failed != tt.failed {
+				t.Errorf("Reconcile() = %+v, want the summary counts against %d expected", r, tt.expected)
+			}
+			if r.Missing != tt.wantMissing {
+				t.Errorf("Missing = %d, want %d", r.Missing, tt.wantMissing)
+			}
+			if err != nil && !strings.Contains(err.Error(), "repository count mismatch") {
+				t.Errorf("error %q does not describe the mismatch", err)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	manager := NewRepositoryManager(t.TempDir(), 1)
+	statuses := []struct {
+		name   string
+		status RepositoryStatus
+		err    error
+	}{
+		{"app", StatusSuccess, nil},
+		{"web", StatusFailed, errors.New("repository not found")},
+		{"lib", StatusSkipped, nil},
+		{"cli", StatusSuccess, nil},
+		{"new", StatusPending, nil},
+	}
+	for _, s := range statuses {
+		repo := manager.AddRepository("acme", s.name, "unused", "", SkipExisting)
+		repo.UpdateStatus(s.status, s.err)
+	}
+
+	summary := manager.Summary()
+	if summary.Total != 5 || summary.Succeeded != 2 || summary.Failed != 1 || summary.Skipped != 1 {
+		t.Errorf("summary = %d total, %d succeeded, %d failed, %d skipped; want 5, 2, 1, 1",
+			summary.Total, summary.Succeeded, summary.Failed, summary.Skipped)
+	}
+	if len(summary.Failures) != 1 || summary.Failures[0].Repository != "acme/web" || summary.Failures[0].Error != "repository not found" {
+		t.Errorf("failures = %+v, want acme/web", summary.Failures)
+	}
+	if len(summary.Repositories) != len(statuses) {
+		t.Fatalf("got %d repository results, want %d", len(summary.Repositories), len(statuses))
+	}
+	for i, s := range statuses {
+		got := summary.Repositories[i]
+		if got.Organization != "acme" || got.Name != s.name || got.Status != s.status.String() {
+			t.Errorf("result %d = %+v, want acme/%s %s", i, got, s.name, s.status)
+		}
+	}
+}
+
+func TestCloneDurations(t *testing.T) {
+	remote := newFixtureRemote(t)
+	manager := NewRepositoryManager(t.TempDir(), 2)
+	manager.SetCloneDefaults(testCloneOptions("", ""))
+	if elapsed := manager.Elapsed(); elapsed != 0 {
+		t.Errorf("Elapsed() before cloning = %v, want 0", elapsed)
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		success bool
+	}{
+		{"cloned", remote, true},
+		{"failed", "file://" + filepath.Join(t.TempDir(), "missing.git"), false},
+	}
+	for _, tt := range tests {
+		manager.AddRepository("acme", tt.name, tt.url, "", SkipExisting)
+	}
+	for range manager.CloneAll(t.Context()) {
+	}
+
+	summary := manager.Summary()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := summary.Repositories[i]
+			if (result.Status == StatusSuccess.String()) != tt.success {
+				t.Fatalf("status = %s (%s)", result.Status, result.Error)
+			}
+			if result.DurationSeconds <= 0 {
+				t.Errorf("DurationSeconds = %v, want the time the clone took", result.DurationSeconds)
+			}
+			if result.DurationSeconds > summary.ElapsedSeconds {
+				t.Errorf("DurationSeconds = %v exceeds the batch's %v", result.DurationSeconds, summary.ElapsedSeconds)
+			}
+		})
+	}
+
+	// The batch is over, so its elapsed time no longer grows
+	if first, second := manager.Elapsed(), manager.Elapsed(); first <= 0 || first != second {
+		t.Errorf("Elapsed() = %v then %v, want a fixed positive duration", first, second)
+	}
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// syncExisting updates an existing clone for the Sync strategy. It refuses to
+// touch a directory cloned from a different repository and treats a clone of a
+// still-empty repository as up to date.
+func syncExisting(ctx context.Context, opts CloneOptions) error {
+	origin, err := remoteURL(ctx, opts.TargetDir)
+	if err != nil {
+		return err
+	}
+	if !sameRemote(origin, opts.URL) {
+		return fmt.Errorf("%s is a clone of %s, not %s", opts.TargetDir, redactToken(origin, opts.Token), opts.URL)
+	}
+
+	if revParse(ctx, opts.TargetDir, "HEAD") == "" {
+		empty, err := remoteIsEmpty(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if empty {
+			util.Info(fmt.Sprintf("Repository %s is still empty, nothing to update", opts.URL))
+			opts.ProgressFunc(fmt.Sprintf("Successfully updated repository: %s", opts.URL))
+			return nil
+		}
+	}
+	return backendFor(opts).Update(ctx, opts)
+}
+
+// remoteIsEmpty reports whether the origin of an existing clone has no branches
+func remoteIsEmpty(ctx context.Context, opts CloneOptions) (bool, error) {
+	lsCtx, cancel := context.WithTimeout(ctx, updateTimeout(opts))
+	defer cancel()
+
+	cmd := gitCommand(lsCtx, opts, append(tokenRewriteArgs(opts), "ls-remote", "--heads", "origin")...)
+	cmd.Dir = opts.TargetDir
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to list remote branches of %s: %w", opts.URL, err)
+	}
+	return strings.TrimSpace(string(output)) == "", nil
+}
+
+// sameRemote reports whether two clone URLs name the same repository,
+// ignoring credentials, a .git suffix, case and HTTPS versus SSH form
+func sameRemote(a, b string) bool {
+	return remoteKey(a) == remoteKey(b)
+}
+
+// remoteKey reduces a clone URL to a lowercase "host/path" for comparison
+func remoteKey(rawURL string) string {
+	return strings.ToLower(remotePath(rawURL))
+}
+
+// remotePath reduces a clone URL to "host/path", without credentials or a .git suffix
+func remotePath(rawURL string) string {
+	key := strings.TrimSpace(rawURL)
+	if u, err := url.Parse(key); err == nil && u.Host != "" {
+		key = u.Hostname() + u.Path
+	} else if at := strings.Index(key, "@"); at >= 0 && strings.Contains(key[at:], ":") {
+		// scp-like SSH form, e.g. git@github.com:org/repo.git
+		key = strings.Replace(key[at+1:], ":", "/", 1)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(key, "/"), ".git")
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// Clone phase names recorded in a trace
+const (
+	PhaseExistingRepo = "existing_repo"
+	PhaseAttempt      = "attempt_%d"
+)
+
+// PhaseTiming records how long a single phase of a clone operation took
+type PhaseTiming struct {
+	Phase    string        `json:"phase" yaml:"phase"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// cloneTrace collects phase timings for a single repository
+type cloneTrace struct {
+	phases []PhaseTiming
+}
+
+// record appends the time elapsed since start for the given phase
+func (t *cloneTrace) record(phase string, start time.Time) {
+	if t == nil {
+		return
+	}
+	t.phases = append(t.phases, PhaseTiming{Phase: phase, Duration: time.Since(start)})
+}
+
+// log emits the recorded phase timings as structured log fields
+func (t *cloneTrace) log(url string) {
+	if t == nil || len(t.phases) == 0 {
+		return
+	}
+
+	fields := map[string]interface{}{"url": url}
+	var total time.Duration
+	for _, p := range t.phases {
+		fields[fmt.Sprintf("%s_ms", p.Phase)] = p.Duration.Milliseconds()
+		total += p.Duration
+	}
+	fields["total_ms"] = total.Milliseconds()
+
+	logger := util.WithFields(fields)
+	logger.Debug().Msg("Clone phase timings")
+}
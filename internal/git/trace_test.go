@@ -0,0 +1,72 @@
+package git
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+func TestCloneRecordsPhaseTimings(t *testing.T) {
+	var logs syncBuffer
+	saved := *util.Logger()
+	t.Cleanup(func() { *util.Logger() = saved })
+	if err := util.InitLoggerWithWriter("debug", "json", &logs); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := newFixtureRemote(t)
+	tests := []struct {
+		name string
+		hook string
+		want []string
+	}{
+		{"clone", "", []string{PhaseExistingRepo, "attempt_1"}},
+		{"clone with hook", "true", []string{PhaseExistingRepo, "attempt_1", PhasePostCloneHook}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			opts.PostCloneHook = tt.hook
+			result := cloneOne(t, opts)
+			if !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+
+			var phases []string
+			for _, p := range result.Phases {
+				if p.Duration <= 0 {
+					t.Errorf("phase %s has duration %v", p.Phase, p.Duration)
+				}
+				phases = append(phases, p.Phase)
+			}
+			if strings.Join(phases, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("phases = %v, want %v", phases, tt.want)
+			}
+
+			// The same timings are logged as fields on one line
+			var fields map[string]interface{}
+			for _, line := range strings.Split(logs.String(), "\n") {
+				if strings.Contains(line, "Clone phase timings") {
+					if err := json.Unmarshal([]byte(line), &fields); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if fields == nil {
+				t.Fatalf("no phase timings logged:\n%s", logs.String())
+			}
+			if fields["url"] != remote {
+				t.Errorf("url field = %v, want %s", fields["url"], remote)
+			}
+			for _, phase := range append(tt.want, "total") {
+				if _, ok := fields[phase+"_ms"]; !ok {
+					t.Errorf("log fields %v missing %s_ms", fields, phase)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// LocalRepository is a previously cloned repository found on disk
+type LocalRepository struct {
+	Organization string
+	Name         string
+	Dir          string
+	URL          string
+}
+
+// FindLocalRepositories finds git repositories laid out as baseDir/org/repo,
+// leaving out backups made by overwrites
+func FindLocalRepositories(ctx context.Context, baseDir string) ([]LocalRepository, error) {
+	dirs, err := filepath.Glob(filepath.Join(baseDir, "*", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", baseDir, err)
+	}
+
+	var repos []LocalRepository
+	for _, dir := range dirs {
+		if isBackupDir(filepath.Base(dir)) || !isGitRepo(dir) {
+			continue
+		}
+		url, err := remoteURL(ctx, dir)
+		if err != nil {
+			util.Warn(fmt.Sprintf("Skipping %s: %v", dir, err))
+			continue
+		}
+		repos = append(repos, LocalRepository{
+			Organization: filepath.Base(filepath.Dir(dir)),
+			Name:         filepath.Base(dir),
+			Dir:          dir,
+			URL:          url,
+		})
+	}
+	return repos, nil
+}
+
+// remoteURL returns the URL of the origin remote of a repository
+func remoteURL(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "remote", "get-url", "origin")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get origin URL: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// FetchRemote fetches updates for an existing repository without touching its working tree
+func FetchRemote(ctx context.Context, opts CloneOptions) error {
+	fetchCtx, cancel := context.WithTimeout(ctx, updateTimeout(opts))
+	defer cancel()
+
+	cmd := gitCommand(fetchCtx, opts, buildFetchArgs(opts)...)
+	cmd.Dir = opts.TargetDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if authErr := authRequired(string(output), opts); authErr != nil {
+			return authErr
+		}
+		return fmt.Errorf("failed to fetch updates: %w\nOutput: %s", err, redactToken(string(output), opts.Token))
+	}
+	return nil
+}
+
+// remoteRef returns the remote-tracking ref compared against for the given branch
+func remoteRef(branch string) string {
+	if branch == "" {
+		return "origin/HEAD"
+	}
+	return "origin/" + branch
+}
+
+// targetRef returns the ref an update resets to: the tag when one is set,
+// otherwise the remote-tracking ref of the branch
+func targetRef(opts CloneOptions) string {
+	if opts.Tag != "" {
+		return "refs/tags/" + opts.Tag
+	}
+	return remoteRef(opts.Branch)
+}
+
+// BehindCount returns how many commits the local HEAD is behind the remote branch.
+// FetchRemote should be called first so the remote-tracking ref is current.
+func BehindCount(ctx context.Context, dir, branch string) (int, error) {
+	return behindRef(ctx, dir, remoteRef(branch))
+}
+
+// behindRef returns how many commits the local HEAD is behind ref
+func behindRef(ctx context.Context, dir, ref string) (int, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--count", "HEAD.."+ref)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits behind %s: %w", ref, err)
+	}
+	return parseCount(string(output))
+}
+
+// revParse resolves ref to a commit SHA, returning "" when it does not exist
+func revParse(ctx context.Context, dir, ref string) string {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// needsReset reports whether a fetched repository must be reset: when ref
+// moved during the fetch (before is its SHA prior to fetching) or the local
+// HEAD is still behind it. A tag checkout must sit exactly on the tag.
+func needsReset(ctx context.Context, dir string, opts CloneOptions, before string) bool {
+	ref := targetRef(opts)
+	after := revParse(ctx, dir, ref)
+	if before == "" || after == "" || before != after {
+		return true
+	}
+	if opts.Tag != "" {
+		return revParse(ctx, dir, "HEAD") != after
+	}
+	behind, err := behindRef(ctx, dir, ref)
+	return err != nil || behind > 0
+}
+
+// parseCount parses the output of `git rev-list --count`
+func parseCount(output string) (int, error) {
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected rev-list output %q: %w", output, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,214 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    int
+		wantErr bool
+	}{
+		{"0\n", 0, false},
+		{"12\n", 12, false},
+		{"  3 ", 3, false},
+		{"", 0, true},
+		{"fatal: bad revision", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCount(tt.output)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseCount(%q) = %d, %v, want %d, wantErr %v", tt.output, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestBehindCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		pushed  int
+		branch  string
+		want    int
+		wantErr bool
+	}{
+		{"up to date", 0, "main", 0, false},
+		{"behind", 3, "main", 3, false},
+		{"default branch", 2, "", 2, false},
+		{"unknown branch", 0, "missing", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newFixtureRemote(t)
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			for i := 0; i < tt.pushed; i++ {
+				pushCommit(t, remote, fmt.Sprintf("file%d.txt", i), "change\n")
+			}
+
+			// Counts compare against the remote-tracking ref, so nothing shows until fetched
+			if got, err := BehindCount(context.Background(), opts.TargetDir, "main"); err != nil || got != 0 {
+				t.Fatalf("BehindCount() before fetch = %d, %v, want 0", got, err)
+			}
+			if err := FetchRemote(context.Background(), opts); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := BehindCount(context.Background(), opts.TargetDir, tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BehindCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BehindCount() = %d, want %d", got, tt.want)
+			}
+			if head := runGit(t, opts.TargetDir, "rev-list", "--count", "HEAD"); head != "1" {
+				t.Errorf("fetch moved HEAD to %s commits, want 1", head)
+			}
+		})
+	}
+}
+
+func TestUpdateResetsOnlyWhenRemoteAdvanced(t *testing.T) {
+	tests := []struct {
+		name string
+		// pushed adds a commit to the remote before the update
+		pushed bool
+		// behind leaves the local branch one commit behind an already fetched remote
+		behind    bool
+		wantReset bool
+	}{
+		{"unchanged", false, false, false},
+		{"remote advanced", true, false, true},
+		{"fetched earlier but not reset", false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := newFixtureRemote(t)
+			opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+			if tt.pushed || tt.behind {
+				pushCommit(t, remote, "CHANGES.md", "changed\n")
+			}
+			if tt.behind {
+				runGit(t, opts.TargetDir, "fetch", "origin")
+			}
+			// A local edit survives only when the reset is skipped
+			readme := filepath.Join(opts.TargetDir, "README.md")
+			writeFile(t, readme, "local edit\n")
+
+			opts.ExistingRepo = FetchOnly
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("update failed: %v", result.Error)
+			}
+
+			reset := readFile(t, readme) == "fixture\n"
+			if reset != tt.wantReset {
+				t.Errorf("reset = %v, want %v", reset, tt.wantReset)
+			}
+			if head, want := runGit(t, opts.TargetDir, "rev-parse", "HEAD"), runGit(t, opts.TargetDir, "rev-parse", "origin/main"); head != want {
+				t.Errorf("HEAD = %s, want origin/main at %s", head, want)
+			}
+		})
+	}
+}
+
+func TestNeedsReset(t *testing.T) {
+	remote := newFixtureRemote(t)
+	runGit(t, t.TempDir(), "--git-dir", remote, "tag", "v1.0.0", "main")
+	opts := testCloneOptions(remote, filepath.Join(t.TempDir(), "repo"))
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+	ctx := context.Background()
+	dir := opts.TargetDir
+	current := revParse(ctx, dir, "origin/main")
+
+	tests := []struct {
+		name   string
+		tag    string
+		before string
+		want   bool
+	}{
+		{"ref unchanged", "", current, false},
+		{"ref moved", "", "0000000000000000000000000000000000000000", true},
+		{"ref unknown before fetch", "", "", true},
+		{"on the tag", "v1.0.0", current, false},
+		{"missing tag", "v9.9.9", current, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := opts
+			opts.Tag = tt.tag
+			if got := needsReset(ctx, dir, opts, tt.before); got != tt.want {
+				t.Errorf("needsReset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	tests := []struct {
+		name  string
+		repos int
+	}{
+		{"two repositories", 2},
+		{"four repositories", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			remotes := make([]string, tt.repos)
+			opts := make([]CloneOptions, tt.repos)
+			for i := range remotes {
+				remotes[i] = newFixtureRemote(t)
+				opts[i] = testCloneOptions(remotes[i], filepath.Join(t.TempDir(), "clone"))
+				if result := cloneOne(t, opts[i]); !result.Success {
+					t.Fatalf("clone %d failed: %v", i, result.Error)
+				}
+				// Each remote gets its own content so a crossed-over update shows
+				pushCommit(t, remotes[i], "VERSION", fmt.Sprintf("repo %d\n", i))
+				opts[i].ExistingRepo = FetchOnly
+			}
+
+			errs := make([]error, tt.repos)
+			var wg sync.WaitGroup
+			for i := range opts {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = execBackend{}.Update(context.Background(), opts[i])
+				}()
+			}
+			wg.Wait()
+
+			for i := range opts {
+				if errs[i] != nil {
+					t.Errorf("update %d failed: %v", i, errs[i])
+					continue
+				}
+				if got, want := readFile(t, filepath.Join(opts[i].TargetDir, "VERSION")), fmt.Sprintf("repo %d\n", i); got != want {
+					t.Errorf("repo %d VERSION = %q, want %q", i, got, want)
+				}
+				if local, upstream := runGit(t, opts[i].TargetDir, "rev-parse", "HEAD"), runGit(t, remotes[i], "rev-parse", "main"); local != upstream {
+					t.Errorf("repo %d HEAD = %s, want %s", i, local, upstream)
+				}
+			}
+			if after, _ := os.Getwd(); after != wd {
+				t.Errorf("working directory changed from %s to %s", wd, after)
+			}
+		})
+	}
+}
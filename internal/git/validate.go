@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// optionRule is a single validation rule over clone options
+type optionRule struct {
+	description string
+	violated    func(opts CloneOptions) bool
+}
+
+// optionRules lists the invalid and mutually exclusive option combinations
+var optionRules = []optionRule{
+	{"max retries cannot be negative", func(o CloneOptions) bool { return o.MaxRetries < 0 }},
+	{"jobs cannot be negative", func(o CloneOptions) bool { return o.Jobs < 0 }},
+	{"depth cannot be negative", func(o CloneOptions) bool { return o.Depth < 0 }},
+	{"post-clone hook retries cannot be negative", func(o CloneOptions) bool { return o.PostCloneRetries < 0 }},
+	{"post-clone hook retries require a post-clone hook", func(o CloneOptions) bool {
+		return o.PostCloneHook == "" && len(o.LanguageHooks) == 0 && (o.PostCloneRetries > 0 || len(o.PostCloneRetryCodes) > 0)
+	}},
+	{"branch and tag are mutually exclusive", func(o CloneOptions) bool { return o.Branch != "" && o.Tag != "" }},
+	{"bare and mirror clones have no working tree for worktrees, submodules, pruning or post-clone hooks", func(o CloneOptions) bool {
+		return o.Mode != CloneNormal && (len(o.Worktrees) > 0 || o.Submodules || len(o.KeepExtensions) > 0 || o.PostCloneHook != "" || len(o.LanguageHooks) > 0)
+	}},
+	{"bare and mirror clones cannot check out a tag", func(o CloneOptions) bool { return o.Mode != CloneNormal && o.Tag != "" }},
+	{"the go-git backend does not support bare or mirror clones", func(o CloneOptions) bool {
+		return o.Backend == BackendGoGit && o.Mode != CloneNormal
+	}},
+	{"post-clone hook is not a valid template", func(o CloneOptions) bool {
+		if _, err := parseHookTemplate(o.PostCloneHook); err != nil {
+			return true
+		}
+		for _, hook := range o.LanguageHooks {
+			if _, err := parseHookTemplate(hook); err != nil {
+				return true
+			}
+		}
+		return false
+	}},
+	{"backup retention cannot be negative", func(o CloneOptions) bool { return o.BackupRetention < 0 }},
+	{"unknown backend (use exec or go-git)", func(o CloneOptions) bool {
+		return o.Backend != "" && o.Backend != BackendExec && o.Backend != BackendGoGit
+	}},
+	{"the go-git backend does not support worktrees", func(o CloneOptions) bool {
+		return o.Backend == BackendGoGit && len(o.Worktrees) > 0
+	}},
+	{"the go-git backend does not support skipping LFS smudge", func(o CloneOptions) bool {
+		return o.Backend == BackendGoGit && o.LFSSkipSmudge
+	}},
+	{"SSH key and SSH command are mutually exclusive", func(o CloneOptions) bool {
+		return o.SSHKeyPath != "" && o.SSHCommand != ""
+	}},
+	{"SSH key file does not exist", func(o CloneOptions) bool {
+		if o.SSHKeyPath == "" {
+			return false
+		}
+		info, err := os.Stat(o.SSHKeyPath)
+		return err != nil || info.IsDir()
+	}},
+	{"the go-git backend does not support a custom SSH key or command", func(o CloneOptions) bool {
+		return o.Backend == BackendGoGit && (o.SSHKeyPath != "" || o.SSHCommand != "")
+	}},
+	{"timeouts cannot be negative", func(o CloneOptions) bool {
+		return o.ConnTimeout < 0 || o.CloneTimeout < 0 || o.UpdateTimeout < 0 || o.SlowThreshold < 0 || o.MaxBackoff < 0
+	}},
+}
+
+// ValidateOptions checks clone options for invalid values and conflicting
+// combinations, returning a single error listing every problem found
+func ValidateOptions(opts CloneOptions) error {
+	var problems []string
+	for _, rule := range optionRules {
+		if rule.violated(opts) {
+			problems = append(problems, rule.description)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid clone options:\n  - %s", strings.Join(problems, "\n  - "))
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateOptions(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	writeFile(t, key, "key\n")
+
+	tests := []struct {
+		name   string
+		modify func(*CloneOptions)
+		// want is a substring of the expected error, "" when the options are valid
+		want string
+	}{
+		{"defaults", func(o *CloneOptions) {}, ""},
+		{"normal clone with everything", func(o *CloneOptions) {
+			o.Branch, o.Depth, o.Submodules, o.Worktrees = "main", 1, true, []string{"release/*"}
+			o.PostCloneHook, o.PostCloneRetries, o.SSHKeyPath = "make", 2, key
+		}, ""},
+		{"negative retries", func(o *CloneOptions) { o.MaxRetries = -1 }, "max retries cannot be negative"},
+		{"negative jobs", func(o *CloneOptions) { o.Jobs = -1 }, "jobs cannot be negative"},
+		{"negative depth", func(o *CloneOptions) { o.Depth = -1 }, "depth cannot be negative"},
+		{"negative hook retries", func(o *CloneOptions) { o.PostCloneHook, o.PostCloneRetries = "make", -1 }, "hook retries cannot be negative"},
+		{"hook retries without hook", func(o *CloneOptions) { o.PostCloneRetries = 2 }, "require a post-clone hook"},
+		{"hook retry codes without hook", func(o *CloneOptions) { o.PostCloneRetryCodes = []int{1} }, "require a post-clone hook"},
+		{"hook retries with language hook", func(o *CloneOptions) {
+			o.LanguageHooks, o.PostCloneRetries = map[string]string{"go": "go build ./..."}, 2
+		}, ""},
+		{"branch and tag", func(o *CloneOptions) { o.Branch, o.Tag = "main", "v1.0.0" }, "branch and tag are mutually exclusive"},
+		{"mirror and submodules", func(o *CloneOptions) { o.Mode, o.Submodules = CloneMirror, true }, "no working tree"},
+		{"bare and worktrees", func(o *CloneOptions) { o.Mode, o.Worktrees = CloneBare, []string{"release/*"} }, "no working tree"},
+		{"mirror and pruning", func(o *CloneOptions) { o.Mode, o.KeepExtensions = CloneMirror, []string{".go"} }, "no working tree"},
+		{"bare and hook", func(o *CloneOptions) { o.Mode, o.PostCloneHook = CloneBare, "make" }, "no working tree"},
+		{"mirror and tag", func(o *CloneOptions) { o.Mode, o.Tag = CloneMirror, "v1.0.0" }, "cannot check out a tag"},
+		{"go-git and mirror", func(o *CloneOptions) { o.Backend, o.Mode = BackendGoGit, CloneMirror }, "go-git backend does not support bare or mirror"},
+		{"go-git and worktrees", func(o *CloneOptions) { o.Backend, o.Worktrees = BackendGoGit, []string{"release/*"} }, "go-git backend does not support worktrees"},
+		{"go-git and lfs skip smudge", func(o *CloneOptions) { o.Backend, o.LFSSkipSmudge = BackendGoGit, true }, "skipping LFS smudge"},
+		{"go-git and ssh command", func(o *CloneOptions) { o.Backend, o.SSHCommand = BackendGoGit, "ssh -v" }, "custom SSH key or command"},
+		{"invalid hook template", func(o *CloneOptions) { o.PostCloneHook = "echo {{.Repo" }, "not a valid template"},
+		{"invalid language hook template", func(o *CloneOptions) { o.LanguageHooks = map[string]string{"go": "{{end}}"} }, "not a valid template"},
+		{"negative backup retention", func(o *CloneOptions) { o.BackupRetention = -1 }, "backup retention cannot be negative"},
+		{"unknown backend", func(o *CloneOptions) { o.Backend = "libgit2" }, "unknown backend"},
+		{"ssh key and command", func(o *CloneOptions) { o.SSHKeyPath, o.SSHCommand = key, "ssh -v" }, "mutually exclusive"},
+		{"missing ssh key", func(o *CloneOptions) { o.SSHKeyPath = key + ".missing" }, "SSH key file does not exist"},
+		{"ssh key is a directory", func(o *CloneOptions) { o.SSHKeyPath = filepath.Dir(key) }, "SSH key file does not exist"},
+		{"negative timeout", func(o *CloneOptions) { o.UpdateTimeout = -time.Second }, "timeouts cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultCloneOptions()
+			tt.modify(&opts)
+			err := ValidateOptions(opts)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("ValidateOptions() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateOptions() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOptionsListsEveryConflict(t *testing.T) {
+	opts := DefaultCloneOptions()
+	opts.Branch, opts.Tag = "main", "v1.0.0"
+	opts.Backend, opts.Mode = BackendGoGit, CloneMirror
+	opts.Depth = -1
+
+	err := ValidateOptions(opts)
+	if err == nil {
+		t.Fatal("ValidateOptions() = nil, want conflicts")
+	}
+	for _, want := range []string{
+		"depth cannot be negative",
+		"branch and tag are mutually exclusive",
+		"cannot check out a tag",
+		"go-git backend does not support bare or mirror",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not list %q", err, want)
+		}
+	}
+	if lines := strings.Count(err.Error(), "\n  - "); lines != 4 {
+		t.Errorf("error lists %d problems, want 4:\n%v", lines, err)
+	}
+}
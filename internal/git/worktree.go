@@ -0,0 +1,127 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// PhaseWorktrees is the trace phase covering worktree creation
+const PhaseWorktrees = "worktrees"
+
+// unsafePathChars matches characters that should not appear in worktree directory names
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitizeBranchPath turns a branch name into a single safe path component
+func sanitizeBranchPath(branch string) string {
+	name := unsafePathChars.ReplaceAllString(branch, "-")
+	name = strings.Trim(name, "-.")
+	if name == "" {
+		name = "branch"
+	}
+	return name
+}
+
+// worktreePath returns the directory of the worktree for branch, alongside the primary checkout
+func worktreePath(targetDir, branch string) string {
+	return fmt.Sprintf("%s@%s", strings.TrimRight(targetDir, "/"), sanitizeBranchPath(branch))
+}
+
+// worktreePaths returns the worktree directory of each branch. Branches whose
+// names sanitize to the same directory, such as release/1.0 and release-1.0,
+// get a numeric suffix after the first one.
+func worktreePaths(targetDir string, branches []string) []string {
+	paths := make([]string, len(branches))
+	used := make(map[string]bool, len(branches))
+	for i, branch := range branches {
+		base := worktreePath(targetDir, branch)
+		dir := base
+		for n := 2; used[dir]; n++ {
+			dir = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[dir] = true
+		paths[i] = dir
+	}
+	return paths
+}
+
+// buildWorktreeAddArgs builds the git arguments adding a worktree in dir that tracks origin/<branch>
+func buildWorktreeAddArgs(targetDir, branch, dir string) []string {
+	return []string{"-C", targetDir, "worktree", "add", "-B", branch, dir, "origin/" + branch}
+}
+
+// matchBranches returns the remote branches matching any of the patterns (path.Match syntax)
+func matchBranches(branches []string, patterns []string) []string {
+	var matched []string
+	for _, branch := range branches {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, branch); ok {
+				matched = append(matched, branch)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// remoteBranches lists the branches of origin in a repository
+func remoteBranches(ctx context.Context, dir string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "for-each-ref", "--format=%(refname:short)", "refs/remotes/origin")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote branches: %w", err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		branch := strings.TrimPrefix(line, "origin/")
+		if branch == "" || branch == "HEAD" || branch == "origin" {
+			continue
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// addWorktrees adds a worktree for every remote branch matching opts.Worktrees
+func addWorktrees(ctx context.Context, opts CloneOptions) error {
+	if len(opts.Worktrees) == 0 {
+		return nil
+	}
+
+	branches, err := remoteBranches(ctx, opts.TargetDir)
+	if err != nil {
+		return err
+	}
+
+	primary := opts.Branch
+	if primary == "" {
+		cmd := exec.CommandContext(ctx, "git", "-C", opts.TargetDir, "rev-parse", "--abbrev-ref", "HEAD")
+		if output, err := cmd.Output(); err == nil {
+			primary = strings.TrimSpace(string(output))
+		}
+	}
+
+	var worktrees []string
+	for _, branch := range matchBranches(branches, opts.Worktrees) {
+		if branch != primary { // already the primary checkout
+			worktrees = append(worktrees, branch)
+		}
+	}
+
+	for i, dir := range worktreePaths(opts.TargetDir, worktrees) {
+		branch := worktrees[i]
+		cmd := gitCommand(ctx, opts, buildWorktreeAddArgs(opts.TargetDir, branch, dir)...)
+		util.Debug(fmt.Sprintf("Running git command: %v", cmd.Args))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to add worktree for %s: %w\nOutput: %s", branch, err, output)
+		}
+		opts.ProgressFunc(fmt.Sprintf("Added worktree for branch %s", branch))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeBranchPath(t *testing.T) {
+	tests := []struct {
+		branch, want string
+	}{
+		{"main", "main"},
+		{"release/1.0", "release-1.0"},
+		{"feature/a b:c", "feature-a-b-c"},
+		{"../escape", "escape"},
+		{"//", "branch"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeBranchPath(tt.branch); got != tt.want {
+			t.Errorf("sanitizeBranchPath(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestBuildWorktreeAddArgs(t *testing.T) {
+	tests := []struct {
+		targetDir, branch string
+		want              string
+	}{
+		{"/src/acme/app", "release/1.0", "-C /src/acme/app worktree add -B release/1.0 /src/acme/app@release-1.0 origin/release/1.0"},
+		{"/src/acme/app/", "develop", "-C /src/acme/app/ worktree add -B develop /src/acme/app@develop origin/develop"},
+	}
+	for _, tt := range tests {
+		dir := worktreePath(tt.targetDir, tt.branch)
+		if got := strings.Join(buildWorktreeAddArgs(tt.targetDir, tt.branch, dir), " "); got != tt.want {
+			t.Errorf("buildWorktreeAddArgs(%q, %q) = %q, want %q", tt.targetDir, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestWorktreePaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		branches []string
+		want     []string
+	}{
+		{"distinct", []string{"develop", "release/1.0"}, []string{"/src/app@develop", "/src/app@release-1.0"}},
+		{"colliding", []string{"release-1.0", "release/1.0", "release:1.0"}, []string{"/src/app@release-1.0", "/src/app@release-1.0-2", "/src/app@release-1.0-3"}},
+		{"suffix taken by a branch", []string{"a-2", "a/2", "a-2-2"}, []string{"/src/app@a-2", "/src/app@a-2-2", "/src/app@a-2-2-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := worktreePaths("/src/app", tt.branches)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("worktreePaths(%v) = %v, want %v", tt.branches, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchBranches(t *testing.T) {
+	branches := []string{"main", "release/1.0", "release/2.0", "feature/login"}
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{"glob", []string{"release/*"}, []string{"release/1.0", "release/2.0"}},
+		{"exact and glob", []string{"main", "feature/*"}, []string{"main", "feature/login"}},
+		{"overlapping patterns", []string{"release/*", "release/1.*"}, []string{"release/1.0", "release/2.0"}},
+		{"no match", []string{"hotfix/*"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchBranches(branches, tt.patterns)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("matchBranches(%v) = %v, want %v", tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneAddsWorktrees(t *testing.T) {
+	remote := newFixtureRemote(t, "release/1.0", "release/2.0", "feature/login")
+	tests := []struct {
+		name   string
+		branch string
+		depth  int
+		// want are the branches checked out as worktrees next to the primary checkout
+		want []string
+	}{
+		{"default branch primary", "", 0, []string{"release/1.0", "release/2.0"}},
+		{"matching primary is not repeated", "release/1.0", 0, []string{"release/2.0"}},
+		{"shallow", "", 1, []string{"release/1.0", "release/2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(t.TempDir(), "app")
+			opts := testCloneOptions(remote, target)
+			opts.Branch = tt.branch
+			opts.Depth = tt.depth
+			opts.Worktrees = []string{"release/*"}
+			if result := cloneOne(t, opts); !result.Success {
+				t.Fatalf("clone failed: %v", result.Error)
+			}
+
+			wantPrimary := tt.branch
+			if wantPrimary == "" {
+				wantPrimary = "main"
+			}
+			if got := runGit(t, target, "rev-parse", "--abbrev-ref", "HEAD"); got != wantPrimary {
+				t.Errorf("primary checkout on %q, want %q", got, wantPrimary)
+			}
+			for _, branch := range tt.want {
+				dir := worktreePath(target, branch)
+				if got := runGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != branch {
+					t.Errorf("worktree %s on %q, want %q", dir, got, branch)
+				}
+			}
+			entries, err := os.ReadDir(filepath.Dir(target))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(tt.want)+1 {
+				t.Errorf("directories = %v, want the primary checkout and %d worktrees", entries, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestCloneAddsCollidingWorktrees(t *testing.T) {
+	remote := newFixtureRemote(t, "release/1.0", "release-1.0")
+	target := filepath.Join(t.TempDir(), "app")
+	opts := testCloneOptions(remote, target)
+	opts.Worktrees = []string{"release/*", "release-*"}
+	if result := cloneOne(t, opts); !result.Success {
+		t.Fatalf("clone failed: %v", result.Error)
+	}
+
+	// Both branches get a worktree; the one listed second gets a suffix
+	for dir, branch := range map[string]string{target + "@release-1.0": "release-1.0", target + "@release-1.0-2": "release/1.0"} {
+		if got := runGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != branch {
+			t.Errorf("worktree %s on %q, want %q", dir, got, branch)
+		}
+	}
+}
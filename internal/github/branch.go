@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// SelectBranch picks the branch to clone from the available branch names.
+// The primary branch wins if present, then each fallback in order, and finally
+// the repository's default branch. An empty primary with no fallbacks yields
+// the default branch.
+func SelectBranch(available []string, primary string, fallbacks []string, defaultBranch string) string {
+	exists := make(map[string]bool, len(available))
+	for _, name := range available {
+		exists[name] = true
+	}
+
+	if primary != "" && exists[primary] {
+		return primary
+	}
+	for _, fallback := range fallbacks {
+		if exists[fallback] {
+			return fallback
+		}
+	}
+	return defaultBranch
+}
+
+// ResolveBranch resolves the branch to clone for a repository, consulting its
+// branches when the primary branch is missing and fallbacks are configured
+func (c *Client) ResolveBranch(ctx context.Context, repo *github.Repository, primary string, fallbacks []string) (string, error) {
+	if primary == "" && len(fallbacks) == 0 {
+		return repo.GetDefaultBranch(), nil
+	}
+
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	branches, err := c.ListBranches(ctx, owner, name, &github.BranchListOptions{
+		ListOptions: github.ListOptions{
+			PerPage: c.perPage(),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve branch: %w", err)
+	}
+
+	available := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		available = append(available, branch.GetName())
+	}
+
+	branch := SelectBranch(available, primary, fallbacks, repo.GetDefaultBranch())
+	if primary != "" && branch != primary {
+		util.Info(fmt.Sprintf("Branch %q not found in %s/%s, falling back to %q", primary, owner, name, branch))
+	}
+	return branch, nil
+}
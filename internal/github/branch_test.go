@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestSelectBranch(t *testing.T) {
+	fallbacks := []string{"release", "main", "master"}
+	tests := []struct {
+		name      string
+		available []string
+		primary   string
+		fallbacks []string
+		want      string
+	}{
+		{"primary present", []string{"develop", "release", "main"}, "develop", fallbacks, "develop"},
+		{"first fallback", []string{"release", "main", "master"}, "develop", fallbacks, "release"},
+		{"later fallback", []string{"master", "trunk"}, "develop", fallbacks, "master"},
+		{"default when nothing matches", []string{"trunk"}, "develop", fallbacks, "trunk"},
+		{"no primary tries fallbacks", []string{"main", "trunk"}, "", fallbacks, "main"},
+		{"no primary or fallbacks", []string{"main"}, "", nil, "trunk"},
+		{"missing primary without fallbacks", []string{"main"}, "develop", nil, "trunk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectBranch(tt.available, tt.primary, tt.fallbacks, "trunk"); got != tt.want {
+				t.Errorf("SelectBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBranchAcrossRepositories(t *testing.T) {
+	branchSets := map[string][]string{
+		"api":    {"main", "release"},
+		"web":    {"main", "master"},
+		"legacy": {"master"},
+		"docs":   {"gh-pages"},
+	}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /repos/acme/<name>/branches
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 4 || parts[3] != "branches" {
+			http.NotFound(w, r)
+			return
+		}
+		var branches []*github.Branch
+		for _, name := range branchSets[parts[2]] {
+			branches = append(branches, &github.Branch{Name: github.String(name)})
+		}
+		json.NewEncoder(w).Encode(branches)
+	}))
+
+	fallbacks := []string{"release", "main", "master"}
+	tests := []struct {
+		repo    string
+		primary string
+		want    string
+	}{
+		{"api", "develop", "release"},
+		{"web", "develop", "main"},
+		{"legacy", "develop", "master"},
+		{"docs", "develop", "gh-pages"},
+		{"legacy", "master", "master"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo+"/"+tt.primary, func(t *testing.T) {
+			repo := &github.Repository{
+				Name:          github.String(tt.repo),
+				Owner:         &github.User{Login: github.String("acme")},
+				DefaultBranch: github.String(branchSets[tt.repo][0]),
+			}
+			got, err := client.ResolveBranch(context.Background(), repo, tt.primary, fallbacks)
+			if err != nil {
+				t.Fatalf("ResolveBranch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBranchWithoutFallbacksSkipsListing(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	}))
+	repo := &github.Repository{Name: github.String("api"), DefaultBranch: github.String("main")}
+
+	got, err := client.ResolveBranch(context.Background(), repo, "", nil)
+	if err != nil {
+		t.Fatalf("ResolveBranch() error = %v", err)
+	}
+	if got != "main" {
+		t.Errorf("ResolveBranch() = %q, want %q", got, "main")
+	}
+}
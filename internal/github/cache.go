@@ -0,0 +1,196 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// ListingCache is a cached organization listing. Each page keeps its ETag so
+// an expired listing can be revalidated with conditional requests.
+type ListingCache struct {
+	// Host and Login identify the API server and the user the listing was fetched as
+	Host         string        `json:"host"`
+	Login        string        `json:"login"`
+	Organization string        `json:"organization"`
+	Type         string        `json:"type,omitempty"`
+	FetchedAt    time.Time     `json:"fetched_at"`
+	Pages        []ListingPage `json:"pages"`
+}
+
+// matches reports whether the cache holds the given listing
+func (l *ListingCache) matches(host, login, org, listType string) bool {
+	return l.Host == host && strings.EqualFold(l.Login, login) &&
+		strings.EqualFold(l.Organization, org) && l.Type == listType
+}
+
+// ListingPage is one cached page of an organization listing
+type ListingPage struct {
+	ETag  string               `json:"etag,omitempty"`
+	Repos []*github.Repository `json:"repos"`
+}
+
+// repositories returns the repositories of every cached page
+func (l *ListingCache) repositories() []*github.Repository {
+	var repos []*github.Repository
+	for _, page := range l.Pages {
+		repos = append(repos, page.Repos...)
+	}
+	return repos
+}
+
+// cacheNameReplacer makes an API host such as "ghe.example.com:8443" safe in a file name
+var cacheNameReplacer = strings.NewReplacer(":", "_", "/", "_", "\\", "_")
+
+// listingCachePath returns the cache file for an organization listing of the
+// given type, fetched from host as login
+func listingCachePath(dir, host, login, org, listType string) string {
+	name := strings.ToLower(strings.Join([]string{"repos", cacheNameReplacer.Replace(host), login, org}, "-"))
+	if listType != "" {
+		name += "-" + listType
+	}
+	return filepath.Join(dir, name+".json")
+}
+
+// loadListingCache reads a cached listing. A missing file yields a nil cache.
+func loadListingCache(dir, host, login, org, listType string) (*ListingCache, error) {
+	data, err := os.ReadFile(listingCachePath(dir, host, login, org, listType))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read listing cache: %w", err)
+	}
+
+	var cache ListingCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse listing cache: %w", err)
+	}
+	if !cache.matches(host, login, org, listType) {
+		return nil, nil
+	}
+	return &cache, nil
+}
+
+// saveListingCache persists a listing for its host, login, organization and
+// type. Listings may name private repositories, so only the user can read them.
+func saveListingCache(dir string, cache *ListingCache) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create listing cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to encode listing cache: %w", err)
+	}
+	path := listingCachePath(dir, cache.Host, cache.Login, cache.Organization, cache.Type)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write listing cache: %w", err)
+	}
+	return nil
+}
+
+// SetCache enables caching organization listings in dir for ttl. A listing
+// older than ttl is revalidated page by page with its ETags.
+func (c *Client) SetCache(dir string, ttl time.Duration) {
+	c.cacheDir = dir
+	c.cacheTTL = ttl
+}
+
+// listOrganizationReposCached lists an organization's repositories through the
+// listing cache, calling onPage (if set) with each page
+func (c *Client) listOrganizationReposCached(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions, onPage func([]*github.Repository)) ([]*github.Repository, error) {
+	host, login := c.client.BaseURL.Host, c.token.Login
+	cached, err := loadListingCache(c.cacheDir, host, login, org, opts.Type)
+	if err != nil {
+		util.Warn(fmt.Sprintf("Ignoring listing cache for %s: %v", org, err))
+	}
+	if cached != nil && c.now().Sub(cached.FetchedAt) < c.cacheTTL {
+		util.Info(fmt.Sprintf("Using cached listing of %s from %s", org, cached.FetchedAt.Local().Format(time.RFC1123)))
+		repos := cached.repositories()
+		if onPage != nil && len(repos) > 0 {
+			onPage(repos)
+		}
+		return repos, nil
+	}
+
+	if err := c.WaitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	fresh := &ListingCache{Host: host, Login: login, Organization: org, Type: opts.Type, FetchedAt: c.now()}
+	for page := 1; ; {
+		var etag string
+		if cached != nil && page <= len(cached.Pages) {
+			etag = cached.Pages[page-1].ETag
+		}
+
+		repos, resp, err := c.listOrganizationPage(ctx, org, opts, page, etag)
+		if resp != nil && resp.StatusCode == http.StatusNotModified {
+			// Unchanged since it was cached; a page beyond the cached ones would not have an ETag
+			unchanged := cached.Pages[page-1]
+			fresh.Pages = append(fresh.Pages, unchanged)
+			if onPage != nil {
+				onPage(unchanged.Repos)
+			}
+			if page == len(cached.Pages) {
+				break
+			}
+			page++
+			continue
+		}
+		if err != nil {
+			return fresh.repositories(), fmt.Errorf("failed to list repositories for organization %q: %w", org, auth.CheckSSO(resp, err))
+		}
+
+		fresh.Pages = append(fresh.Pages, ListingPage{ETag: resp.Header.Get("ETag"), Repos: repos})
+		if onPage != nil {
+			onPage(repos)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	if err := saveListingCache(c.cacheDir, fresh); err != nil {
+		util.Warn(fmt.Sprintf("Failed to save listing cache for %s: %v", org, err))
+	}
+	return fresh.repositories(), nil
+}
+
+// listOrganizationPage fetches one page of an organization listing, sending
+// etag (if set) so an unchanged page is answered with 304 Not Modified
+func (c *Client) listOrganizationPage(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions, page int, etag string) ([]*github.Repository, *github.Response, error) {
+	query := url.Values{}
+	query.Set("page", fmt.Sprintf("%d", page))
+	if opts.PerPage > 0 {
+		query.Set("per_page", fmt.Sprintf("%d", opts.PerPage))
+	}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+
+	req, err := c.client.NewRequest("GET", fmt.Sprintf("orgs/%s/repos?%s", url.PathEscape(org), query.Encode()), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	var repos []*github.Repository
+	resp, err := c.client.Do(ctx, req, &repos)
+	c.recordRate(resp)
+	return repos, resp, err
+}
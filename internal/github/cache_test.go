@@ -0,0 +1,126 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// cachingServer serves a one-page listing of acme with an ETag, answering a
+// matching If-None-Match with 304, and counts the listing requests
+type cachingServer struct {
+	requests    int
+	notModified int
+}
+
+func (s *cachingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests++
+	if r.Header.Get("If-None-Match") == `"v1"` {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", `"v1"`)
+	json.NewEncoder(w).Encode([]*github.Repository{{Name: github.String("api")}})
+}
+
+func TestListingCache(t *testing.T) {
+	server := &cachingServer{}
+	client := newTestClient(t, server)
+	client.token.Login = "octocat"
+	dir := t.TempDir()
+	client.SetCache(dir, time.Hour)
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return clock }
+
+	list := func() []*github.Repository {
+		t.Helper()
+		repos, err := client.ListOrganizationRepos(t.Context(), "acme", listOptions("", 100))
+		if err != nil {
+			t.Fatalf("ListOrganizationRepos() error = %v", err)
+		}
+		return repos
+	}
+
+	steps := []struct {
+		name        string
+		advance     time.Duration
+		requests    int
+		notModified int
+	}{
+		{"first listing is fetched", 0, 1, 0},
+		{"fresh listing is served from the cache", 30 * time.Minute, 1, 0},
+		{"expired listing is revalidated", time.Hour, 2, 1},
+		{"revalidated listing is fresh again", 30 * time.Minute, 2, 1},
+	}
+	for _, step := range steps {
+		clock = clock.Add(step.advance)
+		repos := list()
+		if len(repos) != 1 || repos[0].GetName() != "api" {
+			t.Fatalf("%s: listed %v", step.name, repos)
+		}
+		if server.requests != step.requests || server.notModified != step.notModified {
+			t.Errorf("%s: %d requests (%d not modified), want %d (%d)", step.name, server.requests, server.notModified, step.requests, step.notModified)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		path := listingCachePath(dir, client.client.BaseURL.Host, "octocat", "acme", "")
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("cache file mode = %v, want 0600", perm)
+		}
+	}
+}
+
+func TestListingCacheIsScopedToHostAndLogin(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache := &ListingCache{
+		Host:         "api.github.com",
+		Login:        "octocat",
+		Organization: "acme",
+		FetchedAt:    time.Now(),
+		Pages:        []ListingPage{{Repos: []*github.Repository{{Name: github.String("api")}}}},
+	}
+	if err := saveListingCache(dir, cache); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dir); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0700 {
+		t.Errorf("cache directory mode = %v, want 0700", info.Mode().Perm())
+	}
+
+	tests := []struct {
+		name  string
+		host  string
+		login string
+		org   string
+		hit   bool
+	}{
+		{"same scope", "api.github.com", "octocat", "acme", true},
+		{"organization case", "api.github.com", "octocat", "ACME", true},
+		{"enterprise host", "ghe.example.com", "octocat", "acme", false},
+		{"other token user", "api.github.com", "hubot", "acme", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadListingCache(dir, tt.host, tt.login, tt.org, "")
+			if err != nil {
+				t.Fatalf("loadListingCache() error = %v", err)
+			}
+			if (got != nil) != tt.hit {
+				t.Errorf("loadListingCache() hit = %v, want %v", got != nil, tt.hit)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v60/github"
@@ -14,8 +16,30 @@ import (
 type Client struct {
 	client *github.Client
 	token  *auth.Token
+
+	// listStateDir enables resumable organization listings when set
+	listStateDir string
+
+	// pageSize is the number of items requested per API page (0 = MaxPageSize)
+	pageSize int
+
+	// allowedOrgs restricts which organizations may be listed (empty = no restriction)
+	allowedOrgs []string
+
+	// cacheDir enables the organization listing cache when set
+	cacheDir string
+	cacheTTL time.Duration
+	// now is the clock used for cache expiry
+	now func() time.Time
+
+	// rateLimit is the status seen by the most recent API response or rate limit check
+	rateMu    sync.Mutex
+	rateLimit *RateLimitInfo
 }
 
+// MaxPageSize is the largest page size accepted by the GitHub API
+const MaxPageSize = 100
+
 // RateLimitInfo contains information about the current rate limit status
 type RateLimitInfo struct {
 	Remaining int
@@ -28,7 +52,82 @@ func NewClient(ctx context.Context, token *auth.Token) *Client {
 	return &Client{
 		client: auth.CreateGitHubClient(ctx, token),
 		token:  token,
+		now:    time.Now,
+	}
+}
+
+// Token returns the authentication token used by the client
+func (c *Client) Token() *auth.Token {
+	return c.token
+}
+
+// Revalidate validates the token again, e.g. after the user authorized it for SSO
+func (c *Client) Revalidate(ctx context.Context) error {
+	if _, err := auth.ValidateToken(ctx, c.token.Value, c.token.BaseURL, c.token.Proxy); err != nil {
+		return err
 	}
+	return nil
+}
+
+// SetListStateDir enables persisting the pagination cursor of organization
+// listings to dir, so an interrupted listing resumes where it stopped
+func (c *Client) SetListStateDir(dir string) {
+	c.listStateDir = dir
+}
+
+// ClampPageSize limits a page size to the range accepted by the GitHub API
+func ClampPageSize(size int) int {
+	if size < 1 {
+		return 1
+	}
+	if size > MaxPageSize {
+		return MaxPageSize
+	}
+	return size
+}
+
+// SetPageSize sets the number of items requested per API page, clamped to 1-100
+func (c *Client) SetPageSize(size int) {
+	c.pageSize = ClampPageSize(size)
+}
+
+// perPage returns the page size used for list requests
+func (c *Client) perPage() int {
+	if c.pageSize == 0 {
+		return MaxPageSize
+	}
+	return c.pageSize
+}
+
+// PageSize returns the number of items requested per API page
+func (c *Client) PageSize() int {
+	return c.perPage()
+}
+
+// SetAllowedOrgs restricts the organizations this client may list repositories from
+func (c *Client) SetAllowedOrgs(orgs []string) {
+	c.allowedOrgs = orgs
+}
+
+// IsOrgAllowed reports whether org is permitted by the allowlist. An empty allowlist permits every organization.
+func IsOrgAllowed(allowed []string, org string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(a), org) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOrgAllowed returns an error when org is not in the client's allowlist
+func (c *Client) checkOrgAllowed(org string) error {
+	if !IsOrgAllowed(c.allowedOrgs, org) {
+		return fmt.Errorf("organization %q is not in security.allowed_orgs (%s)", org, strings.Join(c.allowedOrgs, ", "))
+	}
+	return nil
 }
 
 // GetRateLimit returns the current rate limit status
@@ -39,18 +138,52 @@ func (c *Client) GetRateLimit(ctx context.Context) (*RateLimitInfo, error) {
 	}
 
 	core := limits.Core
-	return &RateLimitInfo{
+	info := &RateLimitInfo{
 		Remaining: core.Remaining,
 		Limit:     core.Limit,
 		Reset:     core.Reset.Time,
-	}, nil
+	}
+
+	c.setRateLimit(info)
+	return info, nil
+}
+
+// recordRate keeps the rate limit reported in the headers of an API response,
+// so the status stays current without querying it separately
+func (c *Client) recordRate(resp *github.Response) {
+	if resp == nil || resp.Rate.Limit == 0 {
+		return
+	}
+	c.setRateLimit(&RateLimitInfo{
+		Remaining: resp.Rate.Remaining,
+		Limit:     resp.Rate.Limit,
+		Reset:     resp.Rate.Reset.Time,
+	})
+}
+
+// setRateLimit stores the most recently seen rate limit status
+func (c *Client) setRateLimit(info *RateLimitInfo) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	c.rateLimit = info
 }
 
-// WaitForRateLimit waits until the rate limit resets if necessary
+// LastRateLimit returns the rate limit status seen by the most recent API response or check, or nil before the first one
+func (c *Client) LastRateLimit() *RateLimitInfo {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rateLimit
+}
+
+// WaitForRateLimit waits until the rate limit resets if necessary. It relies
+// on the status from the latest response and only queries it before the first.
 func (c *Client) WaitForRateLimit(ctx context.Context) error {
-	info, err := c.GetRateLimit(ctx)
-	if err != nil {
-		return err
+	info := c.LastRateLimit()
+	if info == nil {
+		var err error
+		if info, err = c.GetRateLimit(ctx); err != nil {
+			return err
+		}
 	}
 
 	if info.Remaining > 0 {
@@ -78,33 +211,134 @@ func (c *Client) GetOrganization(ctx context.Context, name string) (*github.Orga
 		return nil, err
 	}
 
-	org, _, err := c.client.Organizations.Get(ctx, name)
+	org, resp, err := c.client.Organizations.Get(ctx, name)
+	c.recordRate(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get organization %q: %w", name, err)
+		return nil, fmt.Errorf("failed to get organization %q: %w", name, auth.CheckSSO(resp, err))
 	}
 
 	return org, nil
 }
 
-// ListOrganizationRepos lists all repositories in an organization with pagination
+// ListOrganizationRepos lists all repositories in an organization with pagination.
+// Listing starts at opts.Page; when a list state directory is configured and
+// opts.Page is unset, a previously interrupted listing is resumed. On error the
+// repositories fetched so far are returned along with the error.
 func (c *Client) ListOrganizationRepos(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, error) {
+	return c.ListOrganizationReposPaged(ctx, org, opts, nil)
+}
+
+// ListOrganizationReposPaged is like ListOrganizationRepos but also calls onPage
+// with each page of repositories as soon as it arrives
+func (c *Client) ListOrganizationReposPaged(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions, onPage func([]*github.Repository)) ([]*github.Repository, error) {
+	if err := c.checkOrgAllowed(org); err != nil {
+		return nil, err
+	}
+	if c.cacheDir != "" && opts.Page == 0 {
+		return c.listOrganizationReposCached(ctx, org, opts, onPage)
+	}
 	if err := c.WaitForRateLimit(ctx); err != nil {
 		return nil, err
 	}
 
 	var allRepos []*github.Repository
+	if c.listStateDir != "" && opts.Page == 0 {
+		cursor, err := loadListCursor(c.listStateDir, org, opts, c.now())
+		if err != nil {
+			util.Warn(fmt.Sprintf("Ignoring listing state for %s: %v", org, err))
+		} else if cursor != nil {
+			util.Info(fmt.Sprintf("Resuming listing of %s from page %d (%d repositories already fetched)", org, cursor.NextPage, len(cursor.Repos)))
+			allRepos = cursor.Repos
+			opts.Page = cursor.NextPage
+		}
+	}
+	if onPage != nil && len(allRepos) > 0 {
+		onPage(allRepos)
+	}
+
 	for {
 		repos, resp, err := c.client.Repositories.ListByOrg(ctx, org, opts)
+		c.recordRate(resp)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list repositories for organization %q: %w", org, err)
+			return allRepos, fmt.Errorf("failed to list repositories for organization %q: %w", org, auth.CheckSSO(resp, err))
 		}
 
 		allRepos = append(allRepos, repos...)
+		if onPage != nil {
+			onPage(repos)
+		}
 
 		if resp.NextPage == 0 {
 			break
 		}
 		opts.Page = resp.NextPage
+
+		if c.listStateDir != "" {
+			cursor := &ListCursor{
+				Organization: org,
+				Type:         opts.Type,
+				PerPage:      opts.PerPage,
+				SavedAt:      c.now(),
+				NextPage:     resp.NextPage,
+				Repos:        allRepos,
+			}
+			if err := saveListCursor(c.listStateDir, cursor); err != nil {
+				util.Warn(fmt.Sprintf("Failed to save listing state for %s: %v", org, err))
+			}
+		}
+	}
+
+	if c.listStateDir != "" {
+		clearListCursor(c.listStateDir, org)
+	}
+
+	return allRepos, nil
+}
+
+// ListUserRepos lists all repositories owned by a user, following pagination.
+// For the authenticated user this includes private repositories.
+func (c *Client) ListUserRepos(ctx context.Context, user string, opts *github.ListOptions) ([]*github.Repository, error) {
+	if err := c.checkOrgAllowed(user); err != nil {
+		return nil, err
+	}
+	if err := c.WaitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &github.ListOptions{}
+	}
+	if opts.PerPage == 0 {
+		opts.PerPage = c.perPage()
+	}
+	self := c.token != nil && strings.EqualFold(c.token.Login, user)
+
+	var allRepos []*github.Repository
+	for {
+		var repos []*github.Repository
+		var resp *github.Response
+		var err error
+		if self {
+			repos, resp, err = c.client.Repositories.ListByAuthenticatedUser(ctx, &github.RepositoryListByAuthenticatedUserOptions{
+				Affiliation: "owner",
+				ListOptions: *opts,
+			})
+		} else {
+			repos, resp, err = c.client.Repositories.ListByUser(ctx, user, &github.RepositoryListByUserOptions{
+				Type:        "owner",
+				ListOptions: *opts,
+			})
+		}
+		c.recordRate(resp)
+		if err != nil {
+			return allRepos, fmt.Errorf("failed to list repositories for user %q: %w", user, err)
+		}
+
+		allRepos = append(allRepos, repos...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
 	return allRepos, nil
@@ -112,18 +346,34 @@ func (c *Client) ListOrganizationRepos(ctx context.Context, org string, opts *gi
 
 // GetRepository gets information about a specific repository
 func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*github.Repository, error) {
+	if err := c.checkOrgAllowed(owner); err != nil {
+		return nil, err
+	}
 	if err := c.WaitForRateLimit(ctx); err != nil {
 		return nil, err
 	}
 
-	repository, _, err := c.client.Repositories.Get(ctx, owner, repo)
+	repository, resp, err := c.client.Repositories.Get(ctx, owner, repo)
+	c.recordRate(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get repository %s/%s: %w", owner, repo, err)
+		return nil, fmt.Errorf("failed to get repository %s/%s: %w", owner, repo, auth.CheckSSO(resp, err))
 	}
 
 	return repository, nil
 }
 
+// ClassifyRepositoryAccess reports whether the token can read a repository,
+// distinguishing missing from forbidden
+func (c *Client) ClassifyRepositoryAccess(ctx context.Context, owner, repo string) (auth.AccessStatus, error) {
+	if err := c.WaitForRateLimit(ctx); err != nil {
+		return "", err
+	}
+
+	_, resp, err := c.client.Repositories.Get(ctx, owner, repo)
+	c.recordRate(resp)
+	return auth.ClassifyAccess(resp, err)
+}
+
 // ListBranches lists all branches in a repository
 func (c *Client) ListBranches(ctx context.Context, owner, repo string, opts *github.BranchListOptions) ([]*github.Branch, error) {
 	if err := c.WaitForRateLimit(ctx); err != nil {
@@ -133,8 +383,9 @@ func (c *Client) ListBranches(ctx context.Context, owner, repo string, opts *git
 	var allBranches []*github.Branch
 	for {
 		branches, resp, err := c.client.Repositories.ListBranches(ctx, owner, repo, opts)
+		c.recordRate(resp)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list branches for repository %s/%s: %w", owner, repo, err)
+			return nil, fmt.Errorf("failed to list branches for repository %s/%s: %w", owner, repo, auth.CheckSSO(resp, err))
 		}
 
 		allBranches = append(allBranches, branches...)
@@ -0,0 +1,322 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+)
+
+// newTestClient returns a client talking to a test server that serves api.
+// Rate limit checks are answered with a full quota unless api handles them.
+func newTestClient(t *testing.T, api http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rate_limit" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"resources":{"core":{"limit":5000,"remaining":5000,"reset":0}}}`))
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(server.Client())
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: client, token: &auth.Token{Value: "test-token"}, now: time.Now}
+}
+
+func TestIsOrgAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		org     string
+		want    bool
+	}{
+		{"empty allowlist", nil, "acme", true},
+		{"listed", []string{"acme", "partner"}, "partner", true},
+		{"case insensitive", []string{"Acme"}, "acme", true},
+		{"surrounding spaces", []string{" acme "}, "acme", true},
+		{"not listed", []string{"acme"}, "evil", false},
+		{"prefix is not a match", []string{"acme"}, "acme-labs", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOrgAllowed(tt.allowed, tt.org); got != tt.want {
+				t.Errorf("IsOrgAllowed(%v, %q) = %v, want %v", tt.allowed, tt.org, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowedOrgsCheckedBeforeListing(t *testing.T) {
+	var listed []string
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listed = append(listed, r.URL.Path)
+		w.Write([]byte(`[]`))
+	})
+	tests := []struct {
+		name    string
+		allowed []string
+		org     string
+		wantErr bool
+	}{
+		{"no restriction", nil, "acme", false},
+		{"allowed", []string{"acme"}, "acme", false},
+		{"denied", []string{"acme"}, "other", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed = nil
+			client := newTestClient(t, api)
+			client.SetAllowedOrgs(tt.allowed)
+			_, err := client.ListOrganizationRepos(context.Background(), tt.org, &github.RepositoryListByOrgOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListOrganizationRepos() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), "security.allowed_orgs") {
+					t.Errorf("error %q does not mention security.allowed_orgs", err)
+				}
+				if len(listed) != 0 {
+					t.Errorf("denied organization was listed: %v", listed)
+				}
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		name string
+		set  *int
+		want int
+	}{
+		{"unset", nil, MaxPageSize},
+		{"in range", github.Int(25), 25},
+		{"zero", github.Int(0), 1},
+		{"negative", github.Int(-5), 1},
+		{"too large", github.Int(500), MaxPageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []string
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = append(requested, r.URL.Path+" per_page="+r.URL.Query().Get("per_page"))
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`[]`))
+			}))
+			if tt.set != nil {
+				client.SetPageSize(*tt.set)
+			}
+			if got := client.PageSize(); got != tt.want {
+				t.Errorf("PageSize() = %d, want %d", got, tt.want)
+			}
+
+			if _, err := client.ListFilteredRepositories(t.Context(), "acme", nil); err != nil {
+				t.Fatalf("ListFilteredRepositories() error = %v", err)
+			}
+			repo := &github.Repository{Name: github.String("app"), Owner: &github.User{Login: github.String("acme")}}
+			if _, err := client.ResolveBranch(t.Context(), repo, "develop", []string{"main"}); err != nil {
+				t.Fatalf("ResolveBranch() error = %v", err)
+			}
+			perPage := fmt.Sprintf(" per_page=%d", tt.want)
+			want := []string{"/orgs/acme/repos" + perPage, "/repos/acme/app/branches" + perPage}
+			if !slices.Equal(requested, want) {
+				t.Errorf("requests = %q, want %q", requested, want)
+			}
+		})
+	}
+}
+
+func TestGetRepository(t *testing.T) {
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/app" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"app","full_name":"acme/app","default_branch":"trunk","clone_url":"https://github.com/acme/app.git"}`))
+	})
+	tests := []struct {
+		name    string
+		allowed []string
+		owner   string
+		repo    string
+		wantErr string
+	}{
+		{"resolved", nil, "acme", "app", ""},
+		{"not found", nil, "acme", "missing", "failed to get repository acme/missing"},
+		{"owner not allowed", []string{"other"}, "acme", "app", "security.allowed_orgs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, api)
+			client.SetAllowedOrgs(tt.allowed)
+			repo, err := client.GetRepository(context.Background(), tt.owner, tt.repo)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetRepository() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetRepository() error = %v", err)
+			}
+			if repo.GetFullName() != "acme/app" || repo.GetDefaultBranch() != "trunk" {
+				t.Errorf("GetRepository() = %s on %s, want acme/app on trunk", repo.GetFullName(), repo.GetDefaultBranch())
+			}
+		})
+	}
+}
+
+func TestRateLimitFromResponses(t *testing.T) {
+	reset := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var checks, remaining int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			checks++
+			w.Write([]byte(`{"resources":{"core":{"limit":5000,"remaining":5000,"reset":0}}}`))
+			return
+		}
+		remaining--
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(remaining))
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	gc := github.NewClient(server.Client())
+	gc.BaseURL, _ = url.Parse(server.URL + "/")
+	client := &Client{client: gc, token: &auth.Token{Value: "test-token"}, now: time.Now}
+
+	tests := []struct {
+		name      string
+		remaining int
+	}{
+		{"first listing checks the rate limit once", 4999},
+		{"later listings use the response headers", 4998},
+		{"headers keep updating the status", 4997},
+	}
+	remaining = 5000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.ListOrganizationRepos(t.Context(), "acme", nil); err != nil {
+				t.Fatalf("ListOrganizationRepos() error = %v", err)
+			}
+			want := &RateLimitInfo{Remaining: tt.remaining, Limit: 5000, Reset: reset}
+			if got := client.LastRateLimit(); got == nil || got.Remaining != want.Remaining || got.Limit != want.Limit || !got.Reset.Equal(want.Reset) {
+				t.Errorf("LastRateLimit() = %+v, want %+v", got, want)
+			}
+			if checks != 1 {
+				t.Errorf("rate limit queried %d times, want once", checks)
+			}
+		})
+	}
+}
+
+func TestClassifyRepositoryAccessRecordsRate(t *testing.T) {
+	reset := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		if r.URL.Path == "/repos/acme/secret" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Resource not accessible by personal access token"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"app"}`))
+	})
+
+	tests := []struct {
+		repo string
+		want auth.AccessStatus
+	}{
+		{"app", auth.AccessGranted},
+		{"secret", auth.AccessForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			client := newTestClient(t, api)
+			got, err := client.ClassifyRepositoryAccess(t.Context(), "acme", tt.repo)
+			if err != nil {
+				t.Fatalf("ClassifyRepositoryAccess() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ClassifyRepositoryAccess() = %q, want %q", got, tt.want)
+			}
+			if rate := client.LastRateLimit(); rate == nil || rate.Remaining != 4321 || !rate.Reset.Equal(reset) {
+				t.Errorf("LastRateLimit() = %+v, want the response headers recorded", rate)
+			}
+		})
+	}
+}
+
+func TestListUserReposPagination(t *testing.T) {
+	// Each page links to the next until the last, as GitHub does
+	pages := map[string]string{
+		"":  `[{"name":"one","full_name":"octocat/one"},{"name":"two","full_name":"octocat/two"}]`,
+		"2": `[{"name":"three","full_name":"octocat/three"},{"name":"four","full_name":"octocat/four"}]`,
+		"3": `[{"name":"five","full_name":"octocat/five"}]`,
+	}
+	var requested []string
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		// Only repositories owned by the user are listed, not those of their organizations
+		owner := r.URL.Query().Get("type") + r.URL.Query().Get("affiliation")
+		requested = append(requested, r.URL.Path+"?page="+page+" "+owner)
+		body, ok := pages[page]
+		if !ok || (r.URL.Path != "/users/octocat/repos" && r.URL.Path != "/user/repos") {
+			http.NotFound(w, r)
+			return
+		}
+		if next := map[string]string{"": "2", "2": "3"}[page]; next != "" {
+			w.Header().Set("Link", `<http://`+r.Host+r.URL.Path+`?page=`+next+`>; rel="next"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+
+	tests := []struct {
+		name  string
+		login string
+		path  string
+	}{
+		{"other user", "someone-else", "/users/octocat/repos"},
+		{"authenticated user", "OctoCat", "/user/repos"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			client := newTestClient(t, api)
+			client.token.Login = tt.login
+			repos, err := client.ListUserRepos(context.Background(), "octocat", nil)
+			if err != nil {
+				t.Fatalf("ListUserRepos() error = %v", err)
+			}
+
+			var names []string
+			for _, repo := range repos {
+				names = append(names, repo.GetName())
+			}
+			if got := strings.Join(names, " "); got != "one two three four five" {
+				t.Errorf("ListUserRepos() = %q, want all five repositories in order", got)
+			}
+			want := tt.path + "?page= owner, " + tt.path + "?page=2 owner, " + tt.path + "?page=3 owner"
+			if got := strings.Join(requested, ", "); got != want {
+				t.Errorf("requests = %q, want %q", got, want)
+			}
+		})
+	}
+}
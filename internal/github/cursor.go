@@ -0,0 +1,85 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// ListCursor records how far a paginated organization listing has progressed
+// so that an interrupted listing can be resumed
+type ListCursor struct {
+	Organization string `json:"organization"`
+	// Type and PerPage are the listing options the pages were fetched with
+	Type     string               `json:"type,omitempty"`
+	PerPage  int                  `json:"per_page"`
+	SavedAt  time.Time            `json:"saved_at"`
+	NextPage int                  `json:"next_page"`
+	Repos    []*github.Repository `json:"repos"`
+}
+
+// cursorMaxAge is how long an interrupted listing can be resumed; an older
+// cursor is likely out of date with the organization
+const cursorMaxAge = 24 * time.Hour
+
+// resumes reports whether the cursor continues a listing of org with opts at now
+func (c *ListCursor) resumes(org string, opts *github.RepositoryListByOrgOptions, now time.Time) bool {
+	return c.Organization == org && c.NextPage > 0 &&
+		c.Type == opts.Type && c.PerPage == opts.PerPage &&
+		now.Sub(c.SavedAt) < cursorMaxAge
+}
+
+// cursorPath returns the state file used for the given organization
+func cursorPath(dir, org string) string {
+	return filepath.Join(dir, fmt.Sprintf("listing-%s.json", org))
+}
+
+// loadListCursor reads a saved cursor continuing a listing of the organization
+// with opts. A missing file, or a cursor saved with other options or more than
+// cursorMaxAge ago, yields a nil cursor.
+func loadListCursor(dir, org string, opts *github.RepositoryListByOrgOptions, now time.Time) (*ListCursor, error) {
+	data, err := os.ReadFile(cursorPath(dir, org))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read listing state: %w", err)
+	}
+
+	var cursor ListCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("failed to parse listing state: %w", err)
+	}
+	if !cursor.resumes(org, opts, now) {
+		return nil, nil
+	}
+	return &cursor, nil
+}
+
+// saveListCursor persists the cursor for its organization
+func saveListCursor(dir string, cursor *ListCursor) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create listing state directory: %w", err)
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return fmt.Errorf("failed to encode listing state: %w", err)
+	}
+	if err := os.WriteFile(cursorPath(dir, cursor.Organization), data, 0644); err != nil {
+		return fmt.Errorf("failed to write listing state: %w", err)
+	}
+	return nil
+}
+
+// clearListCursor removes the saved cursor once a listing has completed
+func clearListCursor(dir, org string) {
+	if err := os.Remove(cursorPath(dir, org)); err != nil && !os.IsNotExist(err) {
+		util.Warn(fmt.Sprintf("Failed to remove listing state for %s: %v", org, err))
+	}
+}
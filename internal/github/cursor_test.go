@@ -0,0 +1,97 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestLoadListCursor(t *testing.T) {
+	saved := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	cursor := &ListCursor{
+		Organization: "acme",
+		Type:         "private",
+		PerPage:      50,
+		SavedAt:      saved,
+		NextPage:     3,
+		Repos:        []*github.Repository{{Name: github.String("api")}},
+	}
+	if err := saveListCursor(dir, cursor); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		org     string
+		opts    *github.RepositoryListByOrgOptions
+		now     time.Time
+		resumes bool
+	}{
+		{"same listing", "acme", listOptions("private", 50), saved.Add(time.Hour), true},
+		{"other organization", "other", listOptions("private", 50), saved.Add(time.Hour), false},
+		{"other visibility", "acme", listOptions("all", 50), saved.Add(time.Hour), false},
+		{"other page size", "acme", listOptions("private", 100), saved.Add(time.Hour), false},
+		{"expired", "acme", listOptions("private", 50), saved.Add(cursorMaxAge), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadListCursor(dir, tt.org, tt.opts, tt.now)
+			if err != nil {
+				t.Fatalf("loadListCursor() error = %v", err)
+			}
+			if (got != nil) != tt.resumes {
+				t.Fatalf("loadListCursor() = %v, want resume %v", got, tt.resumes)
+			}
+			if got != nil && (got.NextPage != 3 || len(got.Repos) != 1) {
+				t.Errorf("loadListCursor() = page %d with %d repos, want page 3 with 1", got.NextPage, len(got.Repos))
+			}
+		})
+	}
+}
+
+func TestListOrganizationReposResumes(t *testing.T) {
+	requested := map[string]bool{}
+	failPage := "2"
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		requested[page] = true
+		if page == failPage {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if page == "1" {
+			w.Header().Set("Link", `<`+"http://"+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+		}
+		json.NewEncoder(w).Encode([]*github.Repository{{Name: github.String("repo-" + page)}})
+	}))
+	client.SetListStateDir(t.TempDir())
+
+	if _, err := client.ListOrganizationRepos(t.Context(), "acme", listOptions("", 1)); err == nil {
+		t.Fatal("ListOrganizationRepos() succeeded although page 2 failed")
+	}
+
+	failPage = ""
+	requested = map[string]bool{}
+	repos, err := client.ListOrganizationRepos(t.Context(), "acme", listOptions("", 1))
+	if err != nil {
+		t.Fatalf("ListOrganizationRepos() error = %v", err)
+	}
+	if requested["1"] {
+		t.Error("resumed listing requested page 1 again")
+	}
+	if len(repos) != 2 {
+		t.Errorf("ListOrganizationRepos() returned %d repositories, want 2", len(repos))
+	}
+}
+
+// listOptions returns organization listing options with the given type and page size
+func listOptions(listType string, perPage int) *github.RepositoryListByOrgOptions {
+	return &github.RepositoryListByOrgOptions{Type: listType, ListOptions: github.ListOptions{PerPage: perPage}}
+}
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// ErrDependencyGraphUnavailable is returned when a repository's dependency graph
+// cannot be read, e.g. because it is disabled or not available on the plan
+var ErrDependencyGraphUnavailable = errors.New("dependency graph not available")
+
+// packageMatches reports whether an SBOM package name such as "npm:lodash"
+// refers to pkg, which may be given with or without the ecosystem prefix
+func packageMatches(name, pkg string) bool {
+	if strings.EqualFold(name, pkg) {
+		return true
+	}
+	if _, bare, ok := strings.Cut(name, ":"); ok {
+		return strings.EqualFold(bare, pkg)
+	}
+	return false
+}
+
+// SBOMDependsOn reports whether the SBOM lists the given package
+func SBOMDependsOn(sbom *github.SBOM, pkg string) bool {
+	if sbom == nil || sbom.SBOM == nil {
+		return false
+	}
+	for _, p := range sbom.SBOM.Packages {
+		if packageMatches(p.GetName(), pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// RepoDependsOn reports whether a repository's dependency graph contains the package
+func (c *Client) RepoDependsOn(ctx context.Context, owner, repo, pkg string) (bool, error) {
+	if err := c.WaitForRateLimit(ctx); err != nil {
+		return false, err
+	}
+
+	sbom, resp, err := c.client.DependencyGraph.GetSBOM(ctx, owner, repo)
+	c.recordRate(resp)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == 403 || resp.StatusCode == 404) {
+			return false, ErrDependencyGraphUnavailable
+		}
+		return false, fmt.Errorf("failed to get dependency graph of %s/%s: %w", owner, repo, err)
+	}
+	return SBOMDependsOn(sbom, pkg), nil
+}
+
+// FilterByDependency keeps the repositories whose dependency graph contains the package
+func (c *Client) FilterByDependency(ctx context.Context, repos []*github.Repository, pkg string) ([]*github.Repository, error) {
+	filtered := make([]*github.Repository, 0, len(repos))
+	unavailable := 0
+
+	for _, repo := range repos {
+		depends, err := c.RepoDependsOn(ctx, repo.GetOwner().GetLogin(), repo.GetName(), pkg)
+		if errors.Is(err, ErrDependencyGraphUnavailable) {
+			unavailable++
+			util.Debug(fmt.Sprintf("Dependency graph not available for %s", repo.GetFullName()))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if depends {
+			filtered = append(filtered, repo)
+		}
+	}
+
+	if len(repos) > 0 && unavailable == len(repos) {
+		return nil, fmt.Errorf("dependency graph is not available for any repository; it may be disabled or not included in your GitHub plan")
+	}
+	if unavailable > 0 {
+		util.Warn(fmt.Sprintf("Dependency graph not available for %d of %d repositories", unavailable, len(repos)))
+	}
+	return filtered, nil
+}
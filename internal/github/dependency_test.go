@@ -0,0 +1,108 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestPackageMatches(t *testing.T) {
+	tests := []struct {
+		name, pkg string
+		want      bool
+	}{
+		{"npm:lodash", "lodash", true},
+		{"npm:lodash", "npm:lodash", true},
+		{"npm:Lodash", "LODASH", true},
+		{"lodash", "lodash", true},
+		{"npm:lodash.merge", "lodash", false},
+		{"pip:requests", "npm:requests", false},
+		{"npm:lodash", "", false},
+	}
+	for _, tt := range tests {
+		if got := packageMatches(tt.name, tt.pkg); got != tt.want {
+			t.Errorf("packageMatches(%q, %q) = %v, want %v", tt.name, tt.pkg, got, tt.want)
+		}
+	}
+}
+
+// sbomHandler serves the dependency graph of each acme repository from packages,
+// answering with status instead for repositories listed there
+func sbomHandler(packages map[string][]string, status map[string]int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo, ok := strings.CutPrefix(r.URL.Path, "/repos/acme/")
+		repo, ok2 := strings.CutSuffix(repo, "/dependency-graph/sbom")
+		if !ok || !ok2 {
+			http.NotFound(w, r)
+			return
+		}
+		if code, ok := status[repo]; ok {
+			w.WriteHeader(code)
+			w.Write([]byte(`{"message":"unavailable"}`))
+			return
+		}
+		var names []string
+		for _, name := range packages[repo] {
+			names = append(names, fmt.Sprintf(`{"name":%q}`, name))
+		}
+		fmt.Fprintf(w, `{"sbom":{"packages":[%s]}}`, strings.Join(names, ","))
+	})
+}
+
+func TestFilterByDependency(t *testing.T) {
+	packages := map[string][]string{
+		"web":    {"npm:react", "npm:lodash"},
+		"api":    {"go:github.com/google/go-github/v60"},
+		"worker": {"npm:lodash"},
+	}
+	tests := []struct {
+		name    string
+		repos   []string
+		status  map[string]int
+		pkg     string
+		want    []string
+		wantErr string
+	}{
+		{"matching repositories", []string{"web", "api", "worker"}, nil, "lodash", []string{"web", "worker"}, ""},
+		{"with ecosystem", []string{"web", "api"}, nil, "go:github.com/google/go-github/v60", []string{"api"}, ""},
+		{"no match", []string{"web", "api"}, nil, "left-pad", []string{}, ""},
+		{"some unavailable", []string{"web", "legacy"}, map[string]int{"legacy": http.StatusForbidden}, "lodash", []string{"web"}, ""},
+		{"all unavailable", []string{"legacy", "old"}, map[string]int{"legacy": http.StatusForbidden, "old": http.StatusNotFound}, "lodash", nil, "not available for any repository"},
+		{"server error", []string{"web", "broken"}, map[string]int{"broken": http.StatusInternalServerError}, "lodash", nil, "failed to get dependency graph of acme/broken"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, sbomHandler(packages, tt.status))
+			var repos []*github.Repository
+			for _, name := range tt.repos {
+				repos = append(repos, &github.Repository{
+					Name:     github.String(name),
+					FullName: github.String("acme/" + name),
+					Owner:    &github.User{Login: github.String("acme")},
+				})
+			}
+
+			filtered, err := client.FilterByDependency(context.Background(), repos, tt.pkg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FilterByDependency() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, repo := range filtered {
+				got = append(got, repo.GetName())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FilterByDependency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
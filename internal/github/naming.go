@@ -0,0 +1,45 @@
+package github
+
+import (
+	"regexp"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// NamingViolation describes a repository whose name does not match the naming convention
+type NamingViolation struct {
+	Repository string `json:"repository" yaml:"repository"`
+	Name       string `json:"name" yaml:"name"`
+}
+
+// NamingReport is the result of checking an organization's repositories against a naming convention
+type NamingReport struct {
+	Organization string            `json:"organization" yaml:"organization"`
+	Pattern      string            `json:"pattern" yaml:"pattern"`
+	Total        int               `json:"total" yaml:"total"`
+	Compliant    int               `json:"compliant" yaml:"compliant"`
+	Violations   []NamingViolation `json:"violations" yaml:"violations"`
+}
+
+// CheckNamingConvention checks each repository name against the given pattern
+func CheckNamingConvention(org string, repos []*github.Repository, pattern *regexp.Regexp) *NamingReport {
+	report := &NamingReport{
+		Organization: org,
+		Pattern:      pattern.String(),
+		Total:        len(repos),
+		Violations:   []NamingViolation{},
+	}
+
+	for _, repo := range repos {
+		if pattern.MatchString(repo.GetName()) {
+			report.Compliant++
+			continue
+		}
+		report.Violations = append(report.Violations, NamingViolation{
+			Repository: repo.GetFullName(),
+			Name:       repo.GetName(),
+		})
+	}
+
+	return report
+}
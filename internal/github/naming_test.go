@@ -0,0 +1,55 @@
+package github
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestCheckNamingConvention(t *testing.T) {
+	pattern := regexp.MustCompile(`^[a-z]+-[a-z]+$`)
+	tests := []struct {
+		name       string
+		repos      []string
+		compliant  int
+		violations []string
+	}{
+		{"all compliant", []string{"team-api", "team-web"}, 2, nil},
+		{"all violating", []string{"API", "team_web"}, 0, []string{"API", "team_web"}},
+		{"mixed", []string{"team-api", "legacy", "ops-tools", "Team-Web"}, 2, []string{"legacy", "Team-Web"}},
+		{"no repositories", nil, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var repos []*github.Repository
+			for _, name := range tt.repos {
+				repos = append(repos, &github.Repository{
+					Name:     github.String(name),
+					FullName: github.String("acme/" + name),
+				})
+			}
+
+			report := CheckNamingConvention("acme", repos, pattern)
+			if report.Organization != "acme" || report.Pattern != pattern.String() {
+				t.Errorf("report = %s %q, want acme %q", report.Organization, report.Pattern, pattern)
+			}
+			if report.Total != len(tt.repos) || report.Compliant != tt.compliant {
+				t.Errorf("total/compliant = %d/%d, want %d/%d", report.Total, report.Compliant, len(tt.repos), tt.compliant)
+			}
+			// Violations is always a list so the report encodes [] rather than null
+			if report.Violations == nil {
+				t.Fatal("Violations is nil")
+			}
+			if len(report.Violations) != len(tt.violations) {
+				t.Fatalf("violations = %v, want %v", report.Violations, tt.violations)
+			}
+			for i, name := range tt.violations {
+				got := report.Violations[i]
+				if got.Name != name || got.Repository != "acme/"+name {
+					t.Errorf("violation %d = %+v, want %s", i, got, name)
+				}
+			}
+		})
+	}
+}
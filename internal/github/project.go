@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// ParseProjectRef parses an organization project reference. It accepts a project
+// URL (https://github.com/orgs/<org>/projects/<number>), "<org>/<number>", or a
+// bare number combined with defaultOrg.
+func ParseProjectRef(ref, defaultOrg string) (string, int, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", 0, fmt.Errorf("project reference cannot be empty")
+	}
+
+	org, number := defaultOrg, ref
+	if u, err := url.Parse(ref); err == nil && u.Host != "" {
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) != 4 || parts[0] != "orgs" || parts[2] != "projects" {
+			return "", 0, fmt.Errorf("invalid project URL %q", ref)
+		}
+		org, number = parts[1], parts[3]
+	} else if i := strings.LastIndex(ref, "/"); i >= 0 {
+		org, number = ref[:i], ref[i+1:]
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return "", 0, fmt.Errorf("invalid project number in %q", ref)
+	}
+	if org == "" {
+		return "", 0, fmt.Errorf("project %q has no organization. Use a project URL or --org", ref)
+	}
+	return org, n, nil
+}
+
+// projectItemsQuery lists a page of the items of an organization project
+// (Projects v2), with the repository of each linked issue or pull request
+const projectItemsQuery = `query($org: String!, $number: Int!, $first: Int!, $after: String) {
+  organization(login: $org) {
+    projectV2(number: $number) {
+      items(first: $first, after: $after) {
+        nodes {
+          content {
+            ... on Issue { repository { nameWithOwner } }
+            ... on PullRequest { repository { nameWithOwner } }
+          }
+        }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// projectItem is a project item; draft issues have no repository
+type projectItem struct {
+	Content *struct {
+		Repository *struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	} `json:"content"`
+}
+
+// projectItemsResponse is the GraphQL response to projectItemsQuery
+type projectItemsResponse struct {
+	Data struct {
+		Organization *struct {
+			ProjectV2 *struct {
+				Items struct {
+					Nodes    []projectItem `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"items"`
+			} `json:"projectV2"`
+		} `json:"organization"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// projectRepoNames maps project items to the sorted, de-duplicated full names of
+// the repositories they reference. Items may reference repositories in other organizations.
+func projectRepoNames(items []projectItem) []string {
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if item.Content != nil && item.Content.Repository != nil && item.Content.Repository.NameWithOwner != "" {
+			seen[item.Content.Repository.NameWithOwner] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// graphQLEndpoint returns the GraphQL API URL: api.github.com/graphql, or
+// /api/graphql on GitHub Enterprise Server, whose REST API is under /api/v3/
+func (c *Client) graphQLEndpoint() string {
+	base := c.client.BaseURL
+	if strings.HasSuffix(base.Path, "/api/v3/") {
+		return base.ResolveReference(&url.URL{Path: "../graphql"}).String()
+	}
+	return base.ResolveReference(&url.URL{Path: "graphql"}).String()
+}
+
+// listProjectItems fetches every item of an organization project, page by page
+func (c *Client) listProjectItems(ctx context.Context, org string, number int) ([]projectItem, error) {
+	var items []projectItem
+	var after *string
+	for {
+		if err := c.WaitForRateLimit(ctx); err != nil {
+			return nil, err
+		}
+		body := map[string]interface{}{
+			"query": projectItemsQuery,
+			"variables": map[string]interface{}{
+				"org":    org,
+				"number": number,
+				"first":  c.perPage(),
+				"after":  after,
+			},
+		}
+		req, err := c.client.NewRequest("POST", c.graphQLEndpoint(), body)
+		if err != nil {
+			return nil, err
+		}
+		// The GraphQL API has its own rate limit, so the core limit is not recorded
+		var page projectItemsResponse
+		if _, err := c.client.Do(ctx, req, &page); err != nil {
+			return nil, fmt.Errorf("failed to list items of project %d: %w", number, err)
+		}
+		if len(page.Errors) > 0 {
+			return nil, fmt.Errorf("failed to list items of project %d: %s", number, page.Errors[0].Message)
+		}
+		if page.Data.Organization == nil || page.Data.Organization.ProjectV2 == nil {
+			return nil, fmt.Errorf("project %d not found in organization %q", number, org)
+		}
+
+		found := page.Data.Organization.ProjectV2.Items
+		items = append(items, found.Nodes...)
+		if !found.PageInfo.HasNextPage {
+			return items, nil
+		}
+		cursor := found.PageInfo.EndCursor
+		after = &cursor
+	}
+}
+
+// ListProjectRepositories resolves an organization project to the repositories its items link to
+func (c *Client) ListProjectRepositories(ctx context.Context, org string, number int) ([]*github.Repository, error) {
+	if err := c.checkOrgAllowed(org); err != nil {
+		return nil, err
+	}
+
+	items, err := c.listProjectItems(ctx, org, number)
+	if err != nil {
+		return nil, err
+	}
+
+	names := projectRepoNames(items)
+	util.Info(fmt.Sprintf("Project %s/%d links %d repositories", org, number, len(names)))
+
+	repos := make([]*github.Repository, 0, len(names))
+	for _, name := range names {
+		owner, repo, _ := strings.Cut(name, "/")
+		if !IsOrgAllowed(c.allowedOrgs, owner) {
+			util.Warn(fmt.Sprintf("Skipping project repository %s: organization not in security.allowed_orgs", name))
+			continue
+		}
+		repository, err := c.GetRepository(ctx, owner, repo)
+		if err != nil {
+			util.Warn(fmt.Sprintf("Skipping project repository %s: %v", name, err))
+			continue
+		}
+		repos = append(repos, repository)
+	}
+	return repos, nil
+}
@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestParseProjectRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		ref        string
+		defaultOrg string
+		wantOrg    string
+		wantNumber int
+		wantErr    bool
+	}{
+		{"url", "https://github.com/orgs/acme/projects/7", "", "acme", 7, false},
+		{"url overrides default org", "https://github.com/orgs/acme/projects/7/", "other", "acme", 7, false},
+		{"org and number", "acme/12", "", "acme", 12, false},
+		{"bare number", "3", "acme", "acme", 3, false},
+		{"bare number without org", "3", "", "", 0, true},
+		{"empty", " ", "acme", "", 0, true},
+		{"user project url", "https://github.com/users/alice/projects/1", "", "", 0, true},
+		{"not a number", "acme/roadmap", "", "", 0, true},
+		{"zero", "acme/0", "", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org, number, err := ParseProjectRef(tt.ref, tt.defaultOrg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProjectRef(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if org != tt.wantOrg || number != tt.wantNumber {
+				t.Errorf("ParseProjectRef(%q) = %q, %d, want %q, %d", tt.ref, org, number, tt.wantOrg, tt.wantNumber)
+			}
+		})
+	}
+}
+
+func TestProjectRepoNames(t *testing.T) {
+	decode := func(t *testing.T, nodes string) []projectItem {
+		t.Helper()
+		var items []projectItem
+		if err := json.Unmarshal([]byte(nodes), &items); err != nil {
+			t.Fatal(err)
+		}
+		return items
+	}
+	tests := []struct {
+		name  string
+		nodes string
+		want  []string
+	}{
+		{"no items", `[]`, []string{}},
+		{
+			"issues and pull requests",
+			`[{"content":{"repository":{"nameWithOwner":"acme/web"}}},{"content":{"repository":{"nameWithOwner":"acme/api"}}}]`,
+			[]string{"acme/api", "acme/web"},
+		},
+		{
+			"duplicates collapse",
+			`[{"content":{"repository":{"nameWithOwner":"acme/api"}}},{"content":{"repository":{"nameWithOwner":"acme/api"}}}]`,
+			[]string{"acme/api"},
+		},
+		{
+			"spans organizations",
+			`[{"content":{"repository":{"nameWithOwner":"acme/api"}}},{"content":{"repository":{"nameWithOwner":"partner/sdk"}}}]`,
+			[]string{"acme/api", "partner/sdk"},
+		},
+		{
+			"draft issues and redacted items",
+			`[{"content":{}},{"content":null},{"content":{"repository":{"nameWithOwner":"acme/tools"}}}]`,
+			[]string{"acme/tools"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := projectRepoNames(decode(t, tt.nodes))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("projectRepoNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphQLEndpoint(t *testing.T) {
+	tests := []struct {
+		base, want string
+	}{
+		{"https://api.github.com/", "https://api.github.com/graphql"},
+		{"https://ghe.example.com/api/v3/", "https://ghe.example.com/api/graphql"},
+	}
+	for _, tt := range tests {
+		client := github.NewClient(nil)
+		client.BaseURL, _ = url.Parse(tt.base)
+		c := &Client{client: client}
+		if got := c.graphQLEndpoint(); got != tt.want {
+			t.Errorf("graphQLEndpoint() for %s = %s, want %s", tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestListProjectRepositories(t *testing.T) {
+	// Project 2 spans two pages of items; project 3 does not exist
+	pages := map[string]string{
+		"": `{"nodes":[{"content":{"repository":{"nameWithOwner":"acme/api"}}},{"content":{}}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}`,
+		"c1": `{"nodes":[{"content":{"repository":{"nameWithOwner":"partner/sdk"}}},{"content":{"repository":{"nameWithOwner":"acme/gone"}}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}`,
+	}
+	api := http.NewServeMux()
+	api.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				Org    string  `json:"org"`
+				Number int     `json:"number"`
+				First  int     `json:"first"`
+				After  *string `json:"after"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Variables.Org != "acme" || req.Variables.First != MaxPageSize {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if req.Variables.Number != 2 {
+			fmt.Fprint(w, `{"data":{"organization":{"projectV2":null}},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a ProjectV2 with the number 3."}]}`)
+			return
+		}
+		after := ""
+		if req.Variables.After != nil {
+			after = *req.Variables.After
+		}
+		fmt.Fprintf(w, `{"data":{"organization":{"projectV2":{"items":%s}}}}`, pages[after])
+	})
+	for _, name := range []string{"acme/api", "partner/sdk"} {
+		name := name
+		api.HandleFunc("/repos/"+name, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"full_name":%q}`, name)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		allowed []string
+		number  int
+		want    []string
+		wantErr bool
+	}{
+		{"linked repositories", nil, 2, []string{"acme/api", "partner/sdk"}, false},
+		{"allowlist drops other organizations", []string{"acme"}, 2, []string{"acme/api"}, false},
+		{"project organization not allowed", []string{"partner"}, 2, nil, true},
+		{"unknown project", nil, 3, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, api)
+			client.SetAllowedOrgs(tt.allowed)
+			repos, err := client.ListProjectRepositories(context.Background(), "acme", tt.number)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListProjectRepositories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, repo := range repos {
+				got = append(got, repo.GetFullName())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("repositories = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/internal/auth"
+)
+
+// NewUnauthenticatedClient creates a client for public API calls that do not need a token
+func NewUnauthenticatedClient() *Client {
+	return &Client{
+		client: github.NewClient(nil),
+	}
+}
+
+// GetLatestRelease gets the latest published release of a repository
+func (c *Client) GetLatestRelease(ctx context.Context, owner, repo string) (*github.RepositoryRelease, error) {
+	release, resp, err := c.client.Repositories.GetLatestRelease(ctx, owner, repo)
+	c.recordRate(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest release of %s/%s: %w", owner, repo, err)
+	}
+
+	return release, nil
+}
+
+// ReleaseInfo describes the latest release of a repository
+type ReleaseInfo struct {
+	Repository  string     `json:"repository" yaml:"repository"`
+	Tag         string     `json:"tag,omitempty" yaml:"tag,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty" yaml:"published_at,omitempty"`
+	Error       string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// HasRelease reports whether the repository has a published release
+func (r ReleaseInfo) HasRelease() bool {
+	return r.Tag != ""
+}
+
+// latestReleaseInfo looks up the latest release of a repository; a repository
+// without releases yields an empty ReleaseInfo rather than an error
+func (c *Client) latestReleaseInfo(ctx context.Context, repo *github.Repository) ReleaseInfo {
+	info := ReleaseInfo{Repository: repo.GetFullName()}
+	if err := c.WaitForRateLimit(ctx); err != nil {
+		info.Error = err.Error()
+		return info
+	}
+
+	release, resp, err := c.client.Repositories.GetLatestRelease(ctx, repo.GetOwner().GetLogin(), repo.GetName())
+	c.recordRate(resp)
+	if err != nil {
+		if resp == nil || resp.StatusCode != 404 {
+			info.Error = fmt.Sprintf("failed to get latest release: %v", auth.CheckSSO(resp, err))
+		}
+		return info
+	}
+
+	info.Tag = release.GetTagName()
+	if release.PublishedAt != nil {
+		published := release.GetPublishedAt().Time
+		info.PublishedAt = &published
+	}
+	return info
+}
+
+// LatestReleases looks up the latest release of each repository with at most
+// concurrency requests in flight, returning results in the order of repos
+func (c *Client) LatestReleases(ctx context.Context, repos []*github.Repository, concurrency int) []ReleaseInfo {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	results := make([]ReleaseInfo, len(repos))
+
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, repo *github.Repository) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = c.latestReleaseInfo(ctx, repo)
+		}(i, repo)
+	}
+	wg.Wait()
+
+	return results
+}
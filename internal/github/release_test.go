@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestLatestReleases(t *testing.T) {
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+		// Hold each request briefly so concurrent lookups overlap
+		time.Sleep(10 * time.Millisecond)
+
+		switch r.URL.Path {
+		case "/repos/acme/api/releases/latest":
+			w.Write([]byte(`{"tag_name":"v2.1.0","published_at":"2026-09-01T12:00:00Z"}`))
+		case "/repos/acme/web/releases/latest":
+			w.Write([]byte(`{"tag_name":"v0.3.0"}`))
+		case "/repos/acme/broken/releases/latest":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	names := []string{"api", "tools", "web", "broken", "docs"}
+	var repos []*github.Repository
+	for _, name := range names {
+		repos = append(repos, &github.Repository{
+			Name:     github.String(name),
+			FullName: github.String("acme/" + name),
+			Owner:    &github.User{Login: github.String("acme")},
+		})
+	}
+	results := client.LatestReleases(context.Background(), repos, 2)
+
+	published := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		repo      string
+		tag       string
+		published *time.Time
+		wantErr   bool
+	}{
+		{"acme/api", "v2.1.0", &published, false},
+		{"acme/tools", "", nil, false},
+		{"acme/web", "v0.3.0", nil, false},
+		{"acme/broken", "", nil, true},
+		{"acme/docs", "", nil, false},
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("got %d results, want %d", len(results), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			got := results[i]
+			if got.Repository != tt.repo || got.Tag != tt.tag || got.HasRelease() != (tt.tag != "") {
+				t.Errorf("result %d = %+v, want %s at %q", i, got, tt.repo, tt.tag)
+			}
+			if (got.PublishedAt == nil) != (tt.published == nil) || (got.PublishedAt != nil && !got.PublishedAt.Equal(*tt.published)) {
+				t.Errorf("PublishedAt = %v, want %v", got.PublishedAt, tt.published)
+			}
+			// A repository without releases is not an error
+			if (got.Error != "") != tt.wantErr {
+				t.Errorf("Error = %q, wantErr %v", got.Error, tt.wantErr)
+			}
+		})
+	}
+	if maxActive > 2 {
+		t.Errorf("%d lookups ran concurrently, want at most 2", maxActive)
+	}
+}
+
+func TestGetLatestReleaseNotFound(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+	_, err := client.GetLatestRelease(context.Background(), "sachin-duhan", "zikrr")
+	if err == nil || !strings.Contains(err.Error(), "failed to get latest release of sachin-duhan/zikrr") {
+		t.Errorf("GetLatestRelease() = %v, want a wrapped not found error", err)
+	}
+}
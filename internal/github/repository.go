@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,12 +14,22 @@ import (
 type RepositoryFilter struct {
 	Visibility   string    // public, private, or all
 	Topics       []string  // required topics
-	UpdatedAfter time.Time // filter by last update time
+	UpdatedAfter time.Time // filter by last update time, including metadata edits
+	PushedAfter  time.Time // filter by last push
 	MinSize      int       // minimum size in KB
 	MaxSize      int       // maximum size in KB
+	MinStars     int       // minimum stargazers
+	MaxStars     int       // maximum stargazers
 	Language     string    // primary language
 	Archived     *bool     // filter archived repositories
 	Fork         *bool     // filter forked repositories
+	Template     *bool     // filter template repositories
+
+	// NamePatterns keeps only repositories whose name or full name matches one
+	// of these filepath.Match globs; ExcludePatterns drops matches and wins over
+	// NamePatterns
+	NamePatterns    []string
+	ExcludePatterns []string
 }
 
 // FilterRepositories filters a list of repositories based on the given criteria
@@ -67,6 +78,13 @@ func matchesFilter(repo *github.Repository, filter *RepositoryFilter) bool {
 		}
 	}
 
+	// Check push time
+	if !filter.PushedAfter.IsZero() {
+		if repo.GetPushedAt().Time.Before(filter.PushedAfter) {
+			return false
+		}
+	}
+
 	// Check size
 	size := repo.GetSize()
 	if filter.MinSize > 0 && size < filter.MinSize {
@@ -76,6 +94,15 @@ func matchesFilter(repo *github.Repository, filter *RepositoryFilter) bool {
 		return false
 	}
 
+	// Check stars
+	stars := repo.GetStargazersCount()
+	if filter.MinStars > 0 && stars < filter.MinStars {
+		return false
+	}
+	if filter.MaxStars > 0 && stars > filter.MaxStars {
+		return false
+	}
+
 	// Check language
 	if filter.Language != "" {
 		if !strings.EqualFold(repo.GetLanguage(), filter.Language) {
@@ -97,9 +124,48 @@ func matchesFilter(repo *github.Repository, filter *RepositoryFilter) bool {
 		}
 	}
 
+	// Check template status
+	if filter.Template != nil {
+		if repo.GetIsTemplate() != *filter.Template {
+			return false
+		}
+	}
+
+	// Check name patterns; excludes take precedence
+	if matchesAnyPattern(repo, filter.ExcludePatterns) {
+		return false
+	}
+	if len(filter.NamePatterns) > 0 && !matchesAnyPattern(repo, filter.NamePatterns) {
+		return false
+	}
+
 	return true
 }
 
+// ValidatePatterns returns an error for the first malformed name or exclude pattern
+func (f *RepositoryFilter) ValidatePatterns() error {
+	for _, pattern := range append(append([]string{}, f.NamePatterns...), f.ExcludePatterns...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid repository name pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchesAnyPattern reports whether the repository name or full name matches
+// any of the filepath.Match patterns. Malformed patterns never match.
+func matchesAnyPattern(repo *github.Repository, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, repo.GetName()); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, repo.GetFullName()); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // hasAllTopics checks if a repository has all required topics
 func hasAllTopics(repoTopics []string, requiredTopics []string) bool {
 	if len(requiredTopics) == 0 {
@@ -139,7 +205,7 @@ func (c *Client) GetRepositoryInfo(ctx context.Context, owner, repo string) (*Re
 	branches, err := c.ListBranches(ctx, owner, repo, &github.BranchListOptions{
 		Protected: nil,
 		ListOptions: github.ListOptions{
-			PerPage: 100,
+			PerPage: c.perPage(),
 		},
 	})
 	if err != nil {
@@ -154,9 +220,15 @@ func (c *Client) GetRepositoryInfo(ctx context.Context, owner, repo string) (*Re
 
 // ListFilteredRepositories lists repositories in an organization with filtering
 func (c *Client) ListFilteredRepositories(ctx context.Context, org string, filter *RepositoryFilter) ([]*github.Repository, error) {
+	return c.ListFilteredRepositoriesPaged(ctx, org, filter, nil)
+}
+
+// ListFilteredRepositoriesPaged is like ListFilteredRepositories but also calls
+// onPage with the matching repositories of each page as soon as it arrives
+func (c *Client) ListFilteredRepositoriesPaged(ctx context.Context, org string, filter *RepositoryFilter, onPage func([]*github.Repository)) ([]*github.Repository, error) {
 	opts := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{
-			PerPage: 100,
+			PerPage: c.perPage(),
 		},
 	}
 
@@ -165,10 +237,88 @@ func (c *Client) ListFilteredRepositories(ctx context.Context, org string, filte
 		opts.Type = filter.Visibility
 	}
 
-	repos, err := c.ListOrganizationRepos(ctx, org, opts)
+	var pageFunc func([]*github.Repository)
+	if onPage != nil {
+		pageFunc = func(page []*github.Repository) {
+			if matched := FilterRepositories(page, filter); len(matched) > 0 {
+				onPage(matched)
+			}
+		}
+	}
+
+	repos, err := c.ListOrganizationReposPaged(ctx, org, opts, pageFunc)
 	if err != nil {
-		return nil, err
+		return FilterRepositories(repos, filter), err
 	}
 
 	return FilterRepositories(repos, filter), nil
 }
+
+// ListFilteredUserRepositories lists a user's repositories matching the filter
+func (c *Client) ListFilteredUserRepositories(ctx context.Context, user string, filter *RepositoryFilter) ([]*github.Repository, error) {
+	repos, err := c.ListUserRepos(ctx, user, nil)
+	return FilterRepositories(repos, filter), err
+}
+
+// ListFilteredRepositoriesMulti lists the filtered repositories of several
+// organizations in order, calling onPage (if set) as each page arrives. A
+// repository seen more than once, e.g. from an organization given twice, is
+// kept only the first time.
+func (c *Client) ListFilteredRepositoriesMulti(ctx context.Context, orgs []string, filter *RepositoryFilter, onPage func([]*github.Repository)) ([]*github.Repository, error) {
+	seen := make(map[string]bool)
+	var merged []*github.Repository
+	for _, org := range orgs {
+		_, err := c.ListFilteredRepositoriesPaged(ctx, org, filter, func(page []*github.Repository) {
+			unique := DedupeRepositories(page, seen)
+			merged = append(merged, unique...)
+			if onPage != nil && len(unique) > 0 {
+				onPage(unique)
+			}
+		})
+		if err != nil {
+			return merged, fmt.Errorf("failed to list repositories of %s: %w", org, err)
+		}
+	}
+	return merged, nil
+}
+
+// DedupeRepositories returns the repositories not yet in seen, by ID or
+// case-insensitive full name, and records them in seen
+func DedupeRepositories(repos []*github.Repository, seen map[string]bool) []*github.Repository {
+	var unique []*github.Repository
+	for _, repo := range repos {
+		name := "name:" + strings.ToLower(repo.GetFullName())
+		id := fmt.Sprintf("id:%d", repo.GetID())
+		if seen[name] || (repo.GetID() != 0 && seen[id]) {
+			continue
+		}
+		seen[name] = true
+		if repo.GetID() != 0 {
+			seen[id] = true
+		}
+		unique = append(unique, repo)
+	}
+	return unique
+}
+
+// CloneURL returns the URL to clone a repository with: its SSH URL when ssh is set, HTTPS otherwise
+func CloneURL(repo *github.Repository, ssh bool) string {
+	if ssh && repo.GetSSHURL() != "" {
+		return repo.GetSSHURL()
+	}
+	return repo.GetCloneURL()
+}
+
+// ParseOrganizations splits comma-separated and repeated organization values
+// into a list of names, dropping blanks
+func ParseOrganizations(values []string) []string {
+	var orgs []string
+	for _, value := range values {
+		for _, org := range strings.Split(value, ",") {
+			if org = strings.TrimSpace(org); org != "" {
+				orgs = append(orgs, org)
+			}
+		}
+	}
+	return orgs
+}
@@ -0,0 +1,262 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// filterNames returns the names of the repositories that pass filter
+func filterNames(repos []*github.Repository, filter *RepositoryFilter) string {
+	var names []string
+	for _, repo := range FilterRepositories(repos, filter) {
+		names = append(names, repo.GetName())
+	}
+	return strings.Join(names, ",")
+}
+
+func TestTemplateFilter(t *testing.T) {
+	repos := []*github.Repository{
+		{Name: github.String("app")},
+		{Name: github.String("service-template"), IsTemplate: github.Bool(true)},
+		{Name: github.String("web"), IsTemplate: github.Bool(false)},
+	}
+	tests := []struct {
+		name     string
+		template *bool
+		want     string
+	}{
+		{"include templates", nil, "app,service-template,web"},
+		{"exclude templates", github.Bool(false), "app,web"},
+		{"only templates", github.Bool(true), "service-template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterNames(repos, &RepositoryFilter{Template: tt.template}); got != tt.want {
+				t.Errorf("FilterRepositories() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamePatterns(t *testing.T) {
+	var repos []*github.Repository
+	for _, name := range []string{"api", "api-archive", "legacy-api", "legacy-web-archive", "web"} {
+		repos = append(repos, &github.Repository{Name: github.String(name), FullName: github.String("acme/" + name)})
+	}
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    string
+	}{
+		{"no patterns", nil, nil, "api,api-archive,legacy-api,legacy-web-archive,web"},
+		{"include", []string{"api*"}, nil, "api,api-archive"},
+		{"include full name", []string{"acme/legacy-*"}, nil, "legacy-api,legacy-web-archive"},
+		{"several includes", []string{"web", "legacy-*"}, nil, "legacy-api,legacy-web-archive,web"},
+		{"exclude", nil, []string{"*-archive", "legacy-*"}, "api,web"},
+		{"exclude full name", nil, []string{"acme/api*"}, "legacy-api,legacy-web-archive,web"},
+		{"exclude wins over include", []string{"legacy-*"}, []string{"*-archive"}, "legacy-api"},
+		{"exclude everything included", []string{"api*"}, []string{"api*"}, ""},
+		{"no include matches", []string{"mobile-*"}, nil, ""},
+		{"malformed never matches", []string{"["}, []string{"["}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &RepositoryFilter{NamePatterns: tt.include, ExcludePatterns: tt.exclude}
+			if got := filterNames(repos, filter); got != tt.want {
+				t.Errorf("FilterRepositories() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  RepositoryFilter
+		wantErr bool
+	}{
+		{"none", RepositoryFilter{}, false},
+		{"valid", RepositoryFilter{NamePatterns: []string{"api-*", "acme/[a-c]*"}, ExcludePatterns: []string{"*-archive"}}, false},
+		{"malformed include", RepositoryFilter{NamePatterns: []string{"api-["}}, true},
+		{"malformed exclude", RepositoryFilter{ExcludePatterns: []string{"ok", "[z-"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.ValidatePatterns(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePatterns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseOrganizations(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"acme"}, "acme"},
+		{[]string{"acme,widgets, tools"}, "acme widgets tools"},
+		{[]string{"acme", "widgets,tools"}, "acme widgets tools"},
+		{[]string{" , acme,,"}, "acme"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ParseOrganizations(tt.values), " "); got != tt.want {
+			t.Errorf("ParseOrganizations(%q) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestDedupeRepositories(t *testing.T) {
+	repo := func(id int64, fullName string) *github.Repository {
+		return &github.Repository{ID: github.Int64(id), FullName: github.String(fullName)}
+	}
+	tests := []struct {
+		name  string
+		pages [][]*github.Repository
+		want  string
+	}{
+		{"distinct", [][]*github.Repository{{repo(1, "acme/api")}, {repo(2, "widgets/ui")}}, "acme/api widgets/ui"},
+		{"same full name", [][]*github.Repository{{repo(1, "acme/api")}, {repo(0, "ACME/api")}}, "acme/api"},
+		{"same id under another owner", [][]*github.Repository{{repo(1, "acme/api")}, {repo(1, "widgets/api")}}, "acme/api"},
+		{"fork with its own id", [][]*github.Repository{{repo(1, "acme/api")}, {repo(2, "widgets/api")}}, "acme/api widgets/api"},
+		{"no ids", [][]*github.Repository{{repo(0, "acme/api"), repo(0, "acme/web")}, {repo(0, "acme/web")}}, "acme/api acme/web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[string]bool)
+			var names []string
+			for _, page := range tt.pages {
+				for _, r := range DedupeRepositories(page, seen) {
+					names = append(names, r.GetFullName())
+				}
+			}
+			if got := strings.Join(names, " "); got != tt.want {
+				t.Errorf("DedupeRepositories() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListFilteredRepositoriesMulti(t *testing.T) {
+	listings := map[string]string{
+		"/orgs/acme/repos":    `[{"id":1,"name":"api","full_name":"acme/api"},{"id":2,"name":"web","full_name":"acme/web","archived":true}]`,
+		"/orgs/widgets/repos": `[{"id":3,"name":"ui","full_name":"widgets/ui"},{"id":1,"name":"api","full_name":"acme/api"}]`,
+	}
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listing, ok := listings[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(listing))
+	})
+
+	tests := []struct {
+		name    string
+		orgs    []string
+		filter  *RepositoryFilter
+		want    string
+		wantErr string
+	}{
+		{"merged in order", []string{"acme", "widgets"}, &RepositoryFilter{}, "acme/api acme/web widgets/ui", ""},
+		{"organization given twice", []string{"acme", "acme"}, &RepositoryFilter{}, "acme/api acme/web", ""},
+		{"filtered", []string{"acme", "widgets"}, &RepositoryFilter{Archived: github.Bool(false)}, "acme/api widgets/ui", ""},
+		{"failing organization keeps earlier results", []string{"acme", "missing"}, &RepositoryFilter{}, "acme/api acme/web", "failed to list repositories of missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, api)
+			var paged []string
+			repos, err := client.ListFilteredRepositoriesMulti(context.Background(), tt.orgs, tt.filter, func(page []*github.Repository) {
+				for _, r := range page {
+					paged = append(paged, r.GetFullName())
+				}
+			})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ListFilteredRepositoriesMulti() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ListFilteredRepositoriesMulti() error = %v, want %q", err, tt.wantErr)
+			}
+			var names []string
+			for _, r := range repos {
+				names = append(names, r.GetFullName())
+			}
+			if got := strings.Join(names, " "); got != tt.want {
+				t.Errorf("repositories = %q, want %q", got, tt.want)
+			}
+			if got := strings.Join(paged, " "); got != tt.want {
+				t.Errorf("pages delivered %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushedAfterFilter(t *testing.T) {
+	cutoff := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := &github.Timestamp{Time: cutoff.AddDate(0, -1, 0)}
+	after := &github.Timestamp{Time: cutoff.AddDate(0, 1, 0)}
+	repos := []*github.Repository{
+		{Name: github.String("active"), UpdatedAt: after, PushedAt: after},
+		{Name: github.String("renamed"), UpdatedAt: after, PushedAt: before},
+		{Name: github.String("pushed"), UpdatedAt: before, PushedAt: after},
+		{Name: github.String("stale"), UpdatedAt: before, PushedAt: before},
+		{Name: github.String("never-pushed"), UpdatedAt: after},
+	}
+	tests := []struct {
+		name         string
+		updatedAfter time.Time
+		pushedAfter  time.Time
+		want         string
+	}{
+		{"no dates", time.Time{}, time.Time{}, "active,renamed,pushed,stale,never-pushed"},
+		{"updated after", cutoff, time.Time{}, "active,renamed,never-pushed"},
+		{"pushed after", time.Time{}, cutoff, "active,pushed"},
+		{"both", cutoff, cutoff, "active"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &RepositoryFilter{UpdatedAfter: tt.updatedAfter, PushedAfter: tt.pushedAfter}
+			if got := filterNames(repos, filter); got != tt.want {
+				t.Errorf("FilterRepositories() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStarsFilter(t *testing.T) {
+	repos := []*github.Repository{
+		{Name: github.String("unstarred")},
+		{Name: github.String("ten"), StargazersCount: github.Int(10)},
+		{Name: github.String("hundred"), StargazersCount: github.Int(100)},
+		{Name: github.String("popular"), StargazersCount: github.Int(5000)},
+	}
+	tests := []struct {
+		name     string
+		min, max int
+		want     string
+	}{
+		{"unbounded", 0, 0, "unstarred,ten,hundred,popular"},
+		{"minimum is inclusive", 10, 0, "ten,hundred,popular"},
+		{"minimum just above", 11, 0, "hundred,popular"},
+		{"maximum is inclusive", 0, 100, "unstarred,ten,hundred"},
+		{"maximum just below", 0, 99, "unstarred,ten"},
+		{"range", 10, 100, "ten,hundred"},
+		{"empty range", 101, 99, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterNames(repos, &RepositoryFilter{MinStars: tt.min, MaxStars: tt.max}); got != tt.want {
+				t.Errorf("FilterRepositories() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
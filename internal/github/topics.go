@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/sachin-duhan/zikrr/pkg/util"
+)
+
+// MergeTopics adds topic to the existing topics, preserving them. It reports
+// whether the topic was added; GitHub topics are lowercase, so matching is case-insensitive.
+func MergeTopics(existing []string, topic string) ([]string, bool) {
+	topic = strings.ToLower(strings.TrimSpace(topic))
+	for _, t := range existing {
+		if strings.EqualFold(t, topic) {
+			return existing, false
+		}
+	}
+
+	merged := make([]string, 0, len(existing)+1)
+	merged = append(merged, existing...)
+	return append(merged, topic), true
+}
+
+// HasAdminPermission reports whether the authenticated user administers the repository
+func HasAdminPermission(repo *github.Repository) bool {
+	return repo.GetPermissions()["admin"]
+}
+
+// AddTopic adds a topic to a repository on GitHub, keeping its existing topics.
+// The topics are read again right before the update so that topics added
+// since the repository was listed are not lost. It requires admin permission
+// on the repository.
+func (c *Client) AddTopic(ctx context.Context, repo *github.Repository, topic string) error {
+	if !HasAdminPermission(repo) {
+		return fmt.Errorf("admin permission required to change topics of %s", repo.GetFullName())
+	}
+
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	if err := c.WaitForRateLimit(ctx); err != nil {
+		return err
+	}
+	current, resp, err := c.client.Repositories.ListAllTopics(ctx, owner, name)
+	c.recordRate(resp)
+	if err != nil {
+		return fmt.Errorf("failed to list topics of %s: %w", repo.GetFullName(), err)
+	}
+	repo.Topics = current
+
+	topics, changed := MergeTopics(current, topic)
+	if !changed {
+		util.Debug(fmt.Sprintf("Repository %s already has topic %q", repo.GetFullName(), topic))
+		return nil
+	}
+
+	if err := c.WaitForRateLimit(ctx); err != nil {
+		return err
+	}
+
+	updated, resp, err := c.client.Repositories.ReplaceAllTopics(ctx, owner, name, topics)
+	c.recordRate(resp)
+	if err != nil {
+		return fmt.Errorf("failed to update topics of %s: %w", repo.GetFullName(), err)
+	}
+	repo.Topics = updated
+	util.Info(fmt.Sprintf("Added topic %q to %s", topic, repo.GetFullName()))
+	return nil
+}
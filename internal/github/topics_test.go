@@ -0,0 +1,102 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func TestMergeTopics(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		topic    string
+		want     []string
+		changed  bool
+	}{
+		{"no topics", nil, "mirrored", []string{"mirrored"}, true},
+		{"keeps existing", []string{"go", "cli"}, "mirrored", []string{"go", "cli", "mirrored"}, true},
+		{"already present", []string{"go", "mirrored"}, "mirrored", []string{"go", "mirrored"}, false},
+		{"case insensitive", []string{"Mirrored"}, "mirrored", []string{"Mirrored"}, false},
+		{"normalized", []string{"go"}, " Mirrored ", []string{"go", "mirrored"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := append([]string(nil), tt.existing...)
+			got, changed := MergeTopics(existing, tt.topic)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") || changed != tt.changed {
+				t.Errorf("MergeTopics(%v, %q) = %v, %v, want %v, %v", tt.existing, tt.topic, got, changed, tt.want, tt.changed)
+			}
+			if strings.Join(existing, ",") != strings.Join(tt.existing, ",") {
+				t.Errorf("MergeTopics modified the existing topics to %v", existing)
+			}
+		})
+	}
+}
+
+func TestAddTopic(t *testing.T) {
+	tests := []struct {
+		name  string
+		admin bool
+		// listed are the topics from the listing, current the topics GitHub has now
+		listed   []string
+		current  []string
+		wantSent []string // nil when no update is made
+		wantErr  string
+	}{
+		{"adds topic", true, []string{"go"}, []string{"go"}, []string{"go", "mirrored"}, ""},
+		{"already tagged", true, []string{"mirrored"}, []string{"mirrored"}, nil, ""},
+		{"stale listing keeps new topics", true, []string{"go"}, []string{"go", "cli"}, []string{"go", "cli", "mirrored"}, ""},
+		{"tagged since listing", true, []string{"go"}, []string{"go", "mirrored"}, nil, ""},
+		{"not an admin", false, []string{"go"}, []string{"go"}, nil, "admin permission required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/repos/acme/app/topics" {
+					http.NotFound(w, r)
+					return
+				}
+				if r.Method == http.MethodGet {
+					json.NewEncoder(w).Encode(map[string][]string{"names": tt.current})
+					return
+				}
+				var body struct {
+					Names []string `json:"names"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				sent = body.Names
+				json.NewEncoder(w).Encode(body)
+			}))
+			repo := &github.Repository{
+				Name:        github.String("app"),
+				FullName:    github.String("acme/app"),
+				Owner:       &github.User{Login: github.String("acme")},
+				Topics:      tt.listed,
+				Permissions: map[string]bool{"admin": tt.admin},
+			}
+
+			err := client.AddTopic(context.Background(), repo, "mirrored")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("AddTopic() = %v, want an error containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(sent, ",") != strings.Join(tt.wantSent, ",") {
+				t.Errorf("sent topics %v, want %v", sent, tt.wantSent)
+			}
+			if tt.wantSent != nil && strings.Join(repo.Topics, ",") != strings.Join(tt.wantSent, ",") {
+				t.Errorf("repository topics = %v, want %v", repo.Topics, tt.wantSent)
+			}
+		})
+	}
+}
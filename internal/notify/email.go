@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+// EmailBody renders a clone summary as a plain-text email body
+func EmailBody(summary *git.CloneSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Zikrr clone run finished\n\n")
+	fmt.Fprintf(&b, "Total:     %d\n", summary.Total)
+	fmt.Fprintf(&b, "Succeeded: %d\n", summary.Succeeded)
+	fmt.Fprintf(&b, "Skipped:   %d\n", summary.Skipped)
+	fmt.Fprintf(&b, "Failed:    %d\n", summary.Failed)
+	if summary.Empty > 0 {
+		fmt.Fprintf(&b, "Empty:     %d\n", summary.Empty)
+	}
+
+	if len(summary.Failures) > 0 {
+		fmt.Fprintf(&b, "\nFailures:\n")
+		for _, f := range summary.Failures {
+			fmt.Fprintf(&b, "  - %s: %s\n", f.Repository, f.Error)
+		}
+	}
+	return b.String()
+}
+
+// SendEmail emails the clone summary using the configured SMTP server
+func SendEmail(cfg config.SMTPConfig, summary *git.CloneSummary) error {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return fmt.Errorf("smtp notification requires host, from and to")
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	subject := fmt.Sprintf("zikrr: %d succeeded, %d failed", summary.Succeeded, summary.Failed)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(EmailBody(summary), "\n", "\r\n"))
+
+	if err := smtp.SendMail(addr, auth, cfg.From, cfg.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send summary email: %w", err)
+	}
+	return nil
+}
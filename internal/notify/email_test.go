@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sachin-duhan/zikrr/internal/config"
+	"github.com/sachin-duhan/zikrr/internal/git"
+)
+
+// fakeSMTP is a minimal SMTP server accepting a single message per connection
+type fakeSMTP struct {
+	host string
+	port int
+	// rejectRcpt makes the server refuse every recipient
+	rejectRcpt bool
+	messages   chan smtpMessage
+}
+
+// smtpMessage is a message received by fakeSMTP
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTP(t *testing.T, rejectRcpt bool) *fakeSMTP {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	s := &fakeSMTP{host: host, rejectRcpt: rejectRcpt, messages: make(chan smtpMessage, 1)}
+	s.port, _ = strconv.Atoi(port)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var msg smtpMessage
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if s.rejectRcpt {
+				reply("550 no such user")
+				continue
+			}
+			msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			msg.data = data.String()
+			s.messages <- msg
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func testSummary() *git.CloneSummary {
+	return &git.CloneSummary{
+		Total:     4,
+		Succeeded: 2,
+		Skipped:   1,
+		Failed:    1,
+		Empty:     1,
+		Failures:  []git.CloneFailure{{Repository: "acme/broken", Error: "repository not found"}},
+	}
+}
+
+func TestEmailBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary *git.CloneSummary
+		want    []string
+		notWant []string
+	}{
+		{
+			"with failures",
+			testSummary(),
+			[]string{"Total:     4", "Succeeded: 2", "Skipped:   1", "Failed:    1", "Empty:     1", "Failures:", "  - acme/broken: repository not found"},
+			nil,
+		},
+		{
+			"all succeeded",
+			&git.CloneSummary{Total: 2, Succeeded: 2},
+			[]string{"Total:     2", "Succeeded: 2", "Failed:    0"},
+			[]string{"Empty:", "Failures:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := EmailBody(tt.summary)
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body missing %q:\n%s", want, body)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("body contains %q:\n%s", notWant, body)
+				}
+			}
+		})
+	}
+}
+
+func TestSendEmail(t *testing.T) {
+	server := newFakeSMTP(t, false)
+	cfg := config.SMTPConfig{
+		Host: server.host,
+		Port: server.port,
+		From: "zikrr@example.com",
+		To:   []string{"ops@example.com", "dev@example.com"},
+	}
+	if err := SendEmail(cfg, testSummary()); err != nil {
+		t.Fatalf("SendEmail() = %v", err)
+	}
+
+	msg := <-server.messages
+	if msg.from != cfg.From || strings.Join(msg.to, ",") != strings.Join(cfg.To, ",") {
+		t.Errorf("envelope = %s -> %v, want %s -> %v", msg.from, msg.to, cfg.From, cfg.To)
+	}
+	for _, want := range []string{
+		"Subject: zikrr: 2 succeeded, 1 failed\r\n",
+		"To: ops@example.com, dev@example.com\r\n",
+		"Succeeded: 2\r\n",
+		"  - acme/broken: repository not found\r\n",
+	} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("message missing %q:\n%s", want, msg.data)
+		}
+	}
+}
+
+func TestSendEmailErrors(t *testing.T) {
+	rejecting := newFakeSMTP(t, true)
+	tests := []struct {
+		name string
+		cfg  config.SMTPConfig
+		want string
+	}{
+		{"missing host", config.SMTPConfig{From: "a@example.com", To: []string{"b@example.com"}}, "requires host, from and to"},
+		{"missing recipients", config.SMTPConfig{Host: "localhost", From: "a@example.com"}, "requires host, from and to"},
+		{"rejected recipient", config.SMTPConfig{Host: rejecting.host, Port: rejecting.port, From: "a@example.com", To: []string{"b@example.com"}}, "failed to send summary email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SendEmail(tt.cfg, testSummary())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SendEmail() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -14,12 +14,18 @@ var log zerolog.Logger
 
 // InitLogger initializes the global logger with the specified configuration
 func InitLogger(level string, format string, output string) error {
+	return InitLoggerWithRotation(level, format, output, RotateOptions{})
+}
+
+// InitLoggerWithRotation initializes the global logger like InitLogger,
+// rotating a log file given as output according to rotate
+func InitLoggerWithRotation(level string, format string, output string, rotate RotateOptions) error {
 	// Set up output writer
 	var w io.Writer = os.Stdout
 	if output != "" {
-		file, err := os.OpenFile(output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		file, err := newRotatingWriter(output, rotate)
 		if err != nil {
-			return fmt.Errorf("failed to open log file: %w", err)
+			return err
 		}
 		if format == "text" {
 			w = zerolog.MultiLevelWriter(os.Stdout, file)
@@ -27,7 +33,12 @@ func InitLogger(level string, format string, output string) error {
 			w = file
 		}
 	}
+	return InitLoggerWithWriter(level, format, w)
+}
 
+// InitLoggerWithWriter initializes the global logger to write to w, in the
+// console format for "text" and as JSON otherwise
+func InitLoggerWithWriter(level string, format string, w io.Writer) error {
 	// Configure logger format
 	if format == "text" {
 		w = zerolog.ConsoleWriter{
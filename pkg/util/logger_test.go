@@ -0,0 +1,104 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// captureLogs points the global logger at a buffer for the duration of the test
+func captureLogs(t *testing.T, level, format string) *bytes.Buffer {
+	t.Helper()
+
+	saved := log
+	t.Cleanup(func() { log = saved })
+	var buf bytes.Buffer
+	if err := InitLoggerWithWriter(level, format, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestInitLoggerWithWriterJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		log   func()
+		// want are the level and message of the expected lines, in order
+		want [][2]string
+	}{
+		{"info drops debug", "info", func() { Debug("hidden"); Info("shown") }, [][2]string{{"info", "shown"}}},
+		{"debug keeps all", "debug", func() { Debug("d"); Warn("w") }, [][2]string{{"debug", "d"}, {"warn", "w"}}},
+		{"case insensitive level", "WARN", func() { Info("hidden"); Warn("w") }, [][2]string{{"warn", "w"}}},
+		{"errors", "error", func() { Warn("hidden"); Error("failed", errors.New("boom")) }, [][2]string{{"error", "failed"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureLogs(t, tt.level, "json")
+			tt.log()
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tt.want), buf.String())
+			}
+			for i, line := range lines {
+				var event map[string]any
+				if err := json.Unmarshal([]byte(line), &event); err != nil {
+					t.Fatalf("line %d is not JSON: %v\n%s", i, err, line)
+				}
+				if event["level"] != tt.want[i][0] || event["message"] != tt.want[i][1] {
+					t.Errorf("line %d = %s, want level %q message %q", i, line, tt.want[i][0], tt.want[i][1])
+				}
+				if _, ok := event["time"]; !ok {
+					t.Errorf("line %d has no time: %s", i, line)
+				}
+			}
+		})
+	}
+}
+
+func TestInitLoggerWithWriterFields(t *testing.T) {
+	buf := captureLogs(t, "info", "json")
+	logger := WithFields(map[string]interface{}{"repo": "acme/app", "attempt": 2})
+	logger.Info().Msg("cloned")
+	Error("failed", errors.New("exit status 128"))
+
+	var events []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var event map[string]any
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line is not JSON: %v\n%s", err, line)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0]["repo"] != "acme/app" || events[0]["attempt"] != float64(2) {
+		t.Errorf("fields event = %v, want repo and attempt", events[0])
+	}
+	if events[1]["error"] != "exit status 128" {
+		t.Errorf("error event = %v, want the error field", events[1])
+	}
+}
+
+func TestInitLoggerWithWriterText(t *testing.T) {
+	buf := captureLogs(t, "info", "text")
+	Info("cloned acme/app")
+	if out := buf.String(); !strings.Contains(out, "INF") || !strings.Contains(out, "cloned acme/app") {
+		t.Errorf("text output = %q, want a console line", out)
+	}
+	if strings.HasPrefix(strings.TrimSpace(buf.String()), "{") {
+		t.Errorf("text output is JSON: %q", buf.String())
+	}
+}
+
+func TestInitLoggerWithWriterInvalidLevel(t *testing.T) {
+	saved := log
+	t.Cleanup(func() { log = saved })
+	if err := InitLoggerWithWriter("loud", "json", &bytes.Buffer{}); err == nil {
+		t.Error("InitLoggerWithWriter() accepted an invalid level")
+	}
+}
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated log files; it sorts chronologically
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions controls rotation of the log file. Zero values disable the
+// corresponding limit.
+type RotateOptions struct {
+	MaxSizeMB  int // rotate once the file would exceed this size
+	MaxBackups int // rotated files kept
+	MaxAgeDays int // rotated files older than this are removed
+}
+
+// rotatingWriter appends to a log file, moving it aside to a timestamped
+// backup when it would grow past the size limit
+type rotatingWriter struct {
+	mu   sync.Mutex
+	path string
+	opts RotateOptions
+	file *os.File
+	size int64
+	now  func() time.Time
+}
+
+// newRotatingWriter opens path for appending with the given rotation limits
+func newRotatingWriter(path string, opts RotateOptions) (*rotatingWriter, error) {
+	w := &rotatingWriter{path: path, opts: opts, now: time.Now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open opens the log file, continuing from its current size
+func (w *rotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if it would push the file past MaxSizeMB.
+// When the rotation fails, p is still written and the rotation error is
+// returned; the next attempt waits until another MaxSizeMB has been written.
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	limit := int64(w.opts.MaxSizeMB) * 1024 * 1024
+	var rotateErr error
+	if limit > 0 && w.size > 0 && w.size+int64(len(p)) > limit {
+		if rotateErr = w.rotate(); rotateErr != nil {
+			w.size = 0
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, errors.Join(rotateErr, err)
+}
+
+// rotate moves the current file to a timestamped backup, reopens the log
+// file and removes backups beyond the retention limits. When the file cannot be
+// moved, logging continues in it.
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	ext := filepath.Ext(w.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(w.path, ext), w.now().Format(backupTimeFormat), ext)
+	if err := os.Rename(w.path, backup); err != nil {
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	return w.prune()
+}
+
+// backups lists the rotated files of the log file. Only names carrying a
+// backupTimeFormat timestamp count, so e.g. zikrr-audit.log next to zikrr.log is left alone.
+func (w *rotatingWriter) backups() ([]string, error) {
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(w.path, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list log backups: %w", err)
+	}
+
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	return backups, nil
+}
+
+// prune removes rotated files beyond MaxBackups or older than MaxAgeDays
+func (w *rotatingWriter) prune() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+	// Newest first; the timestamp format sorts chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	cutoff := w.now().AddDate(0, 0, -w.opts.MaxAgeDays)
+	for i, backup := range backups {
+		expired := w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups
+		if !expired && w.opts.MaxAgeDays > 0 {
+			if info, err := os.Stat(backup); err == nil && info.ModTime().Before(cutoff) {
+				expired = true
+			}
+		}
+		if expired {
+			if err := os.Remove(backup); err != nil {
+				return fmt.Errorf("failed to remove log backup: %w", err)
+			}
+		}
+	}
+	return nil
+}
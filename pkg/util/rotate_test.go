@@ -0,0 +1,155 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testWriter opens a rotating writer on dir/zikrr.log whose clock advances a
+// second per call
+func testWriter(t *testing.T, dir string, opts RotateOptions) *rotatingWriter {
+	t.Helper()
+
+	w, err := newRotatingWriter(filepath.Join(dir, "zikrr.log"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.file.Close() })
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	w.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return w
+}
+
+// listDir returns the sorted file names in dir
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingWriterRollsOver(t *testing.T) {
+	dir := t.TempDir()
+	w := testWriter(t, dir, RotateOptions{MaxSizeMB: 1})
+
+	chunk := []byte(strings.Repeat("x", 600*1024))
+	for i := 0; i < 2; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := listDir(t, dir)
+	want := []string{"zikrr-2026-01-02T03-04-06.000.log", "zikrr.log"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+	info, err := os.Stat(filepath.Join(dir, "zikrr.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(chunk)) {
+		t.Errorf("current log has %d bytes, want %d", info.Size(), len(chunk))
+	}
+}
+
+func TestRotatingWriterPrunesOnlyBackups(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RotateOptions
+		want []string
+	}{
+		{
+			name: "max backups",
+			opts: RotateOptions{MaxSizeMB: 1, MaxBackups: 1},
+			want: []string{"zikrr-2026-01-02T03-04-08.000.log", "zikrr-audit.log", "zikrr-old.log", "zikrr.log"},
+		},
+		{
+			name: "max age",
+			opts: RotateOptions{MaxSizeMB: 1, MaxAgeDays: 7},
+			want: []string{"zikrr-2026-01-02T03-04-06.000.log", "zikrr-2026-01-02T03-04-08.000.log", "zikrr-audit.log", "zikrr-old.log", "zikrr.log"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			old := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+			for _, name := range []string{"zikrr-audit.log", "zikrr-old.log", "zikrr-2025-01-01T00-00-00.000.log"} {
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, []byte("other"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				os.Chtimes(path, old, old)
+			}
+
+			w := testWriter(t, dir, tt.opts)
+			chunk := []byte(strings.Repeat("x", 600*1024))
+			for i := 0; i < 3; i++ {
+				if _, err := w.Write(chunk); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := listDir(t, dir); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotatingWriterKeepsWritingWhenRenameFails(t *testing.T) {
+	dir := t.TempDir()
+	w := testWriter(t, dir, RotateOptions{MaxSizeMB: 1})
+
+	// A directory in the way of the first backup makes the rename fail
+	blocker := filepath.Join(dir, "zikrr-2026-01-02T03-04-06.000.log")
+	if err := os.MkdirAll(filepath.Join(blocker, "keep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	chunk := []byte(strings.Repeat("x", 600*1024))
+	if _, err := w.Write(chunk); err != nil {
+		t.Fatal(err)
+	}
+	n, err := w.Write(chunk)
+	if err == nil {
+		t.Fatal("Write() reported no error although the rotation failed")
+	}
+	if n != len(chunk) {
+		t.Errorf("Write() wrote %d bytes during the failed rotation, want %d", n, len(chunk))
+	}
+	// Rotation is not retried on every line once it has failed
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write() after a failed rotation: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "zikrr.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 2*len(chunk) + len("after\n"); len(data) != want {
+		t.Errorf("log file has %d bytes, want %d", len(data), want)
+	}
+	if !strings.HasSuffix(string(data), "after\n") {
+		t.Errorf("log file does not end with the write after the failed rotation")
+	}
+	names := listDir(t, dir)
+	if want := []string{"zikrr-2026-01-02T03-04-06.000.log", "zikrr.log"}; strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,37 @@
+package util
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{"equal", "0.1.0", "0.1.0", 0, false},
+		{"v prefix", "0.1.0", "v0.1.0", 0, false},
+		{"newer patch available", "0.1.0", "v0.1.1", -1, false},
+		{"newer minor available", "0.1.9", "0.2.0", -1, false},
+		{"newer major available", "0.9.9", "1.0.0", -1, false},
+		{"running newer", "1.2.0", "v1.1.9", 1, false},
+		{"numeric not lexical", "0.10.0", "0.9.0", 1, false},
+		{"missing components", "1", "1.0.0", 0, false},
+		{"pre-release ignored", "1.0.0-rc1", "1.0.0", 0, false},
+		{"build metadata ignored", "1.0.0+abc", "1.0.1", -1, false},
+		{"invalid current", "dev", "1.0.0", 0, true},
+		{"invalid latest", "1.0.0", "", 0, true},
+		{"too many components", "1.0.0", "1.0.0.1", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompareVersions(%q, %q) error = %v, wantErr %v", tt.a, tt.b, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}